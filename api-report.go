@@ -0,0 +1,315 @@
+// Report JSON API
+//
+// GET /api/report returns the data behind the daily report as JSON, for tools
+// such as dashboards that shouldn't scrape Slack. Callers authenticate with
+// REPORT_API_TOKEN ("Authorization: Bearer <token>" or X-Report-Token).
+//
+// Query parameters (all optional):
+//
+//	mode       - qa (default) or inprogress
+//	project    - only issues of this project (within the report's query)
+//	fixVersion - only issues with this fix version
+//	person     - only this person's issues (case-insensitive)
+//
+// Snapshots are cached for API_CACHE_TTL seconds (default 60) per parameter set.
+// Expired snapshots are dropped whenever one is stored, and at most
+// maxSnapshotCacheEntries are kept, so arbitrary parameters can't grow the cache.
+// Concurrent requests for the same parameters share one build. Responses carry an ETag over their content, so pollers sending If-None-Match get
+// 304 Not Modified while nothing changed.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReportSnapshot is the report data returned by /api/report.
+type ReportSnapshot struct {
+	GeneratedAt time.Time        `json:"generated_at"`
+	Mode        reportMode       `json:"mode"`
+	JQL         string           `json:"jql"`
+	People      []snapshotPerson `json:"people"`
+	Stats       snapshotStats    `json:"stats"`
+}
+
+// snapshotPerson is one person's issues, grouped by status in report order.
+type snapshotPerson struct {
+	Person   string           `json:"person"`
+	Total    int              `json:"total"`
+	Statuses []snapshotStatus `json:"statuses"`
+}
+
+// snapshotStatus holds the issues of one status.
+type snapshotStatus struct {
+	Status string          `json:"status"`
+	Issues []snapshotIssue `json:"issues"`
+}
+
+// snapshotIssue is the API representation of an IssueItem.
+type snapshotIssue struct {
+	Key       string   `json:"key"`
+	Summary   string   `json:"summary"`
+	IssueType string   `json:"issue_type"`
+	PRs       []string `json:"prs"`
+	Severity  string   `json:"severity,omitempty"`
+	EpicKey   string   `json:"epic_key,omitempty"`
+	Watchers  int      `json:"watchers,omitempty"`
+}
+
+// snapshotStats reports how many issues the report filters removed, and why
+// (see filterMetrics).
+type snapshotStats struct {
+	Fetched  int            `json:"fetched"`
+	Included int            `json:"included"`
+	Excluded int            `json:"excluded"`
+	ByReason map[string]int `json:"by_reason"`
+	ByRule   map[string]int `json:"by_rule"`
+}
+
+// cachedSnapshot is a rendered snapshot with its ETag.
+type cachedSnapshot struct {
+	body    []byte
+	etag    string
+	expires time.Time
+}
+
+// maxSnapshotCacheEntries caps the number of cached parameter sets.
+const maxSnapshotCacheEntries = 100
+
+// snapshotBuild is a snapshot being built, shared by the requests waiting for it.
+type snapshotBuild struct {
+	done   chan struct{} // Closed once result and err are set
+	result cachedSnapshot
+	err    error
+}
+
+// snapshotCache holds rendered snapshots and the builds in progress by query parameters.
+var snapshotCache = struct {
+	sync.Mutex
+	entries map[string]cachedSnapshot
+	pending map[string]*snapshotBuild
+}{entries: make(map[string]cachedSnapshot), pending: make(map[string]*snapshotBuild)}
+
+// snapshotQuery holds the /api/report parameters.
+type snapshotQuery struct {
+	mode       reportMode
+	project    string
+	fixVersion string
+	person     string
+}
+
+// handleAPIReport serves the report snapshot as JSON
+func handleAPIReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	modeValue := params.Get("mode")
+	if modeValue == "" {
+		modeValue = string(modeQA)
+	}
+	mode, err := parseReportMode(modeValue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	query := snapshotQuery{
+		mode:       mode,
+		project:    strings.TrimSpace(params.Get("project")),
+		fixVersion: strings.TrimSpace(params.Get("fixVersion")),
+		person:     strings.TrimSpace(params.Get("person")),
+	}
+
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	if jiraURL == "" || jiraToken == "" {
+		http.Error(w, "Configuration error: JIRA_URL or JIRA_TOKEN not set", http.StatusInternalServerError)
+		return
+	}
+
+	cached, err := cachedReportSnapshot(jiraURL, jiraToken, query)
+	if err != nil {
+		logf("❌ Failed to build report snapshot: %v\n", err)
+		http.Error(w, "Failed to fetch JIRA issues", http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("ETag", cached.etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if r.Header.Get("If-None-Match") == cached.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(cached.body)
+}
+
+// cachedReportSnapshot returns the rendered snapshot for the query, building it if
+// the cached one is missing or expired. A build already running for the same
+// query is waited for instead of starting another.
+func cachedReportSnapshot(jiraURL, jiraToken string, query snapshotQuery) (cachedSnapshot, error) {
+	key := fmt.Sprintf("%s|%s|%s|%s", query.mode, query.project, query.fixVersion, strings.ToLower(query.person))
+
+	snapshotCache.Lock()
+	if cached, ok := snapshotCache.entries[key]; ok && time.Now().Before(cached.expires) {
+		snapshotCache.Unlock()
+		return cached, nil
+	}
+	if build, ok := snapshotCache.pending[key]; ok {
+		snapshotCache.Unlock()
+		<-build.done
+		return build.result, build.err
+	}
+	build := &snapshotBuild{done: make(chan struct{})}
+	snapshotCache.pending[key] = build
+	snapshotCache.Unlock()
+
+	build.result, build.err = renderReportSnapshot(jiraURL, jiraToken, query)
+
+	snapshotCache.Lock()
+	delete(snapshotCache.pending, key)
+	if build.err == nil {
+		storeSnapshot(key, build.result)
+	}
+	snapshotCache.Unlock()
+	close(build.done)
+
+	return build.result, build.err
+}
+
+// storeSnapshot caches the snapshot, dropping expired entries and then the ones
+// closest to expiry while the cache is full. Callers hold snapshotCache's lock.
+func storeSnapshot(key string, cached cachedSnapshot) {
+	now := time.Now()
+	for k, entry := range snapshotCache.entries {
+		if !now.Before(entry.expires) {
+			delete(snapshotCache.entries, k)
+		}
+	}
+	delete(snapshotCache.entries, key)
+	for len(snapshotCache.entries) >= maxSnapshotCacheEntries {
+		var oldest string
+		for k, entry := range snapshotCache.entries {
+			if oldest == "" || entry.expires.Before(snapshotCache.entries[oldest].expires) {
+				oldest = k
+			}
+		}
+		delete(snapshotCache.entries, oldest)
+	}
+	snapshotCache.entries[key] = cached
+}
+
+// renderReportSnapshot builds the snapshot for the query and renders it with its ETag.
+func renderReportSnapshot(jiraURL, jiraToken string, query snapshotQuery) (cachedSnapshot, error) {
+	snapshot, err := buildReportSnapshot(jiraURL, jiraToken, query)
+	if err != nil {
+		return cachedSnapshot{}, err
+	}
+
+	body, err := json.Marshal(snapshot)
+	if err != nil {
+		return cachedSnapshot{}, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	// The ETag covers the content only, so a rebuild with unchanged data keeps it
+	snapshot.GeneratedAt = time.Time{}
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return cachedSnapshot{}, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+	sum := sha256.Sum256(content)
+
+	return cachedSnapshot{
+		body:    body,
+		etag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		expires: time.Now().Add(time.Duration(envInt("API_CACHE_TTL", 60)) * time.Second),
+	}, nil
+}
+
+// buildReportSnapshot fetches and groups the report's issues for the query.
+func buildReportSnapshot(jiraURL, jiraToken string, query snapshotQuery) (ReportSnapshot, error) {
+	jql := snapshotJQL(reportQuery(query.mode, jiraURL, jiraToken), query)
+
+	grouper := newPersonGrouper(query.mode)
+	_, err := streamJiraIssues(jiraURL, jiraToken, jql, reportFetchOptions(query.mode), func(page JiraSearchResponse) error {
+		grouper.add(page)
+		return nil
+	})
+	if err != nil {
+		return ReportSnapshot{}, err
+	}
+
+	groups := grouper.groups()
+	if query.person != "" {
+		var matching []PersonStatusGroup
+		for _, group := range groups {
+			if strings.EqualFold(group.Person, query.person) {
+				matching = append(matching, group)
+			}
+		}
+		groups = matching
+	}
+	if envBool("SHOW_EPIC", false) {
+		resolveEpicSummaries(jiraURL, jiraToken, groups)
+	}
+
+	metrics := grouper.filterMetrics()
+	snapshot := ReportSnapshot{
+		GeneratedAt: time.Now().UTC(),
+		Mode:        query.mode,
+		JQL:         jql,
+		People:      []snapshotPerson{},
+		Stats: snapshotStats{
+			Fetched:  metrics.Fetched,
+			Included: metrics.Included,
+			Excluded: metrics.Excluded,
+			ByReason: metrics.ByReason,
+			ByRule:   metrics.ByRule,
+		},
+	}
+	for _, group := range groups {
+		person := snapshotPerson{Person: group.Person, Total: group.TotalIssues}
+		for _, status := range orderedStatuses([]PersonStatusGroup{group}) {
+			var issues []snapshotIssue
+			for _, issue := range group.StatusGroups[status] {
+				// Issues without PRs have an empty list rather than null
+				prs := append([]string{}, issue.GitPullRequest...)
+				issues = append(issues, snapshotIssue{
+					Key:       issue.Key,
+					Summary:   issue.Summary,
+					IssueType: issue.IssueType,
+					PRs:       prs,
+					Severity:  issue.Severity,
+					EpicKey:   issue.EpicKey,
+					Watchers:  issue.Watchers,
+				})
+			}
+			person.Statuses = append(person.Statuses, snapshotStatus{Status: status, Issues: issues})
+		}
+		snapshot.People = append(snapshot.People, person)
+	}
+
+	return snapshot, nil
+}
+
+// snapshotJQL narrows the report query by the project and fixVersion parameters.
+func snapshotJQL(jql string, query snapshotQuery) string {
+	var conditions []string
+	if query.project != "" {
+		conditions = append(conditions, "project = "+sanitizeJQLValue(query.project))
+	}
+	if query.fixVersion != "" {
+		conditions = append(conditions, "fixVersion = "+sanitizeJQLValue(query.fixVersion))
+	}
+	return addJQLConditions(jql, conditions...)
+}
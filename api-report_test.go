@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// resetSnapshotCache empties the snapshot cache for the test.
+func resetSnapshotCache(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		snapshotCache.Lock()
+		snapshotCache.entries = make(map[string]cachedSnapshot)
+		snapshotCache.pending = make(map[string]*snapshotBuild)
+		snapshotCache.Unlock()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestReportSnapshotStatsAndPRs(t *testing.T) {
+	t.Setenv("JIRA_JQL", "project = MTV")
+	server, _ := jiraSearchStub(t, []JiraSearchResponse{{
+		Issues: []JiraIssue{
+			parseTestIssue(t, `{"key": "MTV-1", "fields": {"summary": "No PR yet", "status": {"name": "POST"}, "issuetype": {"name": "Bug"}, "assignee": {"displayName": "Ann"}}}`),
+			parseTestIssue(t, `{"key": "MTV-2", "fields": {"summary": "Has a PR", "status": {"name": "POST"}, "issuetype": {"name": "Bug"}, "assignee": {"displayName": "Ann"}, "customfield_12310220": ["https://github.com/x/y/pull/1"]}}`),
+			parseTestIssue(t, `{"key": "MTV-3", "fields": {"summary": "Empty epic", "status": {"name": "New"}, "issuetype": {"name": "Epic"}, "assignee": {"displayName": "Ann"}}}`),
+		},
+	}})
+
+	snapshot, err := buildReportSnapshot(server.URL, "token", snapshotQuery{mode: modeQA})
+	if err != nil {
+		t.Fatalf("buildReportSnapshot: %v", err)
+	}
+
+	stats := snapshot.Stats
+	if stats.Fetched != 3 || stats.Included != 2 || stats.Excluded != 1 {
+		t.Errorf("stats = %+v, want 3 fetched, 2 included, 1 excluded", stats)
+	}
+	if stats.ByRule["epic-without-PR"] != 1 || len(stats.ByReason) != 1 {
+		t.Errorf("by_reason = %v, by_rule = %v, want the epic rule", stats.ByReason, stats.ByRule)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"prs":[]`) || strings.Contains(string(data), `"prs":null`) {
+		t.Errorf("issue without PRs isn't an empty list: %s", data)
+	}
+}
+
+func TestCachedReportSnapshotSharesConcurrentBuilds(t *testing.T) {
+	resetSnapshotCache(t)
+	t.Setenv("JIRA_JQL", "project = MTV")
+
+	var searches atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		searches.Add(1)
+		<-release
+		json.NewEncoder(w).Encode(JiraSearchResponse{Issues: stubIssues(1, 2)})
+	}))
+	t.Cleanup(server.Close)
+
+	const requests = 5
+	var wg sync.WaitGroup
+	etags := make([]string, requests)
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			cached, err := cachedReportSnapshot(server.URL, "token", snapshotQuery{mode: modeQA})
+			if err != nil {
+				t.Errorf("request %d: %v", i, err)
+			}
+			etags[i] = cached.etag
+		}(i)
+	}
+
+	// Let every request reach the cache before JIRA answers
+	for deadline := time.Now().Add(2 * time.Second); searches.Load() == 0 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := searches.Load(); n != 1 {
+		t.Errorf("%d JIRA searches for %d concurrent requests, want 1", n, requests)
+	}
+	for i, etag := range etags {
+		if etag == "" || etag != etags[0] {
+			t.Errorf("request %d got ETag %q, want %q", i, etag, etags[0])
+		}
+	}
+	if len(snapshotCache.pending) != 0 {
+		t.Errorf("build still pending after it finished")
+	}
+}
+
+func TestStoreSnapshotEvicts(t *testing.T) {
+	resetSnapshotCache(t)
+	now := time.Now()
+
+	snapshotCache.Lock()
+	defer snapshotCache.Unlock()
+	snapshotCache.entries["expired"] = cachedSnapshot{expires: now.Add(-time.Second)}
+	for i := 0; i < maxSnapshotCacheEntries-1; i++ {
+		snapshotCache.entries[fmt.Sprint("live", i)] = cachedSnapshot{expires: now.Add(time.Duration(i+1) * time.Minute)}
+	}
+
+	storeSnapshot("new", cachedSnapshot{expires: now.Add(time.Hour)})
+	if _, ok := snapshotCache.entries["expired"]; ok {
+		t.Error("expired entry kept")
+	}
+	if len(snapshotCache.entries) != maxSnapshotCacheEntries {
+		t.Errorf("%d entries, want %d", len(snapshotCache.entries), maxSnapshotCacheEntries)
+	}
+
+	storeSnapshot("newer", cachedSnapshot{expires: now.Add(time.Hour)})
+	if len(snapshotCache.entries) != maxSnapshotCacheEntries {
+		t.Errorf("%d entries after a full cache, want %d", len(snapshotCache.entries), maxSnapshotCacheEntries)
+	}
+	if _, ok := snapshotCache.entries["live0"]; ok {
+		t.Error("entry closest to expiry kept on a full cache")
+	}
+	if _, ok := snapshotCache.entries["newer"]; !ok {
+		t.Error("new entry not stored")
+	}
+}
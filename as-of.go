@@ -0,0 +1,271 @@
+// Historical reports
+//
+// -as-of=2024-05-02T06:00 generates the report as it looked at that moment, e.g.
+// to recover a failed morning run after statuses have moved on. The query matches
+// issues that were in the report's statuses at the time (JQL "WAS ... ON"), and
+// each issue's status, assignee, QA contact, priority, labels and components are
+// rebuilt by replaying its changelog backwards from now to the timestamp. Issues
+// created after the timestamp are dropped.
+//
+// A custom JIRA_JQL can't be rewritten this way and is used as-is, so issues that
+// have since left its result set are missing from the historical report.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// asOfTime is the moment a historical report is generated for (set by -as-of).
+// Zero means the report reflects the current state.
+var asOfTime time.Time
+
+// asOfLayouts are the accepted -as-of formats. Times without an offset are local.
+var asOfLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"}
+
+// changelogPageSize is how many histories are requested per changelog page.
+const changelogPageSize = 100
+
+// jiraUserRef matches the shape of the user fields on JiraIssue.
+type jiraUserRef = struct {
+	DisplayName string `json:"displayName"`
+	AccountID   string `json:"accountId"`
+	Name        string `json:"name"` // Username on JIRA Server, for profile links
+	TimeZone    string `json:"timeZone"`
+	// EmailAddress is only returned when the token may see it
+	EmailAddress string `json:"emailAddress"`
+}
+
+// reportNow returns the moment the report describes: the -as-of time, or now.
+func reportNow() time.Time {
+	if !asOfTime.IsZero() {
+		return asOfTime
+	}
+	return time.Now()
+}
+
+// parseAsOf validates the -as-of flag value.
+func parseAsOf(value string) (time.Time, error) {
+	for _, layout := range asOfLayouts {
+		t, err := time.ParseInLocation(layout, value, time.Local)
+		if err != nil {
+			continue
+		}
+		if t.After(time.Now()) {
+			return time.Time{}, fmt.Errorf("-as-of %q is in the future", value)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid -as-of %q (expected e.g. 2024-05-02T06:00)", value)
+}
+
+// asOfQuery returns the JQL and fetch options for a historical report at asOf.
+func asOfQuery(mode reportMode, asOf time.Time, jiraURL, jiraToken string) (string, fetchOptions) {
+	opts := reportFetchOptions(mode)
+	opts.Expand = "changelog"
+	if !containsString(opts.ExtraFields, "created") {
+		opts.ExtraFields = append(opts.ExtraFields, "created")
+	}
+
+	if mode == modeQA && strings.TrimSpace(os.Getenv("JIRA_JQL")) != "" {
+		logln("⚠️  JIRA_JQL is set: using it as-is, issues that have since left its results won't be included")
+		return reportJQL(mode), opts
+	}
+
+	loc, err := fetchJiraServerLocation(jiraURL, jiraToken)
+	if err != nil {
+		logf("⚠️  Failed to get JIRA server timezone, using UTC: %v\n", err)
+		loc = time.UTC
+	}
+	at := formatJQLTime(asOf, loc)
+	logf("   Reconstructing state as of %s (JIRA server time)\n", at)
+
+	if mode == modeInProgress {
+		return fmt.Sprintf(`project = MTV AND created <= "%s" AND status WAS "In Progress" ON "%s" ORDER BY assignee`, at, at), opts
+	}
+	return fmt.Sprintf(`project = MTV AND created <= "%s" AND (status WAS IN (POST, ON_QA, MODIFIED) ON "%s" OR (type = Epic AND status WAS NOT Closed ON "%s")) ORDER BY assignee`, at, at, at), opts
+}
+
+// reconstructPage rewinds every issue on the page to asOf and drops the ones that
+// didn't exist yet or weren't part of the report at the time.
+func reconstructPage(jiraURL, jiraToken string, page *JiraSearchResponse, mode reportMode, asOf time.Time) {
+	kept := page.Issues[:0]
+	for _, issue := range page.Issues {
+		if err := completeChangelog(jiraURL, jiraToken, &issue); err != nil {
+			logf("⚠️  Incomplete changelog for %s, its history may be inaccurate: %v\n", issue.Key, err)
+		}
+		if !reconstructIssue(&issue, asOf) || !inHistoricalReport(issue, mode) {
+			continue
+		}
+		kept = append(kept, issue)
+	}
+	page.Issues = kept
+}
+
+// inHistoricalReport reports whether the reconstructed issue belongs in the mode's report.
+// Custom JQL can't be evaluated against the past, so its results are all kept.
+func inHistoricalReport(issue JiraIssue, mode reportMode) bool {
+	status := issue.Fields.Status.Name
+	if mode == modeInProgress {
+		return status == "In Progress"
+	}
+	if strings.TrimSpace(os.Getenv("JIRA_JQL")) != "" {
+		return true
+	}
+	if issue.Fields.IssueType.Name == "Epic" {
+		return status != "Closed"
+	}
+	return status == "POST" || status == "ON_QA" || status == "MODIFIED"
+}
+
+// reconstructIssue rewinds the issue's fields to their values at asOf by undoing
+// every changelog entry made after it, newest first. Later entries are removed from
+// the changelog so ages are computed from the historical state too.
+// Returns false if the issue was created after asOf.
+func reconstructIssue(issue *JiraIssue, asOf time.Time) bool {
+	if created, err := time.Parse(jiraTimeLayout, issue.Fields.Created); err == nil && created.After(asOf) {
+		return false
+	}
+	if issue.Changelog == nil {
+		return true
+	}
+
+	type datedHistory struct {
+		at      time.Time
+		history JiraChangeHistory
+	}
+	var later []datedHistory
+	var kept []JiraChangeHistory
+	for _, history := range issue.Changelog.Histories {
+		at, err := time.Parse(jiraTimeLayout, history.Created)
+		if err != nil || !at.After(asOf) {
+			kept = append(kept, history)
+			continue
+		}
+		later = append(later, datedHistory{at, history})
+	}
+
+	sort.SliceStable(later, func(i, j int) bool {
+		return later[i].at.After(later[j].at)
+	})
+	for _, entry := range later {
+		items := entry.history.Items
+		for i := len(items) - 1; i >= 0; i-- {
+			undoChange(issue, items[i])
+		}
+	}
+
+	issue.Changelog.Histories = kept
+	return true
+}
+
+// undoChange restores the value a field had before the changelog item.
+func undoChange(issue *JiraIssue, item JiraChangeItem) {
+	switch {
+	case item.Field == "status":
+		issue.Fields.Status.Name = canonicalStatus(item.FromString)
+	case item.Field == "assignee":
+		issue.Fields.Assignee = userRef(item.FromString)
+	case item.FieldID == qaContactFieldID || item.Field == "QA Contact":
+		issue.Fields.QAContact = userRef(item.FromString)
+	case item.Field == "priority":
+		if item.FromString == "" {
+			issue.Fields.Priority = nil
+		} else {
+			issue.Fields.Priority = &struct {
+				Name string `json:"name"`
+			}{Name: item.FromString}
+		}
+	case item.Field == "labels":
+		issue.Fields.Labels = strings.Fields(item.FromString)
+	case item.Field == "Component":
+		// Each component change is its own item: an add has only a "to", a removal only a "from"
+		if item.ToString != "" {
+			components := issue.Fields.Components[:0]
+			for _, component := range issue.Fields.Components {
+				if component.Name != item.ToString {
+					components = append(components, component)
+				}
+			}
+			issue.Fields.Components = components
+		}
+		if item.FromString != "" {
+			issue.Fields.Components = append(issue.Fields.Components, struct {
+				Name string `json:"name"`
+			}{Name: item.FromString})
+		}
+	}
+}
+
+// userRef returns a user field value for a display name, or nil for "unassigned".
+func userRef(displayName string) *jiraUserRef {
+	displayName = strings.TrimSpace(displayName)
+	if displayName == "" {
+		return nil
+	}
+	return &jiraUserRef{DisplayName: displayName}
+}
+
+// completeChangelog fetches the full changelog when the search response truncated it.
+func completeChangelog(jiraURL, jiraToken string, issue *JiraIssue) error {
+	if issue.Changelog == nil || issue.Changelog.Total <= len(issue.Changelog.Histories) {
+		return nil
+	}
+
+	var histories []JiraChangeHistory
+	for startAt := 0; ; {
+		url := fmt.Sprintf("%s/rest/api/3/issue/%s/changelog?startAt=%d&maxResults=%d", jiraURL, issue.Key, startAt, changelogPageSize)
+		req, err := http.NewRequest("GET", url, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		setJiraAuth(req, jiraToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to execute request: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode != 200 {
+			return fmt.Errorf("JIRA API returned %d: %s", resp.StatusCode, redactSecrets(string(body)))
+		}
+
+		var page struct {
+			IsLast bool                `json:"isLast"`
+			Values []JiraChangeHistory `json:"values"`
+		}
+		if err := json.Unmarshal(body, &page); err != nil {
+			return fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+
+		histories = append(histories, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+		startAt += len(page.Values)
+	}
+
+	issue.Changelog.Histories = histories
+	issue.Changelog.Total = len(histories)
+	return nil
+}
+
+// containsString reports whether list contains value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
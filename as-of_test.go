@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// parseTestIssue decodes an issue from its JSON search result form.
+func parseTestIssue(t *testing.T, data string) JiraIssue {
+	t.Helper()
+	var issue JiraIssue
+	if err := json.Unmarshal([]byte(data), &issue); err != nil {
+		t.Fatalf("decoding issue: %v", err)
+	}
+	return issue
+}
+
+// componentNames lists the issue's component names in order.
+func componentNames(issue JiraIssue) []string {
+	var names []string
+	for _, component := range issue.Fields.Components {
+		names = append(names, component.Name)
+	}
+	return names
+}
+
+// displayName returns the name of a user field, or "" when unset.
+func displayName(user *jiraUserRef) string {
+	if user == nil {
+		return ""
+	}
+	return user.DisplayName
+}
+
+func TestReconstructIssue(t *testing.T) {
+	asOf := time.Date(2024, 5, 2, 6, 0, 0, 0, time.UTC)
+	current := `{
+		"key": "MTV-1",
+		"fields": {
+			"status": {"name": "ON_QA"},
+			"assignee": {"displayName": "Bob"},
+			"customfield_12315948": {"displayName": "Quinn"},
+			"components": [{"name": "UI"}, {"name": "Storage"}],
+			"created": "2024-04-01T10:00:00.000+0000"
+		},
+		"changelog": {"histories": [%s]}
+	}`
+
+	tests := []struct {
+		name           string
+		histories      string
+		created        string
+		wantOK         bool
+		wantStatus     string
+		wantAssignee   string
+		wantQAContact  string
+		wantComponents []string
+		wantHistories  int
+	}{
+		{
+			name:           "changes before asOf are kept",
+			histories:      `{"created": "2024-05-01T09:00:00.000+0000", "items": [{"field": "status", "fromString": "POST", "toString": "ON_QA"}]}`,
+			wantOK:         true,
+			wantStatus:     "ON_QA",
+			wantAssignee:   "Bob",
+			wantQAContact:  "Quinn",
+			wantComponents: []string{"UI", "Storage"},
+			wantHistories:  1,
+		},
+		{
+			name:           "status change after asOf is undone",
+			histories:      `{"created": "2024-05-03T09:00:00.000+0000", "items": [{"field": "status", "fromString": "Post", "toString": "ON_QA"}]}`,
+			wantOK:         true,
+			wantStatus:     "POST",
+			wantAssignee:   "Bob",
+			wantQAContact:  "Quinn",
+			wantComponents: []string{"UI", "Storage"},
+		},
+		{
+			name: "assignee and QA contact changes are undone newest first",
+			histories: `{"created": "2024-05-04T09:00:00.000+0000", "items": [{"field": "assignee", "fromString": "Carol", "toString": "Bob"}]},
+				{"created": "2024-05-03T09:00:00.000+0000", "items": [{"field": "assignee", "fromString": "", "toString": "Carol"}, {"field": "QA Contact", "fieldId": "customfield_12315948", "fromString": "Pat", "toString": "Quinn"}]}`,
+			wantOK:         true,
+			wantStatus:     "ON_QA",
+			wantAssignee:   "",
+			wantQAContact:  "Pat",
+			wantComponents: []string{"UI", "Storage"},
+		},
+		{
+			name:           "added component is removed",
+			histories:      `{"created": "2024-05-03T09:00:00.000+0000", "items": [{"field": "Component", "toString": "Storage"}]}`,
+			wantOK:         true,
+			wantStatus:     "ON_QA",
+			wantAssignee:   "Bob",
+			wantQAContact:  "Quinn",
+			wantComponents: []string{"UI"},
+		},
+		{
+			name:           "removed component is restored",
+			histories:      `{"created": "2024-05-03T09:00:00.000+0000", "items": [{"field": "Component", "fromString": "Networking"}]}`,
+			wantOK:         true,
+			wantStatus:     "ON_QA",
+			wantAssignee:   "Bob",
+			wantQAContact:  "Quinn",
+			wantComponents: []string{"UI", "Storage", "Networking"},
+		},
+		{
+			name:    "issue created after asOf is dropped",
+			created: "2024-05-02T07:00:00.000+0000",
+			wantOK:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := parseTestIssue(t, fmt.Sprintf(current, tt.histories))
+			if tt.created != "" {
+				issue.Fields.Created = tt.created
+			}
+
+			ok := reconstructIssue(&issue, asOf)
+			if ok != tt.wantOK {
+				t.Fatalf("reconstructIssue = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := issue.Fields.Status.Name; got != tt.wantStatus {
+				t.Errorf("status = %q, want %q", got, tt.wantStatus)
+			}
+			if got := displayName(issue.Fields.Assignee); got != tt.wantAssignee {
+				t.Errorf("assignee = %q, want %q", got, tt.wantAssignee)
+			}
+			if got := displayName(issue.Fields.QAContact); got != tt.wantQAContact {
+				t.Errorf("QA contact = %q, want %q", got, tt.wantQAContact)
+			}
+			if got := componentNames(issue); fmt.Sprint(got) != fmt.Sprint(tt.wantComponents) {
+				t.Errorf("components = %q, want %q", got, tt.wantComponents)
+			}
+			if got := len(issue.Changelog.Histories); got != tt.wantHistories {
+				t.Errorf("kept %d histories, want %d", got, tt.wantHistories)
+			}
+		})
+	}
+}
+
+func TestUndoChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		item  JiraChangeItem
+		check func(JiraIssue) string
+		want  string
+	}{
+		{
+			name:  "status is restored canonically",
+			item:  JiraChangeItem{Field: "status", FromString: "In progress", ToString: "POST"},
+			check: func(issue JiraIssue) string { return issue.Fields.Status.Name },
+			want:  "In Progress",
+		},
+		{
+			name:  "assignee is cleared when it was unassigned",
+			item:  JiraChangeItem{Field: "assignee", ToString: "Bob"},
+			check: func(issue JiraIssue) string { return displayName(issue.Fields.Assignee) },
+			want:  "",
+		},
+		{
+			name:  "QA contact is matched by field ID",
+			item:  JiraChangeItem{Field: "QA Contact (renamed)", FieldID: qaContactFieldID, FromString: "Pat", ToString: "Quinn"},
+			check: func(issue JiraIssue) string { return displayName(issue.Fields.QAContact) },
+			want:  "Pat",
+		},
+		{
+			name:  "component add is undone",
+			item:  JiraChangeItem{Field: "Component", ToString: "UI"},
+			check: func(issue JiraIssue) string { return fmt.Sprint(componentNames(issue)) },
+			want:  "[Storage]",
+		},
+		{
+			name:  "component removal is undone",
+			item:  JiraChangeItem{Field: "Component", FromString: "Networking"},
+			check: func(issue JiraIssue) string { return fmt.Sprint(componentNames(issue)) },
+			want:  "[UI Storage Networking]",
+		},
+		{
+			name: "unrelated fields are ignored",
+			item: JiraChangeItem{Field: "Sprint", FromString: "1", ToString: "2"},
+			check: func(issue JiraIssue) string {
+				return issue.Fields.Status.Name + " " + fmt.Sprint(componentNames(issue))
+			},
+			want: "ON_QA [UI Storage]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := parseTestIssue(t, `{"key": "MTV-1", "fields": {
+				"status": {"name": "ON_QA"},
+				"assignee": {"displayName": "Bob"},
+				"customfield_12315948": {"displayName": "Quinn"},
+				"components": [{"name": "UI"}, {"name": "Storage"}]
+			}}`)
+			undoChange(&issue, tt.item)
+			if got := tt.check(issue); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
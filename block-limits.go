@@ -0,0 +1,122 @@
+// Block text limits
+//
+// Slack rejects a whole message with invalid_blocks when one block's text is too
+// long: 3000 characters for section and context text, 150 for headers. The
+// builders whose text comes from JIRA or templates (person sections, overflow,
+// slash command pages, shared results, watch DMs, status totals) pass their blocks
+// through limitBlockText. Overlong text is cut at a line break where possible,
+// ends with "…", and the truncation is logged.
+//
+// Every message is then checked with validateBlocks right before it is sent, on
+// the blocks as built: more than 50 blocks or overlong section and header text is
+// a descriptive error instead of a message the API rejects or that gets cut
+// without notice.
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// slackSectionTextLimit is the maximum length of a section block's text
+	// (and of a context element's text).
+	slackSectionTextLimit = 3000
+
+	// slackHeaderTextLimit is the maximum length of a header block's text.
+	slackHeaderTextLimit = 150
+
+	// slackMessageBlockLimit is the maximum number of blocks in one message.
+	slackMessageBlockLimit = 50
+)
+
+// limitBlockText truncates overlong section, context and header text in place.
+func limitBlockText(blocks []map[string]interface{}) {
+	for i, block := range blocks {
+		switch block["type"] {
+		case "section":
+			limitTextObject(block["text"], slackSectionTextLimit, i)
+		case "header":
+			limitTextObject(block["text"], slackHeaderTextLimit, i)
+		case "context":
+			switch elements := block["elements"].(type) {
+			case []map[string]string:
+				for _, element := range elements {
+					limitTextObject(element, slackSectionTextLimit, i)
+				}
+			case []interface{}:
+				for _, element := range elements {
+					limitTextObject(element, slackSectionTextLimit, i)
+				}
+			}
+		}
+	}
+}
+
+// limitTextObject truncates the "text" of a Slack text object, which is a
+// map[string]string when built here or a map[string]interface{} when decoded.
+func limitTextObject(object interface{}, limit, blockIndex int) {
+	switch fields := object.(type) {
+	case map[string]string:
+		if text, ok := truncateBlockText(fields["text"], limit, blockIndex); ok {
+			fields["text"] = text
+		}
+	case map[string]interface{}:
+		value, _ := fields["text"].(string)
+		if text, ok := truncateBlockText(value, limit, blockIndex); ok {
+			fields["text"] = text
+		}
+	}
+}
+
+// truncateBlockText shortens text to the limit, preferring to cut at a line break
+// in the second half. Returns false if the text already fits.
+func truncateBlockText(text string, limit, blockIndex int) (string, bool) {
+	length := utf8.RuneCountInString(text)
+	if length <= limit {
+		return text, false
+	}
+
+	cut := string([]rune(text)[:limit-1])
+	if i := strings.LastIndex(cut, "\n"); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	logf("   ⚠️  Block %d text is %d characters (limit %d), truncated\n", blockIndex, length, limit)
+	return cut + "…", true
+}
+
+// validateBlocks checks a message's blocks against Slack's block count and text
+// length limits. The error names the first offending block.
+func validateBlocks(blocks []map[string]interface{}) error {
+	if len(blocks) > slackMessageBlockLimit {
+		return fmt.Errorf("message has %d blocks, Slack allows %d", len(blocks), slackMessageBlockLimit)
+	}
+	for i, block := range blocks {
+		limit := 0
+		switch block["type"] {
+		case "section":
+			limit = slackSectionTextLimit
+		case "header":
+			limit = slackHeaderTextLimit
+		default:
+			continue
+		}
+		if length := utf8.RuneCountInString(textObjectText(block["text"])); length > limit {
+			return fmt.Errorf("%s block %d text is %d characters, Slack allows %d", block["type"], i, length, limit)
+		}
+	}
+	return nil
+}
+
+// textObjectText returns the "text" of a Slack text object, built here or decoded.
+func textObjectText(object interface{}) string {
+	switch fields := object.(type) {
+	case map[string]string:
+		return fields["text"]
+	case map[string]interface{}:
+		text, _ := fields["text"].(string)
+		return text
+	}
+	return ""
+}
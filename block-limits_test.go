@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// sectionBlock returns a mrkdwn section block with the text.
+func sectionBlock(text string) map[string]interface{} {
+	return map[string]interface{}{"type": "section", "text": map[string]string{"type": "mrkdwn", "text": text}}
+}
+
+func TestValidateBlocks(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocks  []map[string]interface{}
+		wantErr string
+	}{
+		{name: "valid message", blocks: []map[string]interface{}{sectionBlock("hello"), {"type": "divider"}}},
+		{name: "exactly 50 blocks", blocks: textBlocks(slackMessageBlockLimit)},
+		{name: "too many blocks", blocks: textBlocks(slackMessageBlockLimit + 1), wantErr: "message has 51 blocks"},
+		{
+			name:    "section text too long",
+			blocks:  []map[string]interface{}{sectionBlock("ok"), sectionBlock(strings.Repeat("é", slackSectionTextLimit+1))},
+			wantErr: "section block 1 text is 3001 characters",
+		},
+		{
+			name:    "header text too long",
+			blocks:  []map[string]interface{}{{"type": "header", "text": map[string]string{"type": "plain_text", "text": strings.Repeat("a", slackHeaderTextLimit+1)}}},
+			wantErr: "header block 0 text is 151 characters",
+		},
+		{
+			name:    "decoded section text too long",
+			blocks:  []map[string]interface{}{{"type": "section", "text": map[string]interface{}{"type": "mrkdwn", "text": strings.Repeat("a", slackSectionTextLimit+1)}}},
+			wantErr: "section block 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateBlocks(tt.blocks)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("validateBlocks: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("validateBlocks error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLimitBlockTextPassesValidation(t *testing.T) {
+	blocks := []map[string]interface{}{
+		sectionBlock(strings.Repeat("line of text\n", 400)),
+		{"type": "header", "text": map[string]string{"type": "plain_text", "text": strings.Repeat("a", 200)}},
+	}
+	limitBlockText(blocks)
+	if err := validateBlocks(blocks); err != nil {
+		t.Fatalf("validateBlocks after limitBlockText: %v", err)
+	}
+
+	section := blocks[0]["text"].(map[string]string)["text"]
+	if !strings.HasSuffix(section, "text…") {
+		t.Errorf("section wasn't cut at a line break: %q", section[len(section)-20:])
+	}
+	if n := utf8.RuneCountInString(blocks[1]["text"].(map[string]string)["text"]); n != slackHeaderTextLimit {
+		t.Errorf("header is %d characters, want %d", n, slackHeaderTextLimit)
+	}
+}
+
+func TestSendRejectsOverlongBlocks(t *testing.T) {
+	posts := slackPostStub(t, func(slackPost, int) string { return "" })
+
+	text := strings.Repeat("a", slackSectionTextLimit+1)
+	blocks := []map[string]interface{}{sectionBlock(text)}
+	_, err := sendToSlackAPI("xoxb-test", "C1", "", blocks, envRender)
+	if err == nil || !strings.Contains(err.Error(), "section block 0 text is 3001 characters") {
+		t.Fatalf("sendToSlackAPI error = %v, want the overlong section reported", err)
+	}
+	if len(*posts) != 0 {
+		t.Errorf("posted %d messages, want none", len(*posts))
+	}
+	if got := blocks[0]["text"].(map[string]string)["text"]; got != text {
+		t.Errorf("the rejected block was cut to %d characters", utf8.RuneCountInString(got))
+	}
+}
+
+func TestPersonBlocksFitSlackLimits(t *testing.T) {
+	tmpl, err := parseIssueTemplate(strings.Repeat("long issue line ", 300))
+	if err != nil {
+		t.Fatal(err)
+	}
+	group := PersonStatusGroup{
+		Person:       "Jane Doe",
+		StatusGroups: map[string][]IssueItem{"POST": {{Key: "MTV-1", Summary: "Fix it", Status: "POST"}}},
+		TotalIssues:  1,
+	}
+
+	blocks := buildPersonBlocks(&reportThread{channel: "C1", threadTS: "1.0"}, "https://jira.example.com", group, modeQA, 0, renderOptions{template: tmpl})
+	if err := validateBlocks(blocks); err != nil {
+		t.Fatalf("person blocks exceed Slack's limits: %v", err)
+	}
+}
@@ -0,0 +1,149 @@
+// Configuration helpers
+//
+// Optional features are configured through environment variables. These helpers
+// read them with sensible defaults so call sites stay short.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envBool reads a boolean environment variable ("true", "1", "yes"), returning def when unset or invalid.
+func envBool(name string, def bool) bool {
+	return parseBool(os.Getenv(name), def)
+}
+
+// parseBool parses a boolean setting value, returning def when empty or invalid.
+func parseBool(value string, def bool) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "1", "yes", "on":
+		return true
+	case "false", "0", "no", "off":
+		return false
+	}
+	return def
+}
+
+// envInt reads an integer environment variable, returning def when unset or invalid.
+func envInt(name string, def int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(name)))
+	if err != nil {
+		return def
+	}
+	return value
+}
+
+// envList reads a comma-separated environment variable, trimming whitespace and dropping empty entries.
+func envList(name string) []string {
+	var items []string
+	for _, item := range strings.Split(os.Getenv(name), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// defaultStatusAliases maps spellings of workflow statuses seen on our JIRA
+// instance (e.g. left over from workflow migrations) to the name used in the
+// reports. Keys are lowercase; matching is case-insensitive.
+var defaultStatusAliases = map[string]string{
+	"modified":    "MODIFIED",
+	"post":        "POST",
+	"on_qa":       "ON_QA",
+	"on qa":       "ON_QA",
+	"in progress": "In Progress",
+	"verified":    "Verified",
+	"closed":      "Closed",
+}
+
+// statusAliasTable is the parsed status normalization.
+type statusAliasTable struct {
+	canonical map[string]string   // Lowercase spelling to the name used in the reports
+	queried   map[string][]string // Report name to its STATUS_ALIASES spellings, as written
+}
+
+// activeStatusAliases holds the defaults and STATUS_ALIASES, loaded at startup.
+var activeStatusAliases = statusAliasTable{canonical: defaultStatusAliases}
+
+// loadStatusAliases extends (or overrides) the default aliases with
+// STATUS_ALIASES, e.g. "Code Review=POST,Ready for QA=ON_QA". An entry that isn't
+// a non-empty alias=status pair is an error.
+func loadStatusAliases() (statusAliasTable, error) {
+	table := statusAliasTable{
+		canonical: make(map[string]string, len(defaultStatusAliases)),
+		queried:   make(map[string][]string),
+	}
+	for alias, canonical := range defaultStatusAliases {
+		table.canonical[alias] = canonical
+	}
+	for _, entry := range envList("STATUS_ALIASES") {
+		alias, canonical, ok := strings.Cut(entry, "=")
+		alias, canonical = strings.TrimSpace(alias), strings.TrimSpace(canonical)
+		if !ok || alias == "" || canonical == "" {
+			return table, fmt.Errorf("STATUS_ALIASES: %q is not an alias=status pair", entry)
+		}
+		table.canonical[strings.ToLower(alias)] = canonical
+		if !strings.EqualFold(alias, canonical) {
+			table.queried[canonical] = append(table.queried[canonical], alias)
+		}
+	}
+	return table, nil
+}
+
+// canonicalStatus returns the report's spelling of a status name.
+func canonicalStatus(name string) string {
+	if canonical, ok := activeStatusAliases.canonical[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return canonical
+	}
+	return name
+}
+
+// statusJQLClause returns a JQL condition matching the status and the
+// STATUS_ALIASES spellings that map to it. The default aliases are left out:
+// they only differ in case, which JQL ignores, or are spellings an instance may
+// not have, and JQL rejects unknown status names.
+func statusJQLClause(status string) string {
+	values := []string{sanitizeJQLValue(status)}
+	for _, alias := range activeStatusAliases.queried[status] {
+		values = append(values, sanitizeJQLValue(alias))
+	}
+	return "status IN (" + strings.Join(values, ", ") + ")"
+}
+
+// presentStatuses returns the statuses that have a group in any of groups.
+func presentStatuses(groups ...map[string][]IssueItem) map[string]bool {
+	present := make(map[string]bool)
+	for _, group := range groups {
+		for status := range group {
+			present[status] = true
+		}
+	}
+	return present
+}
+
+// statusesInOrder returns every status of present exactly once: those listed in
+// order first, in that order (repeated entries are skipped), then the others
+// alphabetically.
+func statusesInOrder(present map[string]bool, order []string) []string {
+	seen := make(map[string]bool, len(present))
+	var statuses []string
+	for _, status := range order {
+		if present[status] && !seen[status] {
+			statuses = append(statuses, status)
+			seen[status] = true
+		}
+	}
+	var others []string
+	for status := range present {
+		if !seen[status] {
+			others = append(others, status)
+		}
+	}
+	sort.Strings(others)
+	return append(statuses, others...)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStatusesInOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		present []string
+		order   []string
+		want    []string
+	}{
+		{
+			name:    "listed statuses in order",
+			present: []string{"ON_QA", "POST", "In Progress"},
+			order:   []string{"In Progress", "POST", "ON_QA"},
+			want:    []string{"In Progress", "POST", "ON_QA"},
+		},
+		{
+			name:    "repeated entries appear once",
+			present: []string{"POST", "ON_QA"},
+			order:   []string{"POST", "ON_QA", "POST"},
+			want:    []string{"POST", "ON_QA"},
+		},
+		{
+			name:    "unlisted statuses follow alphabetically",
+			present: []string{"Verified", "POST", "Blocked"},
+			order:   []string{"POST", "ON_QA"},
+			want:    []string{"POST", "Blocked", "Verified"},
+		},
+		{
+			name:    "absent listed statuses are skipped",
+			present: []string{"Closed"},
+			order:   []string{"POST", "ON_QA", "Closed"},
+			want:    []string{"Closed"},
+		},
+		{
+			name:  "nothing present",
+			order: []string{"POST"},
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			present := make(map[string]bool)
+			for _, status := range tt.present {
+				present[status] = true
+			}
+			if got := statusesInOrder(present, tt.order); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("statusesInOrder = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPresentStatuses(t *testing.T) {
+	first := map[string][]IssueItem{"POST": {{Key: "MTV-1"}}, "ON_QA": {{Key: "MTV-2"}}}
+	second := map[string][]IssueItem{"POST": {{Key: "MTV-3"}}, "Closed": {{Key: "MTV-4"}}}
+
+	present := presentStatuses(first, second)
+	if got := statusesInOrder(present, nil); fmt.Sprint(got) != "[Closed ON_QA POST]" {
+		t.Errorf("presentStatuses = %v, want Closed, ON_QA and POST", present)
+	}
+	if len(presentStatuses()) != 0 {
+		t.Error("presentStatuses of nothing isn't empty")
+	}
+}
+
+func TestLoadStatusAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{name: "unset", value: ""},
+		{name: "pairs", value: "Code Review=POST, Ready for QA = ON_QA"},
+		{name: "missing equals", value: "Code Review=POST,Ready for QA", wantErr: true},
+		{name: "empty alias", value: "=POST", wantErr: true},
+		{name: "empty status", value: "Code Review=", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STATUS_ALIASES", tt.value)
+			if _, err := loadStatusAliases(); (err != nil) != tt.wantErr {
+				t.Errorf("loadStatusAliases error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// useStatusAliases loads STATUS_ALIASES for the rest of the test.
+func useStatusAliases(t *testing.T, value string) {
+	t.Helper()
+	t.Setenv("STATUS_ALIASES", value)
+	table, err := loadStatusAliases()
+	if err != nil {
+		t.Fatalf("loadStatusAliases: %v", err)
+	}
+	saved := activeStatusAliases
+	activeStatusAliases = table
+	t.Cleanup(func() { activeStatusAliases = saved })
+}
+
+func TestStatusAliasQueries(t *testing.T) {
+	useStatusAliases(t, "Code Review=POST,Peer review=POST,Ready for QA=ON_QA,post=POST")
+
+	tests := []struct {
+		in            string
+		wantCanonical string
+		wantClause    string
+	}{
+		{in: "code review", wantCanonical: "POST", wantClause: `status IN ("POST", "Code Review", "Peer review")`},
+		{in: "Ready for QA", wantCanonical: "ON_QA", wantClause: `status IN ("ON_QA", "Ready for QA")`},
+		{in: "modified", wantCanonical: "MODIFIED", wantClause: `status IN ("MODIFIED")`},
+		{in: "Verified", wantCanonical: "Verified", wantClause: `status IN ("Verified")`},
+		{in: "Blocked", wantCanonical: "Blocked", wantClause: `status IN ("Blocked")`},
+	}
+	for _, tt := range tests {
+		canonical := canonicalStatus(tt.in)
+		if canonical != tt.wantCanonical {
+			t.Errorf("canonicalStatus(%q) = %q, want %q", tt.in, canonical, tt.wantCanonical)
+		}
+		if got := statusJQLClause(canonical); got != tt.wantClause {
+			t.Errorf("statusJQLClause(%q) = %s, want %s", canonical, got, tt.wantClause)
+		}
+	}
+
+	if jql := buildJQLQueryWithStatus("jdoe", false, "POST"); !strings.Contains(jql, ` AND status IN ("POST", "Code Review", "Peer review") AND `) {
+		t.Errorf("slash query doesn't include the aliases: %s", jql)
+	}
+	if jql := userStatusJQL(jiraUser{Accounts: []string{"abc"}}, "ON_QA"); !strings.Contains(jql, ` AND status IN ("ON_QA", "Ready for QA") AND `) {
+		t.Errorf("status link query doesn't include the aliases: %s", jql)
+	}
+}
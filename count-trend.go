@@ -0,0 +1,61 @@
+// Issue count trend
+//
+// The run state also records the report's issue total, so the header can show
+// how the count moved since the previous report: "📈 +3 since yesterday",
+// "📉 -5 since Friday". The trend is omitted until a total has been recorded,
+// and for historical (-as-of) runs, which don't compare to the day before.
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// countTrend describes the change from the mode's previously recorded total to
+// total, or returns "" if there is no previous total.
+func countTrend(mode reportMode, total int, now time.Time) string {
+	if !asOfTime.IsZero() {
+		return ""
+	}
+	state, err := loadRunState()
+	if err != nil {
+		logf("⚠️  %v, omitting the count trend\n", err)
+		return ""
+	}
+	previous, ok := state.LastTotal[string(mode)]
+	if !ok {
+		return ""
+	}
+
+	since := "since the last report"
+	if last, ok := state.LastSuccess[string(mode)]; ok {
+		since = "since " + relativeDay(last, now)
+	}
+
+	switch delta := total - previous; {
+	case delta > 0:
+		return fmt.Sprintf("📈 +%d %s", delta, since)
+	case delta < 0:
+		return fmt.Sprintf("📉 %d %s", delta, since)
+	default:
+		return "➡️ no change " + since
+	}
+}
+
+// relativeDay names the day of t as seen from now: "yesterday", a weekday within
+// the last week, or a date.
+func relativeDay(t, now time.Time) string {
+	t = t.In(now.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch days := int(today.Sub(day).Hours() / 24); {
+	case days <= 0:
+		return "earlier today"
+	case days == 1:
+		return "yesterday"
+	case days < 7:
+		return t.Weekday().String()
+	default:
+		return t.Format("Jan 2")
+	}
+}
@@ -0,0 +1,151 @@
+// Linked customer bugs
+//
+// QE picks up issues with linked customer bugs first. With CUSTOMER_BUG_PROJECTS
+// set (comma-separated project keys, e.g. "OCPBUGS,RHOCPBUGS"), the issuelinks
+// field is fetched and each issue counts the distinct issues of those projects
+// that clone it ("is cloned by") or relate to it ("relates to"). Issues with any
+// show "🔗 2 customer bugs" after their PR links, and the report's footer counts
+// the affected issues. Unset, nothing is fetched or shown.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// customerBugRelations are the link descriptions, as seen from the reported issue,
+// that count a customer bug.
+var customerBugRelations = []string{"is cloned by", "relates to"}
+
+// jiraIssueLink is one entry of the issuelinks field. Exactly one of InwardIssue
+// and OutwardIssue is set: the other end of the link.
+type jiraIssueLink struct {
+	Type struct {
+		Inward  string `json:"inward"`
+		Outward string `json:"outward"`
+	} `json:"type"`
+	InwardIssue *struct {
+		Key string `json:"key"`
+	} `json:"inwardIssue"`
+	OutwardIssue *struct {
+		Key string `json:"key"`
+	} `json:"outwardIssue"`
+}
+
+// customerBugProjects returns the upper-cased CUSTOMER_BUG_PROJECTS, or nil when the feature is off.
+func customerBugProjects() []string {
+	var projects []string
+	for _, project := range envList("CUSTOMER_BUG_PROJECTS") {
+		projects = append(projects, strings.ToUpper(project))
+	}
+	return projects
+}
+
+// customerBugsEnabled reports whether CUSTOMER_BUG_PROJECTS is set.
+func customerBugsEnabled() bool {
+	return len(customerBugProjects()) > 0
+}
+
+// linkedIssue returns the key of the link's other issue and how this issue relates to it.
+func (l jiraIssueLink) linkedIssue() (key, relation string) {
+	switch {
+	case l.InwardIssue != nil:
+		return l.InwardIssue.Key, l.Type.Inward
+	case l.OutwardIssue != nil:
+		return l.OutwardIssue.Key, l.Type.Outward
+	}
+	return "", ""
+}
+
+// issueProject returns the project key of an issue key ("OCPBUGS-12" → "OCPBUGS").
+func issueProject(key string) string {
+	if i := strings.LastIndex(key, "-"); i > 0 {
+		return strings.ToUpper(key[:i])
+	}
+	return ""
+}
+
+// countCustomerBugs counts the distinct issues of the projects linked by a
+// customer bug relation.
+func countCustomerBugs(links []jiraIssueLink, projects []string) int {
+	seen := make(map[string]bool)
+	for _, link := range links {
+		key, relation := link.linkedIssue()
+		if key == "" || !containsFold(customerBugRelations, relation) || !containsString(projects, issueProject(key)) {
+			continue
+		}
+		seen[key] = true
+	}
+	return len(seen)
+}
+
+// issueCustomerBugs returns the number of linked customer bugs, or 0 when the
+// feature is off or the issuelinks field wasn't fetched.
+func issueCustomerBugs(issue JiraIssue) int {
+	projects := customerBugProjects()
+	value := issue.customField("issuelinks")
+	if len(projects) == 0 || value == nil {
+		return 0
+	}
+	var links []jiraIssueLink
+	if err := json.Unmarshal(value, &links); err != nil {
+		return 0
+	}
+	return countCustomerBugs(links, projects)
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// customerBugsSuffix renders the customer bug badge of an issue line, or "" if it has none.
+func customerBugsSuffix(issue IssueItem) string {
+	switch issue.CustomerBugs {
+	case 0:
+		return ""
+	case 1:
+		return "  |  🔗 1 customer bug"
+	}
+	return fmt.Sprintf("  |  🔗 %d customer bugs", issue.CustomerBugs)
+}
+
+// buildCustomerBugsBlocks creates the footer note counting issues with linked
+// customer bugs, or nil if there are none.
+func buildCustomerBugsBlocks(personGroups []PersonStatusGroup) []map[string]interface{} {
+	affected := 0
+	for _, group := range personGroups {
+		for _, issues := range group.StatusGroups {
+			for _, issue := range issues {
+				if issue.CustomerBugs > 0 {
+					affected++
+				}
+			}
+		}
+	}
+	if affected == 0 {
+		return nil
+	}
+	noun := "issues have"
+	if affected == 1 {
+		noun = "issue has"
+	}
+
+	return []map[string]interface{}{
+		{
+			"type": "context",
+			"elements": []map[string]string{
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("🔗 %d %s linked customer bugs", affected, noun),
+				},
+			},
+		},
+	}
+}
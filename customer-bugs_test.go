@@ -0,0 +1,140 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// customerBugIssue returns an issue with the given issuelinks field JSON.
+func customerBugIssue(t *testing.T, links string) JiraIssue {
+	t.Helper()
+	return parseTestIssue(t, `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "issuelinks": `+links+`}}`)
+}
+
+func TestIssueCustomerBugs(t *testing.T) {
+	tests := []struct {
+		name     string
+		projects string // CUSTOMER_BUG_PROJECTS
+		links    string
+		want     int
+	}{
+		{
+			name:     "feature off",
+			projects: "",
+			links:    `[{"type": {"inward": "is cloned by"}, "inwardIssue": {"key": "OCPBUGS-1"}}]`,
+			want:     0,
+		},
+		{
+			name:     "clone in a customer project",
+			projects: "OCPBUGS",
+			links:    `[{"type": {"inward": "is cloned by"}, "inwardIssue": {"key": "OCPBUGS-1"}}]`,
+			want:     1,
+		},
+		{
+			name:     "relation in either direction",
+			projects: "OCPBUGS",
+			links: `[{"type": {"outward": "relates to"}, "outwardIssue": {"key": "OCPBUGS-1"}},
+				{"type": {"inward": "relates to"}, "inwardIssue": {"key": "OCPBUGS-2"}}]`,
+			want: 2,
+		},
+		{
+			name:     "other relations don't count",
+			projects: "OCPBUGS",
+			links: `[{"type": {"outward": "blocks"}, "outwardIssue": {"key": "OCPBUGS-1"}},
+				{"type": {"outward": "clones"}, "outwardIssue": {"key": "OCPBUGS-2"}}]`,
+			want: 0,
+		},
+		{
+			name:     "other projects don't count",
+			projects: "OCPBUGS",
+			links:    `[{"type": {"inward": "is cloned by"}, "inwardIssue": {"key": "MTV-2"}}]`,
+			want:     0,
+		},
+		{
+			name:     "an issue linked twice counts once",
+			projects: "OCPBUGS",
+			links: `[{"type": {"inward": "is cloned by"}, "inwardIssue": {"key": "OCPBUGS-1"}},
+				{"type": {"outward": "relates to"}, "outwardIssue": {"key": "OCPBUGS-1"}}]`,
+			want: 1,
+		},
+		{
+			name:     "projects and relations ignore case",
+			projects: "ocpbugs, rhocpbugs",
+			links: `[{"type": {"inward": "Is Cloned By"}, "inwardIssue": {"key": "OCPBUGS-1"}},
+				{"type": {"outward": "relates to"}, "outwardIssue": {"key": "RHOCPBUGS-7"}}]`,
+			want: 2,
+		},
+		{
+			name:     "field not fetched",
+			projects: "OCPBUGS",
+			links:    `null`,
+			want:     0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CUSTOMER_BUG_PROJECTS", tt.projects)
+			if got := issueCustomerBugs(customerBugIssue(t, tt.links)); got != tt.want {
+				t.Errorf("issueCustomerBugs = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCustomerBugsSuffix(t *testing.T) {
+	tests := []struct {
+		bugs int
+		want string
+	}{
+		{bugs: 0, want: ""},
+		{bugs: 1, want: "  |  🔗 1 customer bug"},
+		{bugs: 3, want: "  |  🔗 3 customer bugs"},
+	}
+
+	for _, tt := range tests {
+		if got := customerBugsSuffix(IssueItem{CustomerBugs: tt.bugs}); got != tt.want {
+			t.Errorf("customerBugsSuffix(%d) = %q, want %q", tt.bugs, got, tt.want)
+		}
+	}
+
+	// The badge follows the PR links of the issue line
+	issue := IssueItem{Key: "MTV-1", Summary: "Fix it", Status: "POST", GitPullRequest: []string{"https://github.com/x/y/pull/1"}, CustomerBugs: 2}
+	line := formatDailyIssueText("https://jira.example.com", issue, modeQA, envRender)
+	if !strings.Contains(line, "|PR1>  |  🔗 2 customer bugs") {
+		t.Errorf("issue line = %q, want the badge after the PR links", line)
+	}
+}
+
+func TestBuildCustomerBugsBlocks(t *testing.T) {
+	group := func(bugs ...int) PersonStatusGroup {
+		var issues []IssueItem
+		for _, n := range bugs {
+			issues = append(issues, IssueItem{Key: "MTV-1", Status: "POST", CustomerBugs: n})
+		}
+		return PersonStatusGroup{StatusGroups: map[string][]IssueItem{"POST": issues}}
+	}
+
+	tests := []struct {
+		name   string
+		groups []PersonStatusGroup
+		want   string // Footer text, "" for no footer
+	}{
+		{name: "no customer bugs", groups: []PersonStatusGroup{group(0, 0)}, want: ""},
+		{name: "one issue", groups: []PersonStatusGroup{group(0, 2)}, want: "🔗 1 issue has linked customer bugs"},
+		{name: "issues across people", groups: []PersonStatusGroup{group(1), group(0, 3)}, want: "🔗 2 issues have linked customer bugs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := buildCustomerBugsBlocks(tt.groups)
+			got := ""
+			if len(blocks) > 0 {
+				got = blocks[0]["elements"].([]map[string]string)[0]["text"]
+			}
+			if got != tt.want || len(blocks) > 1 {
+				t.Errorf("footer = %q (%d blocks), want %q", got, len(blocks), tt.want)
+			}
+		})
+	}
+}
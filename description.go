@@ -0,0 +1,128 @@
+// Description preview
+//
+// With SHOW_DESCRIPTION=true each issue in the daily report gets a dimmed second
+// line with the start of its description, DESCRIPTION_LENGTH characters long
+// (default 150). The description field is only requested when the flag is on,
+// since descriptions can be large.
+//
+// The search API returns descriptions as Atlassian Document Format; older
+// instances return wiki markup. Both are flattened to plain text: wiki *bold*,
+// {code} blocks, [links|...], headings and lists lose their markup.
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// wikiMarkupRules rewrite JIRA wiki markup to plain text, in order.
+var wikiMarkupRules = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{regexp.MustCompile(`\{(code|noformat|quote|panel|color)(:[^}]*)?\}`), " "}, // Block markers, content kept
+	{regexp.MustCompile(`![^!\s]+!`), " "},                                      // Embedded images
+	{regexp.MustCompile(`\[([^|\]]*)\|[^\]]*\]`), "$1"},                         // [text|url]
+	{regexp.MustCompile(`\[([^\]]*)\]`), "$1"},                                  // [url]
+	{regexp.MustCompile(`(?m)^\s*h[1-6]\.\s*`), ""},                             // Headings
+	{regexp.MustCompile(`(?m)^\s*[*#-]+\s+`), "• "},                             // List items
+	{regexp.MustCompile(`\*([^*\n]+)\*`), "$1"},                                 // *bold*
+	{regexp.MustCompile(`\b_([^_\n]+)_\b`), "$1"},                               // _italic_
+	{regexp.MustCompile(`\{\{([^}]*)\}\}`), "$1"},                               // {{monospace}}
+}
+
+// descriptionPreview returns the issue's description as a one-line plain-text
+// preview, or "" when SHOW_DESCRIPTION is off or the description is empty.
+func descriptionPreview(issue JiraIssue) string {
+	if !envBool("SHOW_DESCRIPTION", false) {
+		return ""
+	}
+	text := strings.Join(strings.Fields(descriptionText(issue.Fields.Description)), " ")
+	return truncateRunes(text, envInt("DESCRIPTION_LENGTH", 150))
+}
+
+// descriptionText flattens a description in either wiki markup or Atlassian
+// Document Format to plain text.
+func descriptionText(description interface{}) string {
+	switch value := description.(type) {
+	case string:
+		return wikiToPlainText(value)
+	case map[string]interface{}:
+		var b strings.Builder
+		writeADFText(&b, value)
+		return b.String()
+	}
+	return ""
+}
+
+// wikiToPlainText strips JIRA wiki markup.
+func wikiToPlainText(text string) string {
+	for _, rule := range wikiMarkupRules {
+		text = rule.pattern.ReplaceAllString(text, rule.replacement)
+	}
+	return text
+}
+
+// writeADFText writes the text of an Atlassian Document Format node and its children.
+func writeADFText(b *strings.Builder, node map[string]interface{}) {
+	switch node["type"] {
+	case "text":
+		text, _ := node["text"].(string)
+		b.WriteString(text)
+		return
+	case "hardBreak":
+		b.WriteString(" ")
+		return
+	case "listItem":
+		b.WriteString(" • ")
+	case "mention", "emoji":
+		if attrs, ok := node["attrs"].(map[string]interface{}); ok {
+			text, _ := attrs["text"].(string)
+			b.WriteString(text)
+		}
+		return
+	}
+
+	content, _ := node["content"].([]interface{})
+	for _, child := range content {
+		if childNode, ok := child.(map[string]interface{}); ok {
+			writeADFText(b, childNode)
+		}
+	}
+
+	// Block nodes end with a space so words of adjacent paragraphs don't merge
+	switch node["type"] {
+	case "paragraph", "heading", "codeBlock", "blockquote", "tableCell", "tableHeader":
+		b.WriteString(" ")
+	}
+}
+
+// truncateRunes shortens text to at most limit characters, ending with "…" when cut.
+func truncateRunes(text string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	runes := []rune(text)
+	if limit == 1 {
+		return "…"
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+// appendDescriptionLine adds the preview as an indented, dimmed (italic) line to an
+// issue's text, shortening the preview further if needed to stay within Slack's
+// section text limit.
+func appendDescriptionLine(text, preview string, render renderOptions) string {
+	room := slackSectionTextLimit - utf8.RuneCountInString(text)
+	for budget := utf8.RuneCountInString(preview); budget > 0; {
+		line := "\n" + render.indentPrefix(indentDetail) + "_" + escapeSlackText(truncateRunes(preview, budget)) + "_"
+		over := utf8.RuneCountInString(line) - room
+		if over <= 0 {
+			return text + line
+		}
+		// Escaping grows a character by up to 5 ("&" becomes "&amp;")
+		budget -= (over + 4) / 5
+	}
+	return text
+}
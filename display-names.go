@@ -0,0 +1,39 @@
+// Long display names
+//
+// Some JIRA display names run to 90 characters with a company suffix, which makes
+// person headers wrap. Names longer than DISPLAY_NAME_MAX_LENGTH (default 40, 0
+// disables) are shortened where they are shown: bracketed suffixes are dropped
+// first, then only the first and last word are kept, and anything still too long
+// is cut. Shortened names end with "…". Matching, grouping, links and manifests
+// keep using the full name, and the same name always shortens the same way.
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// defaultDisplayNameMaxLength is the longest name shown in full unless DISPLAY_NAME_MAX_LENGTH is set.
+const defaultDisplayNameMaxLength = 40
+
+// nameSuffixPattern matches bracketed suffixes such as "(Acme Consulting)" or "[EXT]".
+var nameSuffixPattern = regexp.MustCompile(`\s*(\([^)]*\)|\[[^\]]*\])`)
+
+// shortDisplayName returns name shortened to DISPLAY_NAME_MAX_LENGTH characters.
+func shortDisplayName(name string) string {
+	limit := envInt("DISPLAY_NAME_MAX_LENGTH", defaultDisplayNameMaxLength)
+	if limit <= 0 || utf8.RuneCountInString(name) <= limit {
+		return name
+	}
+
+	// Leave room for the ellipsis
+	short := strings.Join(strings.Fields(nameSuffixPattern.ReplaceAllString(name, " ")), " ")
+	if words := strings.Fields(short); len(words) > 2 && utf8.RuneCountInString(short) > limit-1 {
+		short = words[0] + " " + words[len(words)-1]
+	}
+	if utf8.RuneCountInString(short) > limit-1 {
+		short = strings.TrimSpace(string([]rune(short)[:limit-1]))
+	}
+	return short + "…"
+}
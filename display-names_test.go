@@ -0,0 +1,66 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShortDisplayName(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit string // DISPLAY_NAME_MAX_LENGTH, "" for the default
+		in    string
+		want  string
+	}{
+		{name: "short name", in: "Jane Doe", want: "Jane Doe"},
+		{name: "exactly the limit", in: strings.Repeat("a", 40), want: strings.Repeat("a", 40)},
+		{
+			name: "bracketed suffix dropped",
+			in:   "Alexandra Konstantinopoulou-Smith (Acme Consulting Services)",
+			want: "Alexandra Konstantinopoulou-Smith…",
+		},
+		{
+			name: "several suffixes dropped",
+			in:   "Jane Doe [EXT] (Some Very Long Consulting Company Name)",
+			want: "Jane Doe…",
+		},
+		{
+			name: "first and last word kept",
+			in:   "Maria Fernanda Guadalupe Rodriguez Hernandez",
+			want: "Maria Hernandez…",
+		},
+		{name: "single long word cut", in: strings.Repeat("b", 50), want: strings.Repeat("b", 39) + "…"},
+		{name: "cut after keeping two words", limit: "10", in: "Jane Doe Smith", want: "Jane Smit…"},
+		{name: "cut counts characters, not bytes", limit: "10", in: "Žofie Nováková", want: "Žofie Nov…"},
+		{name: "trailing space trimmed before the ellipsis", limit: "6", in: "Jane Doe", want: "Jane…"},
+		{name: "zero disables shortening", limit: "0", in: strings.Repeat("c", 80), want: strings.Repeat("c", 80)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DISPLAY_NAME_MAX_LENGTH", tt.limit)
+			if got := shortDisplayName(tt.in); got != tt.want {
+				t.Errorf("shortDisplayName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPersonHeaderUsesShortName(t *testing.T) {
+	t.Setenv("DISPLAY_NAME_MAX_LENGTH", "12")
+	group := PersonStatusGroup{Person: "Jane Doe (Acme Consulting)"}
+
+	if got := personHeaderName("", group, envRender); got != "Jane Doe…" {
+		t.Errorf("personHeaderName = %q, want %q", got, "Jane Doe…")
+	}
+
+	// The link still searches for the full name
+	header := personHeaderName("https://jira.example.com", group, envRender)
+	link, name, ok := strings.Cut(strings.Trim(header, "<>"), "|")
+	if !ok || name != "Jane Doe…" {
+		t.Fatalf("personHeaderName = %q, want a link named %q", header, "Jane Doe…")
+	}
+	if jql := queryOfSearchURL(t, link); !strings.Contains(jql, "Jane Doe (Acme Consulting)") {
+		t.Errorf("link JQL = %q, want the full name", jql)
+	}
+}
@@ -0,0 +1,67 @@
+// Raw response dump
+//
+// -dump-raw=<file> writes every JIRA search response body to the file as it comes
+// back, before it is decoded, one page per line (JSON Lines). This shows what JIRA
+// actually returns when fields end up empty, e.g. because a custom field ID
+// differs on another instance. Only response bodies are written, never request
+// headers, and they pass through the same secret redaction as the logs.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// rawDump receives raw JIRA responses while -dump-raw is set.
+var rawDump struct {
+	sync.Mutex
+	file *os.File
+}
+
+// openRawDump creates (or truncates) the dump file.
+func openRawDump(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create raw dump file: %w", err)
+	}
+	rawDump.Lock()
+	rawDump.file = file
+	rawDump.Unlock()
+	return nil
+}
+
+// closeRawDump closes the dump file, if any.
+func closeRawDump() {
+	rawDump.Lock()
+	defer rawDump.Unlock()
+	if rawDump.file == nil {
+		return
+	}
+	if err := rawDump.file.Close(); err != nil {
+		logf("⚠️  Failed to close raw dump file: %v\n", err)
+	}
+	rawDump.file = nil
+}
+
+// dumpRawResponse appends a response body to the dump file. Bodies that aren't
+// valid JSON (e.g. error pages) are written as a JSON string so each line parses.
+func dumpRawResponse(body []byte) {
+	rawDump.Lock()
+	defer rawDump.Unlock()
+	if rawDump.file == nil {
+		return
+	}
+
+	var line bytes.Buffer
+	if err := json.Compact(&line, body); err != nil {
+		line.Reset()
+		quoted, _ := json.Marshal(string(body))
+		line.Write(quoted)
+	}
+	if _, err := fmt.Fprintln(rawDump.file, redactSecrets(line.String())); err != nil {
+		logf("⚠️  Failed to write raw dump: %v\n", err)
+	}
+}
@@ -0,0 +1,80 @@
+// Epic clause narrowing
+//
+// The default qa query includes every open epic, and nearly all of them are then
+// dropped for having no PRs. To avoid fetching them, the epic clause is narrowed
+// with EPIC_PR_CLAUSE (default `cf[12310220] is not EMPTY`, the Git Pull Request
+// field) so only epics with PRs are fetched. The clause is checked against JIRA
+// once per process. If JIRA rejects it (e.g. the field isn't searchable on the
+// instance), the query falls back to the unnarrowed clause. A check that fails
+// for any other reason (network error, 5xx) isn't remembered, so a -server
+// process asks again on the next report. EPIC_PR_CLAUSE=off
+// disables the narrowing. A custom JIRA_JQL is never rewritten.
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// defaultEpicPRClause restricts epics to those with a Git Pull Request.
+const defaultEpicPRClause = "cf[12310220] is not EMPTY"
+
+// reportEpicClause is the epic clause of the default qa query.
+const reportEpicClause = "(type = Epic AND status != Closed)"
+
+// epicClauseSupport caches whether JIRA accepts a narrowing clause.
+var epicClauseSupport = struct {
+	sync.Mutex
+	checked map[string]bool
+}{checked: make(map[string]bool)}
+
+// epicPRClause returns the configured narrowing clause, or "" when disabled.
+func epicPRClause() string {
+	clause := strings.TrimSpace(os.Getenv("EPIC_PR_CLAUSE"))
+	switch strings.ToLower(clause) {
+	case "":
+		return defaultEpicPRClause
+	case "off", "false", "none":
+		return ""
+	}
+	return clause
+}
+
+// narrowEpicClause adds the PR clause to the epic clause of jql, if jql is the
+// default query and JIRA accepts the PR clause.
+func narrowEpicClause(jql, jiraURL, jiraToken string) string {
+	clause := epicPRClause()
+	if clause == "" || strings.TrimSpace(os.Getenv("JIRA_JQL")) != "" || !strings.Contains(jql, reportEpicClause) || !epicClauseSupported(clause, jiraURL, jiraToken) {
+		return jql
+	}
+	narrowed := "(type = Epic AND status != Closed AND " + clause + ")"
+	return strings.Replace(jql, reportEpicClause, narrowed, 1)
+}
+
+// epicClauseSupported reports whether JIRA accepts the clause. Only JIRA's answer
+// is cached; when the check itself fails the clause isn't used this time.
+func epicClauseSupported(clause, jiraURL, jiraToken string) bool {
+	epicClauseSupport.Lock()
+	defer epicClauseSupport.Unlock()
+
+	if supported, ok := epicClauseSupport.checked[clause]; ok {
+		return supported
+	}
+
+	_, err := validateJQL(jiraURL, jiraToken, "type = Epic AND "+clause)
+	var rejected *jqlRejectedError
+	switch {
+	case err == nil:
+		epicClauseSupport.checked[clause] = true
+		return true
+	case errors.As(err, &rejected) && rejected.StatusCode == http.StatusBadRequest:
+		logf("⚠️  EPIC_PR_CLAUSE %q not accepted by JIRA, fetching all open epics: %v\n", clause, err)
+		epicClauseSupport.checked[clause] = false
+	default:
+		logf("⚠️  Couldn't check EPIC_PR_CLAUSE %q, fetching all open epics this time: %v\n", clause, err)
+	}
+	return false
+}
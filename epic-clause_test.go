@@ -0,0 +1,98 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// resetEpicClauseSupport forgets the cached clause checks for the test.
+func resetEpicClauseSupport(t *testing.T) {
+	t.Helper()
+	epicClauseSupport.Lock()
+	epicClauseSupport.checked = make(map[string]bool)
+	epicClauseSupport.Unlock()
+	t.Cleanup(func() {
+		epicClauseSupport.Lock()
+		epicClauseSupport.checked = make(map[string]bool)
+		epicClauseSupport.Unlock()
+	})
+}
+
+func TestNarrowEpicClause(t *testing.T) {
+	defaultJQL := reportJQL(modeQA)
+
+	t.Run("default query is narrowed", func(t *testing.T) {
+		resetEpicClauseSupport(t)
+		server, _ := approximateCountStub(t, 200, `{"count": 3}`)
+		jql := narrowEpicClause(defaultJQL, server.URL, "token")
+		if !strings.Contains(jql, "status != Closed AND "+defaultEpicPRClause) {
+			t.Errorf("epic clause not narrowed: %s", jql)
+		}
+	})
+
+	t.Run("custom JIRA_JQL isn't narrowed", func(t *testing.T) {
+		resetEpicClauseSupport(t)
+		custom := "project = OTHER AND " + reportEpicClause
+		t.Setenv("JIRA_JQL", custom)
+		server, requests := approximateCountStub(t, 200, `{"count": 3}`)
+		if jql := narrowEpicClause(reportJQL(modeQA), server.URL, "token"); jql != custom {
+			t.Errorf("custom JQL rewritten to %s", jql)
+		}
+		if *requests != 0 {
+			t.Errorf("checked the clause %d times for a custom query", *requests)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		resetEpicClauseSupport(t)
+		t.Setenv("EPIC_PR_CLAUSE", "off")
+		if jql := narrowEpicClause(defaultJQL, "http://127.0.0.1:0", "token"); jql != defaultJQL {
+			t.Errorf("EPIC_PR_CLAUSE=off still narrowed: %s", jql)
+		}
+	})
+}
+
+func TestEpicClauseSupportedCaching(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		wantSupported bool
+		wantRequests  int
+	}{
+		{name: "accepted is cached", status: 200, body: `{"count": 3}`, wantSupported: true, wantRequests: 1},
+		{name: "rejected is cached", status: 400, body: `{"errorMessages": ["Field 'cf[12310220]' does not exist."]}`, wantRequests: 1},
+		{name: "server error is retried", status: 502, body: "bad gateway", wantRequests: 2},
+		{name: "auth failure is retried", status: 401, body: `{"errorMessages": ["Unauthorized"]}`, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetEpicClauseSupport(t)
+			server, requests := approximateCountStub(t, tt.status, tt.body)
+			for i := 0; i < 2; i++ {
+				if got := epicClauseSupported(defaultEpicPRClause, server.URL, "token"); got != tt.wantSupported {
+					t.Errorf("check %d: supported = %v, want %v", i+1, got, tt.wantSupported)
+				}
+			}
+			if *requests != tt.wantRequests {
+				t.Errorf("asked JIRA %d times, want %d", *requests, tt.wantRequests)
+			}
+		})
+	}
+
+	t.Run("network failure is retried", func(t *testing.T) {
+		resetEpicClauseSupport(t)
+		server, _ := approximateCountStub(t, 200, `{"count": 3}`)
+		server.Close()
+		if epicClauseSupported(defaultEpicPRClause, server.URL, "token") {
+			t.Error("unreachable JIRA reported the clause as supported")
+		}
+		epicClauseSupport.Lock()
+		_, cached := epicClauseSupport.checked[defaultEpicPRClause]
+		epicClauseSupport.Unlock()
+		if cached {
+			t.Error("network failure was cached")
+		}
+	})
+}
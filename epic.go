@@ -0,0 +1,116 @@
+// Epic display
+//
+// With SHOW_EPIC=true each issue in the daily report shows the epic it rolls up
+// to ("📎 Epic: KEY – summary"). The Epic Link field ID is instance specific and
+// configured via JIRA_FIELD_EPIC_LINK. Epic summaries are resolved with batched
+// secondary searches and cached for the rest of the run.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// defaultEpicLinkField is the Epic Link custom field in Red Hat JIRA.
+const defaultEpicLinkField = "customfield_12311140"
+
+// epicBatchSize is how many epic keys are resolved per secondary search.
+const epicBatchSize = 50
+
+// epicSummaryCache maps epic key -> summary for the lifetime of the run.
+// The server resolves epics from concurrent requests, so access is guarded.
+var (
+	epicSummaryCache   = make(map[string]string)
+	epicSummaryCacheMu sync.Mutex
+)
+
+// epicLinkField returns the configured Epic Link field ID.
+func epicLinkField() string {
+	if field := os.Getenv("JIRA_FIELD_EPIC_LINK"); field != "" {
+		return field
+	}
+	return defaultEpicLinkField
+}
+
+// issueEpicKey returns the key of the issue's epic, or "" if it has none (or SHOW_EPIC is off).
+func issueEpicKey(issue JiraIssue) string {
+	if !envBool("SHOW_EPIC", false) {
+		return ""
+	}
+	return issue.customFieldString(epicLinkField())
+}
+
+// resolveEpicSummaries fills in EpicSummary for every issue in the groups.
+// Keys not already cached are fetched in batches; a failed lookup only loses the
+// summary, the epic key is still displayed.
+func resolveEpicSummaries(jiraURL, jiraToken string, groups []PersonStatusGroup) {
+	var missing []string
+	seen := make(map[string]bool)
+	epicSummaryCacheMu.Lock()
+	for _, group := range groups {
+		for _, issues := range group.StatusGroups {
+			for _, issue := range issues {
+				if issue.EpicKey == "" || seen[issue.EpicKey] {
+					continue
+				}
+				seen[issue.EpicKey] = true
+				if _, cached := epicSummaryCache[issue.EpicKey]; !cached {
+					missing = append(missing, issue.EpicKey)
+				}
+			}
+		}
+	}
+	epicSummaryCacheMu.Unlock()
+
+	for start := 0; start < len(missing); start += epicBatchSize {
+		end := start + epicBatchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+
+		var keys []string
+		for _, key := range missing[start:end] {
+			keys = append(keys, sanitizeJQLValue(key))
+		}
+		jql := fmt.Sprintf("key in (%s)", strings.Join(keys, ", "))
+		responses, err := fetchJiraIssuesFields(jiraURL, jiraToken, jql, []string{"summary"})
+		if err != nil {
+			logf("⚠️  Failed to resolve epic summaries: %v\n", err)
+			continue
+		}
+		epicSummaryCacheMu.Lock()
+		for _, resp := range responses {
+			for _, epic := range resp.Issues {
+				epicSummaryCache[epic.Key] = epic.Fields.Summary
+			}
+		}
+		epicSummaryCacheMu.Unlock()
+	}
+
+	epicSummaryCacheMu.Lock()
+	defer epicSummaryCacheMu.Unlock()
+
+	for _, group := range groups {
+		for _, issues := range group.StatusGroups {
+			for i := range issues {
+				if issues[i].EpicKey != "" {
+					issues[i].EpicSummary = epicSummaryCache[issues[i].EpicKey]
+				}
+			}
+		}
+	}
+}
+
+// formatEpicLine renders the epic line shown under an issue, or "" if the issue has no epic.
+func formatEpicLine(jiraURL string, issue IssueItem) string {
+	if issue.EpicKey == "" {
+		return ""
+	}
+	line := fmt.Sprintf("📎 Epic: <%s|%s>", issueBrowseURL(jiraURL, issue.EpicKey), issue.EpicKey)
+	if issue.EpicSummary != "" {
+		line += " – " + escapeSlackText(issue.EpicSummary)
+	}
+	return line
+}
@@ -0,0 +1,157 @@
+// Exclusion patterns
+//
+// Entries in excludedComponents and excludedLabels may be plain strings (exact,
+// case-sensitive match, as before) or glob patterns: "*" matches any sequence of
+// characters (so "ui-*" is a prefix match), "?" any single character and "[abc]"
+// any character in the class (ranges like [0-9] and negations like [!0-9] are
+// allowed).
+//
+// Patterns are compiled once at startup; an invalid pattern is a startup error.
+//
+// EXCLUDED_COMPONENTS and EXCLUDED_LABELS (comma-separated) replace the built-in
+// lists; set to an empty value they clear them.
+//
+// INCLUDED_COMPONENTS and INCLUDED_LABELS (comma-separated, same pattern syntax)
+// switch their kind to allowlist semantics: only issues with at least one matching
+// component (label) are kept, and the built-in exclusion list of that kind isn't
+// used. Include and exclude are mutually exclusive per kind: setting both
+// INCLUDED_X and EXCLUDED_X is a startup error.
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// exclusionPattern is a compiled entry of an exclusion list.
+type exclusionPattern struct {
+	raw  string
+	glob *regexp.Regexp // nil for plain strings
+}
+
+// matches reports whether value matches the pattern.
+func (p exclusionPattern) matches(value string) bool {
+	if p.glob == nil {
+		return value == p.raw
+	}
+	return p.glob.MatchString(value)
+}
+
+var (
+	excludedComponentPatterns []exclusionPattern
+	excludedLabelPatterns     []exclusionPattern
+	includedComponentPatterns []exclusionPattern // Allowlist from INCLUDED_COMPONENTS, nil if unset
+	includedLabelPatterns     []exclusionPattern // Allowlist from INCLUDED_LABELS, nil if unset
+)
+
+// compileExclusions compiles the exclusion lists (excludedComponents and
+// excludedLabels, or EXCLUDED_COMPONENTS and EXCLUDED_LABELS) or the
+// INCLUDED_COMPONENTS and INCLUDED_LABELS allowlists replacing them.
+func compileExclusions() error {
+	var err error
+	if includedComponentPatterns, excludedComponentPatterns, err = compileFilterKind("COMPONENTS", excludedComponents); err != nil {
+		return err
+	}
+	if includedLabelPatterns, excludedLabelPatterns, err = compileFilterKind("LABELS", excludedLabels); err != nil {
+		return err
+	}
+	return nil
+}
+
+// compileFilterKind compiles the allowlist or the exclusion list of one kind
+// (COMPONENTS or LABELS). Builtin is the exclusion list used when EXCLUDED_<kind>
+// isn't set. Setting both INCLUDED_<kind> and EXCLUDED_<kind> is an error.
+func compileFilterKind(kind string, builtin []string) (included, excluded []exclusionPattern, err error) {
+	includeVar, excludeVar := "INCLUDED_"+kind, "EXCLUDED_"+kind
+	excludes := builtin
+	_, excludeSet := os.LookupEnv(excludeVar)
+	if excludeSet {
+		excludes = envList(excludeVar)
+	}
+
+	includes := envList(includeVar)
+	if len(includes) > 0 {
+		if excludeSet && len(excludes) > 0 {
+			return nil, nil, fmt.Errorf("%s and %s are mutually exclusive, set only one", includeVar, excludeVar)
+		}
+		if included, err = compilePatterns(includes); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", includeVar, err)
+		}
+		return included, nil, nil
+	}
+
+	if excluded, err = compilePatterns(excludes); err != nil {
+		return nil, nil, fmt.Errorf("excluded %s: %w", strings.ToLower(kind), err)
+	}
+	return nil, excluded, nil
+}
+
+// compilePatterns compiles a list of plain strings and glob patterns.
+func compilePatterns(entries []string) ([]exclusionPattern, error) {
+	patterns := make([]exclusionPattern, 0, len(entries))
+	for _, entry := range entries {
+		pattern, err := compilePattern(entry)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	return patterns, nil
+}
+
+// compilePattern turns a glob into an anchored regular expression.
+// Entries without glob metacharacters stay plain strings.
+func compilePattern(entry string) (exclusionPattern, error) {
+	if !strings.ContainsAny(entry, "*?[") {
+		return exclusionPattern{raw: entry}, nil
+	}
+
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(entry); i++ {
+		switch c := entry[i]; c {
+		case '*':
+			expr.WriteString(".*")
+		case '?':
+			expr.WriteString(".")
+		case '[':
+			end := strings.IndexByte(entry[i+1:], ']')
+			if end <= 0 {
+				return exclusionPattern{}, fmt.Errorf("invalid pattern %q: unterminated character class", entry)
+			}
+			class := entry[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+
+	glob, err := regexp.Compile(expr.String())
+	if err != nil {
+		return exclusionPattern{}, fmt.Errorf("invalid pattern %q: %w", entry, err)
+	}
+	return exclusionPattern{raw: entry, glob: glob}, nil
+}
+
+// matchesAny reports whether value matches any of the patterns.
+func matchesAny(patterns []exclusionPattern, value string) bool {
+	_, ok := matchingPattern(patterns, value)
+	return ok
+}
+
+// matchingPattern returns the configured entry of the first pattern matching value.
+func matchingPattern(patterns []exclusionPattern, value string) (string, bool) {
+	for _, pattern := range patterns {
+		if pattern.matches(value) {
+			return pattern.raw, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompilePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		value   string
+		want    bool
+	}{
+		{pattern: "ui", value: "ui", want: true},
+		{pattern: "ui", value: "ui-core", want: false},
+		{pattern: "ui", value: "UI", want: false},
+		{pattern: "ui-*", value: "ui-core", want: true},
+		{pattern: "ui-*", value: "ui-", want: true},
+		{pattern: "ui-*", value: "core-ui-x", want: false},
+		{pattern: "*-docs", value: "mtv-docs", want: true},
+		{pattern: "v?", value: "v2", want: true},
+		{pattern: "v?", value: "v10", want: false},
+		{pattern: "v[0-9]", value: "v7", want: true},
+		{pattern: "v[0-9]", value: "vx", want: false},
+		{pattern: "v[!0-9]", value: "vx", want: true},
+		{pattern: "v[!0-9]", value: "v7", want: false},
+		{pattern: "2.*", value: "2.9", want: true},
+		{pattern: "2.*", value: "209", want: false},
+		{pattern: "a+b*", value: "a+bc", want: true},
+		{pattern: "a+b*", value: "aab", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.value, func(t *testing.T) {
+			pattern, err := compilePattern(tt.pattern)
+			if err != nil {
+				t.Fatalf("compilePattern(%q): %v", tt.pattern, err)
+			}
+			if got := pattern.matches(tt.value); got != tt.want {
+				t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompilePatternErrors(t *testing.T) {
+	for _, pattern := range []string{"v[0-9", "[]", "ui-["} {
+		if _, err := compilePattern(pattern); err == nil || !strings.Contains(err.Error(), pattern) {
+			t.Errorf("compilePattern(%q) error = %v, want an error naming the pattern", pattern, err)
+		}
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	patterns, err := compilePatterns([]string{"docs", "ui-*"})
+	if err != nil {
+		t.Fatalf("compilePatterns: %v", err)
+	}
+	tests := []struct {
+		value     string
+		want      bool
+		wantEntry string
+	}{
+		{value: "docs", want: true, wantEntry: "docs"},
+		{value: "ui-core", want: true, wantEntry: "ui-*"},
+		{value: "storage", want: false},
+	}
+	for _, tt := range tests {
+		if got := matchesAny(patterns, tt.value); got != tt.want {
+			t.Errorf("matchesAny(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+		if entry, _ := matchingPattern(patterns, tt.value); entry != tt.wantEntry {
+			t.Errorf("matchingPattern(%q) = %q, want %q", tt.value, entry, tt.wantEntry)
+		}
+	}
+	if matchesAny(nil, "docs") {
+		t.Error("an empty list matched")
+	}
+}
+
+// useFilterEnv sets the filter variables and recompiles the filters, restoring
+// both after the test.
+func useFilterEnv(t *testing.T, env map[string]string) error {
+	t.Helper()
+	// Registered before t.Setenv, so it runs after the variables are restored
+	t.Cleanup(func() {
+		if err := compileExclusions(); err != nil {
+			t.Errorf("restoring filters: %v", err)
+		}
+	})
+	for name, value := range env {
+		t.Setenv(name, value)
+	}
+	return compileExclusions()
+}
+
+func TestAllowlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		issue      string
+		wantReason string
+	}{
+		{
+			name:  "component on the allowlist is kept",
+			env:   map[string]string{"INCLUDED_COMPONENTS": "Controller, Inventory"},
+			issue: `{"fields": {"components": [{"name": "Storage"}, {"name": "Inventory"}]}}`,
+		},
+		{
+			name:       "component not on the allowlist is dropped",
+			env:        map[string]string{"INCLUDED_COMPONENTS": "Controller, Inventory"},
+			issue:      `{"fields": {"components": [{"name": "Storage"}]}}`,
+			wantReason: "component not in INCLUDED_COMPONENTS",
+		},
+		{
+			name:       "no components is dropped",
+			env:        map[string]string{"INCLUDED_COMPONENTS": "Controller"},
+			issue:      `{"fields": {}}`,
+			wantReason: "component not in INCLUDED_COMPONENTS",
+		},
+		{
+			name:  "allowlist glob",
+			env:   map[string]string{"INCLUDED_LABELS": "mtv-*"},
+			issue: `{"fields": {"labels": ["mtv-2.9"]}}`,
+		},
+		{
+			name:       "label not on the allowlist is dropped",
+			env:        map[string]string{"INCLUDED_LABELS": "mtv-*"},
+			issue:      `{"fields": {"labels": ["docs"]}}`,
+			wantReason: "label not in INCLUDED_LABELS",
+		},
+		{
+			name:  "built-in exclusions don't apply with an allowlist",
+			env:   map[string]string{"INCLUDED_COMPONENTS": "User Interface"},
+			issue: `{"fields": {"components": [{"name": "User Interface"}]}}`,
+		},
+		{
+			name:       "other kind keeps its exclusions",
+			env:        map[string]string{"INCLUDED_COMPONENTS": "Controller"},
+			issue:      `{"fields": {"components": [{"name": "Controller"}], "labels": ["user-interface"]}}`,
+			wantReason: "label 'user-interface'",
+		},
+		{
+			name:       "EXCLUDED_COMPONENTS replaces the built-in list",
+			env:        map[string]string{"EXCLUDED_COMPONENTS": "Docs"},
+			issue:      `{"fields": {"components": [{"name": "Docs"}]}}`,
+			wantReason: "component 'Docs'",
+		},
+		{
+			name:  "empty EXCLUDED_COMPONENTS clears the built-in list",
+			env:   map[string]string{"EXCLUDED_COMPONENTS": ""},
+			issue: `{"fields": {"components": [{"name": "User Interface"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := useFilterEnv(t, tt.env); err != nil {
+				t.Fatalf("compileExclusions: %v", err)
+			}
+			issue := parseTestIssue(t, tt.issue)
+			if got := filterOutReason(issue.Fields.Components, issue.Fields.Labels); got != tt.wantReason {
+				t.Errorf("filterOutReason = %q, want %q", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestIncludeAndExcludeAreExclusive(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{
+			name:    "components",
+			env:     map[string]string{"INCLUDED_COMPONENTS": "Controller", "EXCLUDED_COMPONENTS": "Docs"},
+			wantErr: "INCLUDED_COMPONENTS and EXCLUDED_COMPONENTS are mutually exclusive",
+		},
+		{
+			name:    "labels",
+			env:     map[string]string{"INCLUDED_LABELS": "mtv-*", "EXCLUDED_LABELS": "docs"},
+			wantErr: "INCLUDED_LABELS and EXCLUDED_LABELS are mutually exclusive",
+		},
+		{name: "different kinds", env: map[string]string{"INCLUDED_COMPONENTS": "Controller", "EXCLUDED_LABELS": "docs"}},
+		{name: "empty exclusion list", env: map[string]string{"INCLUDED_COMPONENTS": "Controller", "EXCLUDED_COMPONENTS": ""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := useFilterEnv(t, tt.env)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("compileExclusions: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("compileExclusions error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
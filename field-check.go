@@ -0,0 +1,145 @@
+// JIRA field check
+//
+// On a JIRA instance without Red Hat's custom fields the report silently shows
+// no QA contacts and no PRs. Before fetching, the report loads the instance's
+// field list (/rest/api/2/field, once per run) and checks that the QA Contact and
+// Git Pull Request fields exist, as well as every custom field the configuration
+// asks for (severity, epic link, ...). Missing fields are logged prominently with
+// how to substitute them; with -strict-fields the run fails instead. If the field
+// list can't be loaded the check is skipped with a warning.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Custom field IDs the JiraIssue struct is bound to (Red Hat JIRA).
+const (
+	qaContactFieldID   = "customfield_12315948"
+	pullRequestFieldID = "customfield_12310220"
+)
+
+// strictFields fails the run on missing fields instead of warning (set by -strict-fields).
+var strictFields bool
+
+// jiraFieldCache holds the field list of the instance for the rest of the run.
+var jiraFieldCache struct {
+	sync.Mutex
+	fields map[string]string // ID -> name
+}
+
+// checkedField is a field the report relies on and how to point it elsewhere.
+type checkedField struct {
+	id  string
+	use string
+	fix string
+}
+
+// fetchJiraFieldNames returns the instance's fields by ID, loading them on first use.
+func fetchJiraFieldNames(jiraURL, jiraToken string) (map[string]string, error) {
+	jiraFieldCache.Lock()
+	defer jiraFieldCache.Unlock()
+	if jiraFieldCache.fields != nil {
+		return jiraFieldCache.fields, nil
+	}
+
+	req, err := http.NewRequest("GET", jiraURL+"/rest/api/2/field", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	setJiraAuth(req, jiraToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("JIRA API returned %d: %s", resp.StatusCode, redactSecrets(string(body)))
+	}
+
+	var list []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	fields := make(map[string]string, len(list))
+	for _, field := range list {
+		fields[field.ID] = field.Name
+	}
+	jiraFieldCache.fields = fields
+	return fields, nil
+}
+
+// reportFields returns the custom fields the mode's report reads.
+func reportFields(mode reportMode) []checkedField {
+	fields := []checkedField{
+		{qaContactFieldID, "QA Contact", "the ID is fixed in main.go (JiraIssue.QAContact and the search fields)"},
+		{pullRequestFieldID, "Git Pull Request", "the ID is fixed in main.go (JiraIssue.GitPullRequest and the search fields)"},
+		{severityField(), "Severity", "set JIRA_FIELD_SEVERITY"},
+	}
+	if envBool("SHOW_EPIC", false) {
+		fields = append(fields, checkedField{epicLinkField(), "Epic Link", "set JIRA_FIELD_EPIC_LINK"})
+	}
+
+	// Any other configured extra field
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.id] = true
+	}
+	for _, id := range reportFetchOptions(mode).ExtraFields {
+		if strings.HasPrefix(id, "customfield_") && !known[id] {
+			fields = append(fields, checkedField{id, "configured field", "check the configuration that names it"})
+			known[id] = true
+		}
+	}
+	return fields
+}
+
+// checkJiraFields warns about (or with -strict-fields, fails on) report fields the
+// instance doesn't have.
+func checkJiraFields(jiraURL, jiraToken string, mode reportMode) error {
+	names, err := fetchJiraFieldNames(jiraURL, jiraToken)
+	if err != nil {
+		logf("⚠️  Couldn't load the JIRA field list, skipping the field check: %v\n", err)
+		return nil
+	}
+
+	var missing []string
+	for _, field := range reportFields(mode) {
+		if _, ok := names[field.id]; !ok {
+			missing = append(missing, fmt.Sprintf("   • %s (%s): %s", field.id, field.use, field.fix))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	if strictFields {
+		logln("❌ JIRA doesn't have these fields the report reads:")
+	} else {
+		logln("⚠️  ─────────────────────────────────────────────")
+		logln("⚠️  JIRA doesn't have these fields the report reads, so their values will be empty:")
+	}
+	for _, line := range missing {
+		logln(line)
+	}
+	if strictFields {
+		return fmt.Errorf("%d report field(s) missing in JIRA", len(missing))
+	}
+	logln("⚠️  ─────────────────────────────────────────────")
+	return nil
+}
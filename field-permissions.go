@@ -0,0 +1,119 @@
+// Field-level permissions
+//
+// A token that may not see a field gets issues without it: JIRA leaves the field
+// out of the fields object rather than returning null (the QA contact of a
+// restricted project, for instance). Absent fields decode as empty, exactly like
+// null ones, so the report degrades instead of failing. The first issue that
+// comes back without a requested field logs one warning per run naming the field
+// and the likely cause.
+//
+// -validate-jql also fetches one matching issue with the report's fields and
+// lists the requested fields it came back without, to catch a token's missing
+// permissions before the first report.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// errSampleFetched stops a search after the sample page.
+var errSampleFetched = errors.New("sample fetched")
+
+// missingFieldWarnings remembers the fields already warned about in this run.
+var missingFieldWarnings struct {
+	sync.Mutex
+	warned map[string]bool
+}
+
+// fieldLabel names a field for logs: its ID, plus its name when the field list
+// has been loaded (see field-check.go).
+func fieldLabel(id string) string {
+	jiraFieldCache.Lock()
+	name := jiraFieldCache.fields[id]
+	jiraFieldCache.Unlock()
+	if name == "" || name == id {
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", id, name)
+}
+
+// missingFields returns the fields of requested that aren't in the issue's
+// fields object at all, as opposed to being null.
+func missingFields(issue JiraIssue, requested []string) []string {
+	if issue.RawFields == nil {
+		return nil
+	}
+	var missing []string
+	for _, id := range requested {
+		if _, ok := issue.RawFields[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// warnMissingFields logs, once per run and field, requested fields an issue came
+// back without.
+func warnMissingFields(ctx context.Context, issues []JiraIssue, requested []string) {
+	missingFieldWarnings.Lock()
+	defer missingFieldWarnings.Unlock()
+	if missingFieldWarnings.warned == nil {
+		missingFieldWarnings.warned = make(map[string]bool)
+	}
+
+	for _, issue := range issues {
+		for _, id := range missingFields(issue, requested) {
+			if missingFieldWarnings.warned[id] {
+				continue
+			}
+			missingFieldWarnings.warned[id] = true
+			ctxLogf(ctx, "⚠️  JIRA returned %s without %s, treating it as empty. The token probably can't see the field (field-level security), or it isn't on the issue's screens\n", issue.Key, fieldLabel(id))
+		}
+	}
+}
+
+// checkSampleFields fetches one issue matching jql with the fields of opts and
+// returns its key and the requested fields it came back without. The key is
+// empty when nothing matches.
+func checkSampleFields(jiraURL, jiraToken, jql string, opts fetchOptions) (string, []string, error) {
+	opts.MaxResults = 1
+	requested := searchFields(opts)
+
+	var key string
+	var missing []string
+	_, err := streamJiraIssues(jiraURL, jiraToken, jql, opts, func(page JiraSearchResponse) error {
+		if len(page.Issues) > 0 {
+			key = page.Issues[0].Key
+			missing = missingFields(page.Issues[0], requested)
+		}
+		return errSampleFetched
+	})
+	if err != nil && !errors.Is(err, errSampleFetched) {
+		return "", nil, err
+	}
+	return key, missing, nil
+}
+
+// reportSampleFields runs checkSampleFields for -validate-jql and logs the result.
+func reportSampleFields(jiraURL, jiraToken, jql string, opts fetchOptions) {
+	key, missing, err := checkSampleFields(jiraURL, jiraToken, jql, opts)
+	switch {
+	case err != nil:
+		logf("⚠️  Couldn't fetch a sample issue to check the returned fields: %v\n", err)
+	case key == "":
+		logln("   No matching issue to check the returned fields on")
+	case len(missing) == 0:
+		logf("   ✓ Sample issue %s has every requested field\n", key)
+	default:
+		var labels []string
+		for _, id := range missing {
+			labels = append(labels, fieldLabel(id))
+		}
+		logf("⚠️  Sample issue %s came back without: %s\n", key, strings.Join(labels, ", "))
+		logln("   The token probably can't see these fields (field-level security); the report shows them as empty")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestMissingFields(t *testing.T) {
+	requested := []string{"summary", "customfield_12315948", "customfield_12310220"}
+	tests := []struct {
+		name  string
+		issue string
+		want  []string
+	}{
+		{
+			name:  "every field returned",
+			issue: `{"key": "MTV-1", "fields": {"summary": "a", "customfield_12315948": {"displayName": "Quinn"}, "customfield_12310220": "pr"}}`,
+			want:  nil,
+		},
+		{
+			name:  "null is not missing",
+			issue: `{"key": "MTV-1", "fields": {"summary": "a", "customfield_12315948": null, "customfield_12310220": null}}`,
+			want:  nil,
+		},
+		{
+			name:  "left out fields are missing",
+			issue: `{"key": "MTV-1", "fields": {"summary": "a"}}`,
+			want:  []string{"customfield_12315948", "customfield_12310220"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := missingFields(parseTestIssue(t, tt.issue), requested)
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("missingFields = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// An issue that wasn't decoded from JIRA has no fields object to check
+	if got := missingFields(JiraIssue{Key: "MTV-1"}, requested); got != nil {
+		t.Errorf("missingFields without raw fields = %q, want none", got)
+	}
+}
+
+func TestWarnMissingFieldsOncePerField(t *testing.T) {
+	missingFieldWarnings.Lock()
+	saved := missingFieldWarnings.warned
+	missingFieldWarnings.warned = nil
+	missingFieldWarnings.Unlock()
+	defer func() {
+		missingFieldWarnings.Lock()
+		missingFieldWarnings.warned = saved
+		missingFieldWarnings.Unlock()
+	}()
+
+	requested := []string{"summary", "customfield_12315948", "customfield_12310220"}
+	first := []JiraIssue{
+		parseTestIssue(t, `{"key": "MTV-1", "fields": {"summary": "a"}}`),
+		parseTestIssue(t, `{"key": "MTV-2", "fields": {"summary": "b", "customfield_12310220": null}}`),
+	}
+	second := []JiraIssue{parseTestIssue(t, `{"key": "MTV-3", "fields": {"summary": "c"}}`)}
+
+	stdout, _ := captureOutput(t, func() {
+		warnMissingFields(context.Background(), first, requested)
+		warnMissingFields(context.Background(), second, requested)
+	})
+
+	if n := strings.Count(stdout, "without customfield_12315948"); n != 1 {
+		t.Errorf("warned %d times about the QA contact, want once:\n%s", n, stdout)
+	}
+	if n := strings.Count(stdout, "without customfield_12310220"); n != 1 {
+		t.Errorf("warned %d times about the PR field, want once:\n%s", n, stdout)
+	}
+	if !strings.Contains(stdout, "MTV-1") || strings.Contains(stdout, "MTV-3") {
+		t.Errorf("warnings should name the first issue only:\n%s", stdout)
+	}
+	if strings.Contains(stdout, "without summary") {
+		t.Errorf("warned about a returned field:\n%s", stdout)
+	}
+}
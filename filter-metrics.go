@@ -0,0 +1,72 @@
+// Filter metrics
+//
+// After every report run a single "filter_metrics" JSON log line tallies how
+// many issues were fetched, kept and removed, per reason (component, label,
+// priority, severity, epic-without-PR) and per rule, e.g.
+//
+//	📈 filter_metrics {"mode":"qa","fetched":120,"included":98,"excluded":22,"by_reason":{"component":15,"epic-without-PR":7},...}
+//
+// so filter effectiveness can be followed over time, not only with -filter-stats.
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// filterMetrics is the filter tally of one run.
+type filterMetrics struct {
+	Mode     reportMode     `json:"mode"`
+	Fetched  int            `json:"fetched"`
+	Included int            `json:"included"`
+	Excluded int            `json:"excluded"`
+	ByReason map[string]int `json:"by_reason"`
+	ByRule   map[string]int `json:"by_rule"`
+	// Epics fetched vs. kept shows how much the epic clause narrowing saves
+	EpicsFetched int `json:"epics_fetched"`
+	EpicsKept    int `json:"epics_kept"`
+}
+
+// exclusionCategory returns the reason category of an exclusionReason rule,
+// e.g. "component" for "component 'User Interface'".
+func exclusionCategory(rule string) string {
+	switch {
+	case strings.HasPrefix(rule, "component "):
+		return "component"
+	case strings.HasPrefix(rule, "label "):
+		return "label"
+	case strings.Contains(rule, "priority"):
+		return "priority"
+	case strings.HasPrefix(rule, "severity "):
+		return "severity"
+	}
+	return rule
+}
+
+// filterMetrics returns the filter tally of the issues added to the grouper.
+func (g *personGrouper) filterMetrics() filterMetrics {
+	metrics := filterMetrics{
+		Mode:     g.mode,
+		Fetched:  g.fetched,
+		Included: g.included,
+		Excluded: g.fetched - g.included,
+		ByReason: make(map[string]int),
+		ByRule:   make(map[string]int),
+	}
+	metrics.EpicsFetched, metrics.EpicsKept = g.epicsFetched, g.epicsKept
+	for rule, count := range g.removed {
+		metrics.ByReason[exclusionCategory(rule)] += count
+		metrics.ByRule[rule] = count
+	}
+	return metrics
+}
+
+// logFilterMetrics writes the tally as a single JSON log line.
+func logFilterMetrics(metrics filterMetrics) {
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		logf("⚠️  Failed to encode filter metrics: %v\n", err)
+		return
+	}
+	logf("📈 filter_metrics %s\n", data)
+}
@@ -0,0 +1,60 @@
+// Filter statistics
+//
+// -filter-stats fetches the report's issues without posting anything and prints
+// how many issues each filter rule removed, e.g.
+//
+//	component 'User Interface': 12 removed
+//	epic-without-PR: 5 removed
+//
+// to help tune the component/label exclusions and MIN_PRIORITY.
+package main
+
+import (
+	"os"
+	"sort"
+)
+
+// runFilterStats prints the per-rule breakdown of excluded issues for the mode.
+func runFilterStats(mode reportMode) {
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	if jiraURL == "" || jiraToken == "" {
+		logln("❌ Missing required credentials")
+		logln("Please set environment variables: JIRA_URL, JIRA_TOKEN")
+		os.Exit(1)
+	}
+
+	grouper := newPersonGrouper(mode)
+	jql := reportQuery(mode, jiraURL, jiraToken)
+	fetched, err := streamJiraIssues(jiraURL, jiraToken, jql, reportFetchOptions(mode), func(page JiraSearchResponse) error {
+		grouper.add(page)
+		return nil
+	})
+	if err != nil {
+		logf("❌ Failed to fetch JIRA issues: %v\n", err)
+		os.Exit(1)
+	}
+
+	removed := grouper.removed
+	totalRemoved := 0
+	var rules []string
+	for rule, count := range removed {
+		rules = append(rules, rule)
+		totalRemoved += count
+	}
+	// Most effective rules first
+	sort.Slice(rules, func(i, j int) bool {
+		if removed[rules[i]] != removed[rules[j]] {
+			return removed[rules[i]] > removed[rules[j]]
+		}
+		return rules[i] < rules[j]
+	})
+
+	logf("\n🧮 Filter breakdown for the %s report (%d fetched, %d kept, %d removed):\n", mode, fetched.Issues, fetched.Issues-totalRemoved, totalRemoved)
+	if len(rules) == 0 {
+		logln("   No issues were removed by the filters")
+	}
+	for _, rule := range rules {
+		logf("   %s: %d removed\n", rule, removed[rule])
+	}
+}
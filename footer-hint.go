@@ -0,0 +1,42 @@
+// Slash command hint
+//
+// The daily thread ends with a short context block pointing readers at the /issues
+// command, since new channel members often don't know it exists. The text can be
+// replaced with COMMAND_HINT_TEXT, and SHOW_COMMAND_HINT=false leaves it out.
+package main
+
+import "os"
+
+// defaultCommandHint is the footer shown at the end of the daily thread.
+const defaultCommandHint = "💡 Want just your items? Run `/issues` — or `/issues --all` for everything"
+
+// commandHintText returns the footer text, or "" if the hint is disabled.
+func commandHintText() string {
+	if !envBool("SHOW_COMMAND_HINT", true) {
+		return ""
+	}
+	if text := os.Getenv("COMMAND_HINT_TEXT"); text != "" {
+		return text
+	}
+	return defaultCommandHint
+}
+
+// buildCommandHintBlocks creates the footer context block, or nil if the hint is disabled.
+func buildCommandHintBlocks() []map[string]interface{} {
+	text := commandHintText()
+	if text == "" {
+		return nil
+	}
+
+	return []map[string]interface{}{
+		{
+			"type": "context",
+			"elements": []map[string]string{
+				{
+					"type": "mrkdwn",
+					"text": text,
+				},
+			},
+		},
+	}
+}
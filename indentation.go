@@ -0,0 +1,46 @@
+// Indentation
+//
+// Report lines are indented to show the person > status > issue hierarchy.
+// Non-breaking spaces work on desktop but collapse on Slack mobile, so
+// INDENT_STYLE selects how the levels are marked:
+//
+//	nbsp    - non-breaking spaces (default)
+//	quote   - issue lines in a Slack blockquote (">")
+//	bullets - nested bullet characters instead of spaces
+//
+// All report paths (daily thread, slash results, public slash threads) render
+// their status headers and issue lines through renderOptions.indentPrefix.
+package main
+
+import "strings"
+
+// Indentation levels of the report hierarchy
+const (
+	indentStatus = iota // Status headers under a person
+	indentIssue         // Issue lines under a status
+	indentDetail        // Detail lines of an issue (status, PRs, epic, description)
+)
+
+// indentStyles maps INDENT_STYLE values to the prefixes of each level.
+var indentStyles = map[string][3]string{
+	"nbsp":    {"\u00A0\u00A0\u00A0", "\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0", "\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0"},
+	"quote":   {"", "> ", "> "},
+	"bullets": {"", "", "◦ "},
+}
+
+// indentStyle returns the configured style, falling back to nbsp.
+func (r renderOptions) indentStyle() string {
+	style := strings.ToLower(strings.TrimSpace(r.setting("INDENT_STYLE")))
+	if _, ok := indentStyles[style]; ok {
+		return style
+	}
+	if style != "" {
+		logf("⚠️  Unknown INDENT_STYLE %q, using nbsp\n", style)
+	}
+	return "nbsp"
+}
+
+// indentPrefix returns the prefix of a line at the given level.
+func (r renderOptions) indentPrefix(level int) string {
+	return indentStyles[r.indentStyle()][level]
+}
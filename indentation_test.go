@@ -0,0 +1,65 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIndentStyles(t *testing.T) {
+	const nbsp = "\u00A0"
+	group := PersonStatusGroup{
+		Person:      "Jane Doe",
+		TotalIssues: 1,
+		StatusGroups: map[string][]IssueItem{
+			"POST": {{Key: "MTV-1", Summary: "Fix the migration plan", Status: "POST", GitPullRequest: []string{"https://github.com/x/y/pull/1"}, EpicKey: "MTV-100"}},
+		},
+	}
+	issue := "• <https://jira.example.com/browse/MTV-1|*MTV-1*> — Fix the migration plan"
+	details := []string{
+		"*Status:* POST  |  *PR:* <https://github.com/x/y/pull/1|PR1>",
+		"📎 Epic: <https://jira.example.com/browse/MTV-100|MTV-100>",
+	}
+
+	nbspGolden := []string{
+		"\n" + strings.Repeat(nbsp, 3) + "📂 *POST* (1)",
+		strings.Repeat(nbsp, 6) + issue + "\n" + strings.Repeat(nbsp, 8) + details[0] + "\n" + strings.Repeat(nbsp, 8) + details[1],
+	}
+	tests := []struct {
+		style string
+		want  []string // Texts of the status header and issue blocks
+	}{
+		{style: "", want: nbspGolden},
+		{style: "nbsp", want: nbspGolden},
+		{style: "unknown", want: nbspGolden},
+		{
+			style: "quote",
+			want: []string{
+				"\n📂 *POST* (1)",
+				"> " + issue + "\n> " + details[0] + "\n> " + details[1],
+			},
+		},
+		{
+			style: " Bullets ",
+			want: []string{
+				"\n📂 *POST* (1)",
+				issue + "\n◦ " + details[0] + "\n◦ " + details[1],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			t.Setenv("INDENT_STYLE", tt.style)
+			blocks := buildPersonBlocks(&reportThread{}, "https://jira.example.com", group, modeQA, 1, envRender)
+
+			// Between the person header and the closing separator
+			var got []string
+			for _, block := range blocks[1 : len(blocks)-1] {
+				got = append(got, block["text"].(map[string]string)["text"])
+			}
+			if strings.Join(got, "\n---\n") != strings.Join(tt.want, "\n---\n") {
+				t.Errorf("blocks:\n%q\nwant:\n%q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Issue line template
+//
+// Every issue line (daily report, /issues results and shared threads) is rendered
+// from one Go text/template. ISSUE_TEMPLATE replaces the default, e.g.
+//
+//	ISSUE_TEMPLATE='{{.Indent}}• <{{.URL}}|{{.Key}}> {{.Summary}} ({{.Status}}) {{.PRs}}'
+//
+// The template sees issueLineData; values are already escaped for Slack and the
+// optional parts (resolution, severity, ...) are empty when disabled. The template
+// is checked at startup, so a typo fails the run instead of every line. The epic
+// and description lines of the daily report are appended after the template.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// defaultIssueTemplate is the built-in issue line. Age is only set by the
+// in-progress report, which shows it in place of the status.
+const defaultIssueTemplate = "{{.Indent}}• <{{.URL}}|*{{.Key}}*> — {{.Summary}}{{.Links}}\n" +
+	"{{.DetailIndent}}{{if .Age}}*⏳ In status:* {{.Age}}{{else}}*Status:* {{.Status}}{{.Resolution}}{{.Severity}}{{end}}" +
+	"{{.Ownership}}  |  *PR:* {{.PRs}}{{.Watchers}}{{.CustomerBugs}}{{.Workstreams}}"
+
+// issueLineData is what ISSUE_TEMPLATE is executed with.
+type issueLineData struct {
+	Indent       string // Indentation of the issue line
+	DetailIndent string // Indentation of the detail line
+	Key          string
+	URL          string // The issue's browse URL
+	Summary      string // Escaped and truncated
+	Status       string
+	Resolution   string // " (Won't Fix)" style suffix, or empty
+	Severity     string // Severity suffix, or empty
+	Ownership    string // SHOW_OWNERSHIP_AGE suffix, or empty
+	Age          string // Time in the current status (in-progress report only)
+	PRs          string // PR links, or "–" (or the missing PR badge)
+	Watchers     string // SHOW_WATCHERS suffix, or empty
+	CustomerBugs string // Linked customer bugs suffix, or empty
+	Links        string // SHOW_LINKS link count suffix, or empty
+	Workstreams  string // Other matching workstreams with GROUP_BY=workstream, or empty
+}
+
+// activeIssueTemplate is the parsed issue line template, loaded at startup.
+var activeIssueTemplate = template.Must(template.New("issue").Parse(defaultIssueTemplate))
+
+// loadIssueTemplate parses ISSUE_TEMPLATE, or the default when unset.
+func loadIssueTemplate() (*template.Template, error) {
+	return parseIssueTemplate(os.Getenv("ISSUE_TEMPLATE"))
+}
+
+// parseIssueTemplate parses an issue line template, or the default when text is
+// empty, and renders a sample line to catch unknown fields.
+func parseIssueTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = defaultIssueTemplate
+	}
+	tmpl, err := template.New("issue").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("ISSUE_TEMPLATE is not a valid template: %w", err)
+	}
+	sample := issueLineData{Key: "MTV-1", URL: "https://jira.example.com/browse/MTV-1", Summary: "Sample", Status: "POST", PRs: "–"}
+	if err := tmpl.Execute(&strings.Builder{}, sample); err != nil {
+		return nil, fmt.Errorf("ISSUE_TEMPLATE can't be rendered: %w", err)
+	}
+	return tmpl, nil
+}
+
+// issuePRLinks formats the issue's PR links, or "–" without any (the missing PR
+// badge when flagged, see missing-pr.go).
+func issuePRLinks(issue IssueItem) string {
+	if missingPRFlagged(issue) {
+		return missingPRBadge
+	}
+	if len(issue.GitPullRequest) == 0 {
+		return "–"
+	}
+	var prLinks []string
+	for i, prURL := range issue.GitPullRequest {
+		prLinks = append(prLinks, fmt.Sprintf("<%s|PR%d>", prURL, i+1))
+	}
+	return strings.Join(prLinks, " ")
+}
+
+// newIssueLineData fills the template fields shared by every renderer, with the
+// summary cut to maxSummary bytes (backing off to a character boundary).
+func newIssueLineData(jiraURL string, issue IssueItem, maxSummary int, render renderOptions) issueLineData {
+	summary := escapeSlackText(issue.Summary)
+	if len(summary) > maxSummary {
+		for maxSummary > 0 && !utf8.RuneStart(summary[maxSummary]) {
+			maxSummary--
+		}
+		summary = summary[:maxSummary] + "..."
+	}
+	return issueLineData{
+		Indent:       render.indentPrefix(indentIssue),
+		DetailIndent: render.indentPrefix(indentDetail),
+		Key:          issue.Key,
+		URL:          issueBrowseURL(jiraURL, issue.Key),
+		Summary:      summary,
+		Status:       escapeSlackText(issue.Status),
+		Resolution:   resolutionSuffix(issue),
+		Severity:     severitySuffix(issue),
+		PRs:          issuePRLinks(issue),
+		CustomerBugs: customerBugsSuffix(issue),
+		Links:        linkCountSuffix(issue),
+	}
+}
+
+// renderIssueLine executes the issue line template. Errors can only come from
+// data the startup check didn't cover, so they fall back to the default layout.
+func renderIssueLine(data issueLineData, render renderOptions) string {
+	var b strings.Builder
+	if err := render.issueTemplate().Execute(&b, data); err != nil {
+		logf("⚠️  ISSUE_TEMPLATE failed for %s, using the default: %v\n", data.Key, err)
+		b.Reset()
+		template.Must(template.New("issue").Parse(defaultIssueTemplate)).Execute(&b, data)
+	}
+	return b.String()
+}
@@ -0,0 +1,387 @@
+// Issue watching
+//
+// Reacting with 👀 (:eyes:) to a reply in the report thread subscribes the
+// reacting user to the issues in that reply; removing the reaction unsubscribes.
+// Replies are mapped to their issues through the report manifest, and
+// subscriptions are kept in watches.json in STATE_DIR. Each daily run looks up the
+// status of every watched issue and DMs the watchers of issues whose status
+// changed since it was last seen. Watched issues JIRA no longer finds (deleted,
+// or no longer visible to the token) are dropped, and moved issues are followed
+// to their new key.
+//
+// The server and the daily run both write watches.json. Every write re-reads the
+// file just before replacing it (see updateWatches), and the daily run applies
+// its status updates that way after notifying, so subscriptions the server made
+// in the meantime are kept.
+//
+// Requires the Events API with the Request URL set to /slack/events and the
+// reaction_added and reaction_removed bot events (reactions:read scope), plus
+// im:write for the DMs. The server and the daily run must share STATE_DIR.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// watchStoreFile is the name of the subscription file inside STATE_DIR
+	watchStoreFile = "watches.json"
+
+	// watchReaction is the reaction that subscribes to a reply's issues
+	watchReaction = "eyes"
+
+	// watchQueryBatch is how many issue keys are looked up per JQL query
+	watchQueryBatch = 100
+)
+
+// issueWatch is the subscription state of one issue.
+type issueWatch struct {
+	Status   string   `json:"status"`   // Status when last seen
+	Watchers []string `json:"watchers"` // Slack user IDs
+}
+
+// watchStoreMu serializes read-modify-write cycles of the subscription file
+// within a process. Across processes the cycles are kept short: the file is
+// re-read right before each atomic write.
+var watchStoreMu sync.Mutex
+
+// slackEventEnvelope is the body of an Events API request.
+type slackEventEnvelope struct {
+	Type      string     `json:"type"` // url_verification or event_callback
+	Challenge string     `json:"challenge"`
+	Event     slackEvent `json:"event"`
+}
+
+// slackEvent is the part of a reaction event the watcher needs.
+type slackEvent struct {
+	Type     string `json:"type"`
+	User     string `json:"user"`
+	Reaction string `json:"reaction"`
+	Item     struct {
+		Type    string `json:"type"`
+		Channel string `json:"channel"`
+		TS      string `json:"ts"`
+	} `json:"item"`
+}
+
+// loadWatches reads the subscriptions by issue key. A missing file yields none.
+func loadWatches() (map[string]issueWatch, error) {
+	watches := make(map[string]issueWatch)
+
+	data, err := os.ReadFile(filepath.Join(stateDir(), watchStoreFile))
+	if os.IsNotExist(err) {
+		return watches, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watches: %w", err)
+	}
+	if err := json.Unmarshal(data, &watches); err != nil {
+		return nil, fmt.Errorf("failed to parse watches: %w", err)
+	}
+	return watches, nil
+}
+
+// updateWatches applies change to the freshly read subscriptions and writes them.
+func updateWatches(change func(map[string]issueWatch)) error {
+	watchStoreMu.Lock()
+	defer watchStoreMu.Unlock()
+
+	watches, err := loadWatches()
+	if err != nil {
+		return err
+	}
+	change(watches)
+	return writeStateFile(watchStoreFile, watches)
+}
+
+// handleSlackEvents receives Events API callbacks
+func handleSlackEvents(w http.ResponseWriter, r *http.Request) {
+	var envelope slackEventEnvelope
+	if err := json.NewDecoder(r.Body).Decode(&envelope); err != nil {
+		http.Error(w, "Invalid event", http.StatusBadRequest)
+		return
+	}
+
+	// Slack verifies the endpoint once when the Request URL is saved
+	if envelope.Type == "url_verification" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(envelope.Challenge))
+		return
+	}
+
+	// Slack expects an answer within 3 seconds, so events are handled afterwards
+	w.WriteHeader(http.StatusOK)
+	if envelope.Type == "event_callback" {
+		go processReactionEvent(envelope.Event)
+	}
+}
+
+// processReactionEvent subscribes or unsubscribes the reacting user to the issues
+// of the report reply the reaction is on.
+func processReactionEvent(event slackEvent) {
+	if event.Reaction != watchReaction || event.Item.Type != "message" || event.User == "" {
+		return
+	}
+
+	issues, err := issuesInMessage(event.Item.Channel, event.Item.TS)
+	if err != nil {
+		logf("❌ Failed to look up reacted message: %v\n", err)
+		return
+	}
+	if len(issues) == 0 {
+		return // Not a report reply
+	}
+
+	switch event.Type {
+	case "reaction_added":
+		err = updateWatches(func(watches map[string]issueWatch) {
+			for key, status := range issues {
+				watch := watches[key]
+				if watch.Status == "" {
+					watch.Status = status
+				}
+				if !containsString(watch.Watchers, event.User) {
+					watch.Watchers = append(watch.Watchers, event.User)
+				}
+				watches[key] = watch
+			}
+		})
+		if err == nil {
+			logf("👀 %s now watches %d issue(s)\n", event.User, len(issues))
+		}
+	case "reaction_removed":
+		err = updateWatches(func(watches map[string]issueWatch) {
+			for key := range issues {
+				watch := watches[key]
+				kept := watch.Watchers[:0]
+				for _, user := range watch.Watchers {
+					if user != event.User {
+						kept = append(kept, user)
+					}
+				}
+				if len(kept) == 0 {
+					delete(watches, key)
+					continue
+				}
+				watch.Watchers = kept
+				watches[key] = watch
+			}
+		})
+		if err == nil {
+			logf("👀 %s stopped watching %d issue(s)\n", event.User, len(issues))
+		}
+	default:
+		return
+	}
+	if err != nil {
+		logf("❌ Failed to save watches: %v\n", err)
+	}
+}
+
+// issuesInMessage returns the issues (key to status) of the people whose report
+// section is in the message, across all report modes.
+func issuesInMessage(channel, ts string) (map[string]string, error) {
+	manifests, err := loadReportManifests()
+	if err != nil {
+		return nil, err
+	}
+	issues := make(map[string]string)
+	for _, manifest := range manifests {
+		for _, person := range manifest.People {
+			if person.Channel == channel && (person.TS == ts || slices.Contains(person.Parts, ts)) {
+				for key, status := range person.Issues {
+					issues[key] = status
+				}
+			}
+		}
+	}
+	return issues, nil
+}
+
+// groupIssueStatuses returns the key and status of every issue of a person.
+func groupIssueStatuses(group PersonStatusGroup) map[string]string {
+	issues := make(map[string]string, group.TotalIssues)
+	for status, items := range group.StatusGroups {
+		for _, issue := range items {
+			issues[issue.Key] = status
+		}
+	}
+	return issues
+}
+
+// watchChange is a status change of a watched issue.
+type watchChange struct {
+	key, summary, from, to string
+}
+
+// notifyWatchers DMs the watchers of every watched issue whose status changed
+// since the last check, and records the new statuses.
+func notifyWatchers(jiraURL, jiraToken, botToken string) error {
+	watches, err := loadWatches()
+	if err != nil || len(watches) == 0 {
+		return err
+	}
+
+	keys := make([]string, 0, len(watches))
+	for key := range watches {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	current, gone, err := fetchWatchedIssues(jiraURL, jiraToken, keys)
+	if err != nil {
+		return fmt.Errorf("failed to fetch watched issues: %w", err)
+	}
+	if len(gone) > 0 {
+		logf("👀 Dropping %d watched issue(s) JIRA no longer finds: %s\n", len(gone), strings.Join(gone, ", "))
+	}
+
+	changesByUser := make(map[string][]watchChange)
+	for _, key := range keys {
+		watch := watches[key]
+		issue, ok := current[key]
+		if !ok || issue.Status == watch.Status {
+			continue
+		}
+		for _, user := range watch.Watchers {
+			changesByUser[user] = append(changesByUser[user], watchChange{key: issue.Key, summary: issue.Summary, from: watch.Status, to: issue.Status})
+		}
+	}
+
+	for user, changes := range changesByUser {
+		if err := sendWatchDM(botToken, jiraURL, user, changes); err != nil {
+			logf("   ⚠️  Failed to notify %s of %d change(s): %v\n", user, len(changes), err)
+		}
+	}
+	if len(changesByUser) > 0 {
+		logf("👀 Notified %d watcher(s) of status changes\n", len(changesByUser))
+	}
+
+	return updateWatches(func(watches map[string]issueWatch) {
+		applyWatchedIssues(watches, current, gone)
+	})
+}
+
+// applyWatchedIssues records the current status of the watched issues, follows
+// moved issues to their new key and drops the gone ones. Keys subscribed since
+// the issues were fetched are left alone.
+func applyWatchedIssues(watches map[string]issueWatch, current map[string]IssueItem, gone []string) {
+	for _, key := range gone {
+		delete(watches, key)
+	}
+	for key, issue := range current {
+		watch, ok := watches[key]
+		if !ok {
+			continue // Unsubscribed meanwhile
+		}
+		watch.Status = issue.Status
+		if issue.Key == key {
+			watches[key] = watch
+			continue
+		}
+
+		// Moved: merge into any subscriptions under the new key
+		delete(watches, key)
+		if existing, ok := watches[issue.Key]; ok {
+			for _, user := range existing.Watchers {
+				if !containsString(watch.Watchers, user) {
+					watch.Watchers = append(watch.Watchers, user)
+				}
+			}
+		}
+		watches[issue.Key] = watch
+	}
+}
+
+// fetchWatchedIssues returns the current state of the issues by watched key (a
+// moved issue is returned under the key it is watched by), and the keys JIRA no
+// longer finds. Keys are looked up in batches; when JIRA rejects a batch, or a
+// key is missing from the result, the keys are looked up one at a time.
+func fetchWatchedIssues(jiraURL, jiraToken string, keys []string) (map[string]IssueItem, []string, error) {
+	current := make(map[string]IssueItem, len(keys))
+	var retry []string
+	for start := 0; start < len(keys); start += watchQueryBatch {
+		end := start + watchQueryBatch
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch, err := fetchIssuesByKey(jiraURL, jiraToken, keys[start:end])
+		var rejected *jqlRejectedError
+		if errors.As(err, &rejected) && rejected.StatusCode == http.StatusBadRequest {
+			retry = append(retry, keys[start:end]...)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, key := range keys[start:end] {
+			if issue, ok := batch[key]; ok {
+				current[key] = issue
+			} else {
+				retry = append(retry, key)
+			}
+		}
+	}
+
+	var gone []string
+	for _, key := range retry {
+		found, err := fetchIssuesByKey(jiraURL, jiraToken, []string{key})
+		var rejected *jqlRejectedError
+		if errors.As(err, &rejected) && rejected.StatusCode == http.StatusBadRequest {
+			gone = append(gone, key)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, issue := range found {
+			current[key] = issue
+		}
+	}
+	return current, gone, nil
+}
+
+// fetchIssuesByKey fetches the issues with the given keys by their current key.
+func fetchIssuesByKey(jiraURL, jiraToken string, keys []string) (map[string]IssueItem, error) {
+	issues := make(map[string]IssueItem, len(keys))
+	jql := fmt.Sprintf("key in (%s)", strings.Join(keys, ", "))
+	_, err := streamJiraIssues(jiraURL, jiraToken, jql, fetchOptions{}, func(page JiraSearchResponse) error {
+		for _, issue := range page.Issues {
+			issues[issue.Key] = newIssueItem(issue)
+		}
+		return nil
+	})
+	return issues, err
+}
+
+// sendWatchDM sends a user the status changes of the issues they watch.
+func sendWatchDM(botToken, jiraURL, user string, changes []watchChange) error {
+	channel, err := openDirectMessage(botToken, user)
+	if err != nil {
+		return err
+	}
+
+	lines := []string{"👀 *Status changes on issues you watch:*"}
+	for _, change := range changes {
+		lines = append(lines, fmt.Sprintf("• <%s|*%s*> %s → %s — %s",
+			issueBrowseURL(jiraURL, change.key), change.key, escapeSlackMrkdwn(change.from), escapeSlackMrkdwn(change.to), escapeSlackText(change.summary)))
+	}
+	blocks := []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": strings.Join(lines, "\n")},
+		},
+	}
+	limitBlockText(blocks)
+	_, err = sendToSlackAPI(botToken, channel, "", blocks, envRender)
+	return err
+}
@@ -0,0 +1,384 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// watchedIssue is an issue served by the watch JIRA stub. A moved issue is
+// found by its old key but returned under movedTo.
+type watchedIssue struct {
+	status, movedTo string
+}
+
+// watchJiraStub answers "key in (...)" searches from issues, rejecting a query
+// with a 400 when any of its keys is unknown, like JIRA does. During is called
+// on every search. It returns the number of searches made.
+func watchJiraStub(t *testing.T, issues map[string]watchedIssue, during func()) (*httptest.Server, *int) {
+	t.Helper()
+	var searches int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			JQL string `json:"jql"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		searches++
+		if during != nil {
+			during()
+		}
+
+		list := strings.TrimSuffix(strings.TrimPrefix(body.JQL, "key in ("), ")")
+		var page JiraSearchResponse
+		for _, key := range strings.Split(list, ", ") {
+			issue, ok := issues[key]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprintf(w, `{"errorMessages": ["An issue with key '%s' does not exist for field 'key'."]}`, key)
+				return
+			}
+			var found JiraIssue
+			found.Key = key
+			if issue.movedTo != "" {
+				found.Key = issue.movedTo
+			}
+			found.Fields.Summary = "Summary of " + found.Key
+			found.Fields.Status.Name = issue.status
+			page.Issues = append(page.Issues, found)
+		}
+		json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server, &searches
+}
+
+// watchSlackStub serves conversations.open and chat.postMessage and returns the
+// DM texts by user.
+func watchSlackStub(t *testing.T) map[string]string {
+	t.Helper()
+	var mu sync.Mutex
+	dms := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Users   string `json:"users"`
+			Channel string `json:"channel"`
+			Blocks  []struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+			} `json:"blocks"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		switch r.URL.Path {
+		case "/conversations.open":
+			fmt.Fprintf(w, `{"ok": true, "channel": {"id": "D-%s"}}`, payload.Users)
+		case "/chat.postMessage":
+			mu.Lock()
+			dms[strings.TrimPrefix(payload.Channel, "D-")] = payload.Blocks[0].Text.Text
+			mu.Unlock()
+			json.NewEncoder(w).Encode(SlackMessageResponse{OK: true, TS: "1.0"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	previous := slackAPIBase
+	slackAPIBase = server.URL
+	t.Cleanup(func() { slackAPIBase = previous })
+	return dms
+}
+
+// storeWatches replaces the stored subscriptions.
+func storeWatches(t *testing.T, watches map[string]issueWatch) {
+	t.Helper()
+	if err := writeStateFile(watchStoreFile, watches); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// storedWatches reads the stored subscriptions.
+func storedWatches(t *testing.T) map[string]issueWatch {
+	t.Helper()
+	watches, err := loadWatches()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return watches
+}
+
+func TestWatchStore(t *testing.T) {
+	t.Setenv("STATE_DIR", t.TempDir())
+	if watches := storedWatches(t); len(watches) != 0 {
+		t.Fatalf("missing file gave %v", watches)
+	}
+
+	err := updateWatches(func(watches map[string]issueWatch) {
+		watches["MTV-1"] = issueWatch{Status: "POST", Watchers: []string{"U1"}}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = updateWatches(func(watches map[string]issueWatch) {
+		watch := watches["MTV-1"]
+		watch.Watchers = append(watch.Watchers, "U2")
+		watches["MTV-1"] = watch
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := storedWatches(t)["MTV-1"]; got.Status != "POST" || fmt.Sprint(got.Watchers) != "[U1 U2]" {
+		t.Errorf("stored %+v", got)
+	}
+}
+
+func TestIssuesInMessage(t *testing.T) {
+	t.Setenv("STATE_DIR", t.TempDir())
+	for _, manifest := range []reportManifest{
+		{Mode: modeQA, People: map[string]manifestPerson{
+			"Ann": {Channel: "C1", TS: "10.1", Issues: map[string]string{"MTV-1": "ON_QA"}},
+			"Bob": {Channel: "C1", TS: "10.1", Issues: map[string]string{"MTV-2": "POST"}},
+			"Cid": {Channel: "C1", TS: "10.2", Issues: map[string]string{"MTV-3": "POST"}},
+			"Eve": {Channel: "C1", TS: "10.3", Parts: []string{"10.4", "10.5"}, Issues: map[string]string{"MTV-5": "POST"}},
+		}},
+		{Mode: modeInProgress, People: map[string]manifestPerson{
+			"Dee": {Channel: "C2", TS: "10.1", Issues: map[string]string{"MTV-4": "In Progress"}},
+		}},
+	} {
+		if err := saveReportManifest(manifest); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		channel, ts string
+		want        string
+	}{
+		{name: "packed reply", channel: "C1", ts: "10.1", want: "map[MTV-1:ON_QA MTV-2:POST]"},
+		{name: "single person", channel: "C1", ts: "10.2", want: "map[MTV-3:POST]"},
+		{name: "later part of a split reply", channel: "C1", ts: "10.5", want: "map[MTV-5:POST]"},
+		{name: "other mode", channel: "C2", ts: "10.1", want: "map[MTV-4:In Progress]"},
+		{name: "not a report reply", channel: "C1", ts: "99.9", want: "map[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := issuesInMessage(tt.channel, tt.ts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := fmt.Sprint(issues); got != tt.want {
+				t.Errorf("issuesInMessage = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleSlackEvents(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "url verification", body: `{"type": "url_verification", "challenge": "abc123"}`, wantStatus: 200, wantBody: "abc123"},
+		{name: "invalid JSON", body: `{"type": `, wantStatus: 400},
+		{name: "other reaction", body: `{"type": "event_callback", "event": {"type": "reaction_added", "reaction": "tada"}}`, wantStatus: 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handleSlackEvents(rec, httptest.NewRequest("POST", "/slack/events", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantBody != "" && rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestProcessReactionEvent(t *testing.T) {
+	t.Setenv("STATE_DIR", t.TempDir())
+	err := saveReportManifest(reportManifest{Mode: modeQA, People: map[string]manifestPerson{
+		"Ann": {Channel: "C1", TS: "10.1", Issues: map[string]string{"MTV-1": "ON_QA", "MTV-2": "POST"}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	react := func(eventType, user, reaction, ts string) {
+		event := slackEvent{Type: eventType, User: user, Reaction: reaction}
+		event.Item.Type, event.Item.Channel, event.Item.TS = "message", "C1", ts
+		processReactionEvent(event)
+	}
+
+	react("reaction_added", "U1", watchReaction, "10.1")
+	react("reaction_added", "U2", watchReaction, "10.1")
+	react("reaction_added", "U1", watchReaction, "10.1") // Repeated events don't duplicate watchers
+	react("reaction_added", "U3", "tada", "10.1")
+	react("reaction_added", "U3", watchReaction, "99.9")
+	if got := storedWatches(t); fmt.Sprint(got) != "map[MTV-1:{ON_QA [U1 U2]} MTV-2:{POST [U1 U2]}]" {
+		t.Fatalf("after subscribing: %v", got)
+	}
+
+	react("reaction_removed", "U1", watchReaction, "10.1")
+	if got := storedWatches(t); fmt.Sprint(got) != "map[MTV-1:{ON_QA [U2]} MTV-2:{POST [U2]}]" {
+		t.Fatalf("after U1 unsubscribed: %v", got)
+	}
+	react("reaction_removed", "U2", watchReaction, "10.1")
+	if got := storedWatches(t); len(got) != 0 {
+		t.Fatalf("issues without watchers kept: %v", got)
+	}
+}
+
+func TestApplyWatchedIssues(t *testing.T) {
+	tests := []struct {
+		name    string
+		watches map[string]issueWatch
+		current map[string]IssueItem
+		gone    []string
+		want    string
+	}{
+		{
+			name:    "status recorded",
+			watches: map[string]issueWatch{"MTV-1": {Status: "POST", Watchers: []string{"U1"}}},
+			current: map[string]IssueItem{"MTV-1": {Key: "MTV-1", Status: "ON_QA"}},
+			want:    "map[MTV-1:{ON_QA [U1]}]",
+		},
+		{
+			name:    "gone issue dropped",
+			watches: map[string]issueWatch{"MTV-1": {Status: "POST", Watchers: []string{"U1"}}, "MTV-2": {Status: "POST", Watchers: []string{"U1"}}},
+			current: map[string]IssueItem{"MTV-2": {Key: "MTV-2", Status: "POST"}},
+			gone:    []string{"MTV-1"},
+			want:    "map[MTV-2:{POST [U1]}]",
+		},
+		{
+			name: "moved issue follows its new key",
+			watches: map[string]issueWatch{
+				"MTV-1": {Status: "POST", Watchers: []string{"U1"}},
+				"OTH-9": {Status: "POST", Watchers: []string{"U1", "U2"}},
+			},
+			current: map[string]IssueItem{"MTV-1": {Key: "OTH-9", Status: "ON_QA"}},
+			want:    "map[OTH-9:{ON_QA [U1 U2]}]",
+		},
+		{
+			name:    "unsubscribed meanwhile",
+			watches: map[string]issueWatch{},
+			current: map[string]IssueItem{"MTV-1": {Key: "MTV-1", Status: "ON_QA"}},
+			want:    "map[]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			applyWatchedIssues(tt.watches, tt.current, tt.gone)
+			if got := fmt.Sprint(tt.watches); got != tt.want {
+				t.Errorf("watches = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNotifyWatchers(t *testing.T) {
+	t.Setenv("STATE_DIR", t.TempDir())
+	storeWatches(t, map[string]issueWatch{
+		"MTV-1": {Status: "POST", Watchers: []string{"U1", "U2"}},
+		"MTV-2": {Status: "ON_QA", Watchers: []string{"U1"}},
+		"MTV-3": {Status: "POST", Watchers: []string{"U2"}}, // Deleted in JIRA
+		"MTV-4": {Status: "POST", Watchers: []string{"U3"}}, // Moved to OTH-4
+	})
+	dms := watchSlackStub(t)
+	server, _ := watchJiraStub(t, map[string]watchedIssue{
+		"MTV-1": {status: "ON_QA"},
+		"MTV-2": {status: "ON_QA"},
+		"MTV-4": {status: "Verified", movedTo: "OTH-4"},
+	}, nil)
+
+	if err := notifyWatchers(server.URL, "token", "xoxb-test"); err != nil {
+		t.Fatalf("notifyWatchers: %v", err)
+	}
+
+	var users []string
+	for user := range dms {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+	if fmt.Sprint(users) != "[U1 U2 U3]" {
+		t.Fatalf("DMed %v, want U1, U2 and U3", users)
+	}
+	if !strings.Contains(dms["U1"], "MTV-1") || !strings.Contains(dms["U1"], "POST → ON") || strings.Contains(dms["U1"], "MTV-2") {
+		t.Errorf("U1 got %q, want only MTV-1's change", dms["U1"])
+	}
+	if !strings.Contains(dms["U3"], "OTH-4") {
+		t.Errorf("U3 got %q, want the moved issue's new key", dms["U3"])
+	}
+
+	want := "map[MTV-1:{ON_QA [U1 U2]} MTV-2:{ON_QA [U1]} OTH-4:{Verified [U3]}]"
+	if got := fmt.Sprint(storedWatches(t)); got != want {
+		t.Errorf("stored %s, want %s", got, want)
+	}
+}
+
+func TestNotifyWatchersKeepsConcurrentSubscriptions(t *testing.T) {
+	t.Setenv("STATE_DIR", t.TempDir())
+	storeWatches(t, map[string]issueWatch{"MTV-1": {Status: "POST", Watchers: []string{"U1"}}})
+	watchSlackStub(t)
+
+	// The server subscribes someone while the daily run is fetching
+	subscribed := false
+	server, _ := watchJiraStub(t, map[string]watchedIssue{"MTV-1": {status: "ON_QA"}}, func() {
+		if subscribed {
+			return
+		}
+		subscribed = true
+		err := updateWatches(func(watches map[string]issueWatch) {
+			watches["MTV-9"] = issueWatch{Status: "POST", Watchers: []string{"U9"}}
+		})
+		if err != nil {
+			t.Errorf("subscribing: %v", err)
+		}
+	})
+
+	if err := notifyWatchers(server.URL, "token", "xoxb-test"); err != nil {
+		t.Fatalf("notifyWatchers: %v", err)
+	}
+	want := "map[MTV-1:{ON_QA [U1]} MTV-9:{POST [U9]}]"
+	if got := fmt.Sprint(storedWatches(t)); got != want {
+		t.Errorf("stored %s, want %s", got, want)
+	}
+}
+
+func TestFetchWatchedIssuesFallsBackToSingleKeys(t *testing.T) {
+	server, searches := watchJiraStub(t, map[string]watchedIssue{
+		"MTV-1": {status: "POST"},
+		"MTV-3": {status: "ON_QA"},
+	}, nil)
+
+	current, gone, err := fetchWatchedIssues(server.URL, "token", []string{"MTV-1", "MTV-2", "MTV-3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(current) != 2 || current["MTV-3"].Status != "ON_QA" {
+		t.Errorf("current = %v", current)
+	}
+	if fmt.Sprint(gone) != "[MTV-2]" {
+		t.Errorf("gone = %v, want [MTV-2]", gone)
+	}
+	if *searches != 4 {
+		t.Errorf("%d searches, want the failed batch and one per key", *searches)
+	}
+}
@@ -0,0 +1,73 @@
+// Dynamic JIRA fields
+//
+// Some fields differ between JIRA instances (custom field IDs such as the Epic
+// Link), so their IDs are configured at runtime. JiraIssue keeps the raw JSON of
+// every returned field to support reading those without a fixed struct tag.
+package main
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// UnmarshalJSON decodes the issue normally and additionally keeps the raw fields map.
+func (i *JiraIssue) UnmarshalJSON(data []byte) error {
+	type plainIssue JiraIssue // Avoids recursing into this method
+	var issue plainIssue
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return err
+	}
+
+	var raw struct {
+		Fields map[string]json.RawMessage `json:"fields"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*i = JiraIssue(issue)
+	i.RawFields = raw.Fields
+	return nil
+}
+
+// customField returns the raw JSON of a field by ID, or nil if missing or null.
+func (i JiraIssue) customField(id string) json.RawMessage {
+	value, ok := i.RawFields[id]
+	if !ok || string(value) == "null" {
+		return nil
+	}
+	return value
+}
+
+// customFieldString reads a field that is either a plain string or an object
+// carrying the interesting part in "value", "key" or "name" (select lists,
+// issue pickers and similar custom field types).
+func (i JiraIssue) customFieldString(id string) string {
+	value := i.customField(id)
+	if value == nil {
+		return ""
+	}
+
+	var str string
+	if err := json.Unmarshal(value, &str); err == nil {
+		return strings.TrimSpace(str)
+	}
+
+	var obj struct {
+		Value string `json:"value"`
+		Key   string `json:"key"`
+		Name  string `json:"name"`
+	}
+	if err := json.Unmarshal(value, &obj); err == nil {
+		switch {
+		case obj.Value != "":
+			return strings.TrimSpace(obj.Value)
+		case obj.Key != "":
+			return strings.TrimSpace(obj.Key)
+		case obj.Name != "":
+			return strings.TrimSpace(obj.Name)
+		}
+	}
+
+	return ""
+}
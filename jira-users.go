@@ -0,0 +1,108 @@
+// JIRA user lookup
+//
+// When the slash command finds no issues, the name is looked up in JIRA's user
+// directory to tell a name that matches nobody apart from a person whose queue is
+// simply empty, so the reply doesn't ask people with nothing to do to check the
+// spelling of their name.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// userSearchLimit is how many users are requested per directory lookup.
+const userSearchLimit = 20
+
+// jiraDirectoryUser is an entry returned by /rest/api/3/user/search.
+type jiraDirectoryUser struct {
+	AccountID   string `json:"accountId"`
+	DisplayName string `json:"displayName"`
+	Active      bool   `json:"active"`
+}
+
+// findJiraUser returns the display name of an active JIRA user whose name contains
+// one of the variants of username (case-insensitive, as the issue matching does),
+// or "" if there's none.
+func findJiraUser(ctx context.Context, jiraURL, jiraToken, username string) (string, error) {
+	for _, variant := range userNameVariants(username) {
+		users, err := searchJiraUsers(ctx, jiraURL, jiraToken, variant)
+		if err != nil {
+			return "", err
+		}
+		variantLower := strings.ToLower(variant)
+		for _, user := range users {
+			if user.Active && strings.Contains(strings.ToLower(user.DisplayName), variantLower) {
+				return user.DisplayName, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// searchJiraUsers queries JIRA's user directory.
+func searchJiraUsers(ctx context.Context, jiraURL, jiraToken, query string) ([]jiraDirectoryUser, error) {
+	endpoint := fmt.Sprintf("%s/rest/api/3/user/search?query=%s&maxResults=%d", jiraURL, url.QueryEscape(query), userSearchLimit)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	setJiraAuth(req, jiraToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("JIRA API returned %d: %s", resp.StatusCode, redactSecrets(string(body)))
+	}
+
+	var users []jiraDirectoryUser
+	if err := json.Unmarshal(body, &users); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return users, nil
+}
+
+// noIssuesMessage returns the reply for a slash command without results, and whether
+// the person exists in JIRA (an empty queue rather than an error). Lookup failures
+// fall back to the generic message.
+func noIssuesMessage(ctx context.Context, jiraURL, jiraToken, username string, includeAll bool, statusFilter string) (string, bool) {
+	notFound := fmt.Sprintf("No issues found for: *%s*\n\nMake sure the name matches exactly as it appears in JIRA.", escapeSlackMrkdwn(username))
+
+	displayName, err := findJiraUser(ctx, jiraURL, jiraToken, username)
+	if err != nil {
+		ctxLogf(ctx, "   ⚠️  JIRA user lookup failed: %v\n", err)
+		return notFound, false
+	}
+	if displayName == "" {
+		ctxLogf(ctx, "   No JIRA user matches %q\n", username)
+		return fmt.Sprintf("No JIRA user matches: *%s*\n\nMake sure the name matches exactly as it appears in JIRA.", escapeSlackMrkdwn(username)), false
+	}
+
+	ctxLogf(ctx, "   %s exists in JIRA, nothing matched the query\n", displayName)
+	name := escapeSlackMrkdwn(displayName)
+	switch {
+	case statusFilter != "":
+		displayStatus := statusFilter
+		if statusFilter == "MODIFIED" {
+			displayStatus = "Modified"
+		}
+		return fmt.Sprintf("📭 *%s* has no *%s* issues updated in the last year.", name, escapeSlackMrkdwn(displayStatus)), true
+	case includeAll:
+		return fmt.Sprintf("📭 *%s* has no issues updated in the last year.", name), true
+	default:
+		return fmt.Sprintf("📭 *%s* has no open issues in the report's statuses right now.", name), true
+	}
+}
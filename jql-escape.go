@@ -0,0 +1,22 @@
+// JQL value quoting
+//
+// Any text that doesn't come from our own constants (Slack input, values read from
+// JIRA) must be quoted with sanitizeJQLValue before it is embedded in a JQL query,
+// so quotes or keywords in it can't change the query's structure.
+package main
+
+import "strings"
+
+// jqlValueEscaper escapes the characters that are special inside a quoted JQL string.
+var jqlValueEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\n", " ",
+	"\r", " ",
+	"\t", " ",
+)
+
+// sanitizeJQLValue returns s as a double-quoted JQL string literal.
+func sanitizeJQLValue(s string) string {
+	return `"` + jqlValueEscaper.Replace(s) + `"`
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeJQLValue(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "plain value", in: "ON_QA", want: `"ON_QA"`},
+		{name: "spaces and keywords stay inside the quotes", in: "In Progress OR project = X", want: `"In Progress OR project = X"`},
+		{name: "double quote", in: `Jane "JD" Doe`, want: `"Jane \"JD\" Doe"`},
+		{name: "backslash", in: `a\b`, want: `"a\\b"`},
+		{name: "backslash before a quote", in: `x\" OR 1=1`, want: `"x\\\" OR 1=1"`},
+		{name: "control whitespace becomes spaces", in: "a\nb\rc\td", want: `"a b c d"`},
+		{name: "empty", in: "", want: `""`},
+		{name: "unicode", in: "Zoë", want: `"Zoë"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeJQLValue(tt.in); got != tt.want {
+				t.Errorf("sanitizeJQLValue(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlashStatusFilterCantEscapeQuotes(t *testing.T) {
+	injection := `POST" OR project = SECRET OR status = "x`
+	jql := buildJQLQueryWithStatus("jdoe", false, injection)
+	if !strings.Contains(jql, `"POST\" OR project = SECRET OR status = \"x"`) {
+		t.Errorf("status filter wasn't quoted as one value: %s", jql)
+	}
+	if strings.Contains(jql, `"POST" OR`) {
+		t.Errorf("status filter closed the quoted string: %s", jql)
+	}
+}
@@ -0,0 +1,98 @@
+// JSON Lines output
+//
+// With -output jsonl the report is not posted to Slack. Instead every issue that
+// passes the report filters is written to stdout as one JSON object per line, as
+// soon as its page has been fetched, for ingestion into other systems. Log output
+// moves to stderr so stdout only carries the JSON lines.
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// outputSlack and outputJSONL are the values accepted by the -output flag.
+const (
+	outputSlack = "slack"
+	outputJSONL = "jsonl"
+)
+
+// jsonlIssue is the shape of one line of JSON Lines output.
+type jsonlIssue struct {
+	Key       string   `json:"key"`
+	Summary   string   `json:"summary"`
+	Status    string   `json:"status"`
+	Assignee  string   `json:"assignee"`
+	QAContact string   `json:"qa_contact"`
+	PRs       []string `json:"prs"`
+	Labels    []string `json:"labels"`
+}
+
+// runJSONLReport fetches the report's issues and streams the filtered ones to stdout.
+func runJSONLReport(mode reportMode) {
+	// Keep stdout for data only: everything logged goes to stderr from here on
+	out := os.Stdout
+	os.Stdout = os.Stderr
+
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	if jiraURL == "" || jiraToken == "" {
+		logln("❌ Missing required credentials")
+		logln("Please set environment variables: JIRA_URL, JIRA_TOKEN")
+		os.Exit(1)
+	}
+
+	jql := reportQuery(mode, jiraURL, jiraToken)
+	written := 0
+	fetched, err := streamJiraIssues(jiraURL, jiraToken, jql, reportFetchOptions(mode), func(page JiraSearchResponse) error {
+		n, err := writeJSONLIssues(out, []JiraSearchResponse{page}, mode)
+		written += n
+		return err
+	})
+	if err != nil {
+		logf("❌ Failed to write JSON Lines output: %v\n", err)
+		os.Exit(1)
+	}
+
+	logf("✅ Wrote %d of %d issues as JSON Lines\n", written, fetched.Issues)
+}
+
+// writeJSONLIssues writes one JSON object per issue that passes the report filters.
+// Returns how many lines were written.
+func writeJSONLIssues(w io.Writer, responses []JiraSearchResponse, mode reportMode) (int, error) {
+	encoder := json.NewEncoder(w)
+	written := 0
+
+	for _, resp := range responses {
+		for _, issue := range resp.Issues {
+			if !includeInReport(issue, mode) {
+				continue
+			}
+
+			line := jsonlIssue{
+				Key:     issue.Key,
+				Summary: issue.Fields.Summary,
+				Status:  issue.Fields.Status.Name,
+				PRs:     extractPRs(issue.Fields.GitPullRequest),
+				Labels:  issue.Fields.Labels,
+			}
+			if issue.Fields.Assignee != nil {
+				line.Assignee = issue.Fields.Assignee.DisplayName
+			}
+			if issue.Fields.QAContact != nil {
+				line.QAContact = issue.Fields.QAContact.DisplayName
+			}
+			if line.PRs == nil {
+				line.PRs = []string{}
+			}
+
+			if err := encoder.Encode(line); err != nil {
+				return written, err
+			}
+			written++
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,82 @@
+// Report legend
+//
+// With SHOW_LEGEND=true the report header carries a short legend explaining the
+// icons and statuses used in the thread. It is built from the features enabled for
+// the run and the statuses actually present, so it only explains what readers see.
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// statusDescriptions explains the workflow statuses that show up in the reports.
+var statusDescriptions = map[string]string{
+	"In Progress": "being worked on",
+	"MODIFIED":    "fix merged, waiting for a build",
+	"POST":        "PR posted, waiting for review/merge",
+	"ON_QA":       "build available, waiting for QA verification",
+	"Open":        "not started yet",
+	"Closed":      "done",
+	"Archived":    "archived",
+}
+
+// buildLegendBlocks creates the legend context block, or nil unless SHOW_LEGEND=true.
+func buildLegendBlocks(mode reportMode, personGroups []PersonStatusGroup) []map[string]interface{} {
+	if !envBool("SHOW_LEGEND", false) {
+		return nil
+	}
+
+	lines := []string{
+		"*Legend*",
+		"👤 person (issue count)  ·  📂 status (issue count)",
+		"PR1, PR2… link to the issue's pull requests, – means none",
+	}
+	if mode == modeInProgress {
+		lines = append(lines, "⏳ time in the current status  ·  ❌ none yet: no PR linked")
+	} else {
+		lines = append(lines, "*Severity:* shown for bugs")
+	}
+	if envBool("SHOW_OWNERSHIP_AGE", false) {
+		lines = append(lines, "(owned 4d): time since the issue was assigned to its current owner")
+	}
+	if envBool("SHOW_EPIC", false) {
+		lines = append(lines, "📎 epic the issue belongs to")
+	}
+	if envBool("SHOW_WATCHERS", false) {
+		lines = append(lines, "👀 number of people watching the issue")
+	}
+	if envBool("FLAG_MISSING_PR", false) {
+		lines = append(lines, missingPRBadge+": the issue's status calls for a PR, but none is linked")
+	}
+
+	for _, status := range orderedStatuses(personGroups) {
+		if description, ok := statusDescriptions[status]; ok {
+			lines = append(lines, fmt.Sprintf("*%s*: %s", escapeSlackMrkdwn(status), description))
+		}
+	}
+
+	return []map[string]interface{}{
+		{
+			"type": "context",
+			"elements": []map[string]string{
+				{
+					"type": "mrkdwn",
+					"text": strings.Join(lines, "\n"),
+				},
+			},
+		},
+	}
+}
+
+// orderedStatuses returns the statuses present in the groups, in thread order.
+func orderedStatuses(personGroups []PersonStatusGroup) []string {
+	present := make(map[string]bool)
+	for _, group := range personGroups {
+		for status := range group.StatusGroups {
+			present[status] = true
+		}
+	}
+
+	return statusesInOrder(present, dailyStatusOrder)
+}
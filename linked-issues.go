@@ -0,0 +1,55 @@
+// Linked issue count
+//
+// With SHOW_LINKS=true each issue shows how many issue links it has ("(🔗 2)")
+// after its summary, as a quick signal of how entangled it is: blockers, clones,
+// duplicates and plain relations all count. Issues without links show nothing.
+// The issuelinks field is fetched only when this (or CUSTOMER_BUG_PROJECTS) is on.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// linkCountEnabled reports whether SHOW_LINKS is on.
+func linkCountEnabled() bool {
+	return envBool("SHOW_LINKS", false)
+}
+
+// issueLinksNeeded reports whether the issuelinks field is needed for this run.
+func issueLinksNeeded() bool {
+	return linkCountEnabled() || customerBugsEnabled()
+}
+
+// issueLinkCount returns the number of links to other issues, or 0 when
+// SHOW_LINKS is off or the issuelinks field wasn't fetched. Entries that aren't
+// links to an issue are skipped rather than failing the whole field.
+func issueLinkCount(issue JiraIssue) int {
+	value := issue.customField("issuelinks")
+	if !linkCountEnabled() || value == nil {
+		return 0
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(value, &entries); err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		var link jiraIssueLink
+		if err := json.Unmarshal(entry, &link); err != nil {
+			continue
+		}
+		if key, _ := link.linkedIssue(); key != "" {
+			count++
+		}
+	}
+	return count
+}
+
+// linkCountSuffix renders the link count after an issue's summary, or "" if it has none.
+func linkCountSuffix(issue IssueItem) string {
+	if issue.Links == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (🔗 %d)", issue.Links)
+}
@@ -0,0 +1,53 @@
+// Local time indicator
+//
+// For distributed teams, SHOW_LOCAL_TIME=true annotates each person header with
+// the person's current local time, "🕐 14:32 local" during their workday
+// (Mon-Fri, 08:00-18:00) and "🌙 22:10 local" outside it. The timezone comes from
+// USER_TIMEZONE_MAP (comma-separated "JIRA Name=Europe/Prague" pairs) or else the
+// JIRA user's profile. People without a known timezone get no annotation, and
+// historical reports never show one.
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embedded so IANA names resolve in minimal images without zoneinfo
+	_ "time/tzdata"
+)
+
+// Local working hours used for the workday indicator.
+const (
+	workdayStartHour = 8
+	workdayEndHour   = 18
+)
+
+// personTimeZone returns the configured timezone for the person, or profileZone.
+func personTimeZone(person, profileZone string) string {
+	for _, entry := range envList("USER_TIMEZONE_MAP") {
+		name, zone, ok := strings.Cut(entry, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), person) {
+			return strings.TrimSpace(zone)
+		}
+	}
+	return profileZone
+}
+
+// localTimeSuffix returns the person header's local time annotation, or "".
+func localTimeSuffix(group PersonStatusGroup) string {
+	if !envBool("SHOW_LOCAL_TIME", false) || group.TimeZone == "" || !asOfTime.IsZero() {
+		return ""
+	}
+	loc, err := time.LoadLocation(group.TimeZone)
+	if err != nil {
+		return ""
+	}
+
+	now := time.Now().In(loc)
+	icon := "🕐"
+	if weekday := now.Weekday(); weekday == time.Saturday || weekday == time.Sunday || now.Hour() < workdayStartHour || now.Hour() >= workdayEndHour {
+		icon = "🌙"
+	}
+	return fmt.Sprintf("  %s %s local", icon, now.Format("15:04"))
+}
@@ -0,0 +1,53 @@
+// Logging
+//
+// All output goes through logf/logln. Secrets are redacted (see redact.go), and
+// with ENVIRONMENT set (e.g. "staging") every line is prefixed with it, so the
+// logs of several instances are easy to tell apart. Production leaves it empty.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// environmentPrefix returns "[<ENVIRONMENT>] ", or "" when ENVIRONMENT is unset.
+func environmentPrefix() string {
+	if env := strings.TrimSpace(os.Getenv("ENVIRONMENT")); env != "" {
+		return "[" + env + "] "
+	}
+	return ""
+}
+
+// logf formats and prints a log message with secrets redacted.
+func logf(format string, args ...interface{}) {
+	writeLog("", fmt.Sprintf(format, args...))
+}
+
+// logln prints a log line with secrets redacted.
+func logln(args ...interface{}) {
+	writeLog("", fmt.Sprintln(args...))
+}
+
+// ctxLogf is logf for request handling: lines also carry the request ID of ctx.
+func ctxLogf(ctx context.Context, format string, args ...interface{}) {
+	writeLog(requestIDPrefix(ctx), fmt.Sprintf(format, args...))
+}
+
+// writeLog redacts the message, prefixes each non-empty line with the environment
+// and the given prefix, and writes it to stdout (which the JSON Lines output
+// redirects to stderr).
+func writeLog(prefix, message string) {
+	message = redactSecrets(message)
+	if prefix = environmentPrefix() + prefix; prefix != "" {
+		lines := strings.Split(message, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = prefix + line
+			}
+		}
+		message = strings.Join(lines, "\n")
+	}
+	fmt.Fprint(os.Stdout, message)
+}
@@ -11,13 +11,16 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
@@ -25,12 +28,12 @@ import (
 
 // Filtering configuration - add or remove items to customize what issues are excluded from reports
 var (
-	// Components to exclude from the report (case-sensitive)
+	// Components to exclude from the report (case-sensitive, glob patterns like "ui-*" allowed)
 	excludedComponents = []string{
 		"User Interface",
 	}
 
-	// Labels to exclude from the report (case-sensitive)
+	// Labels to exclude from the report (case-sensitive, glob patterns like "mtv-*-offload" allowed)
 	excludedLabels = []string{
 		"user-interface",
 		"mtv-storage-offload",
@@ -38,36 +41,96 @@ var (
 	}
 )
 
+// verboseLogging enables extra diagnostic output (set by the -verbose flag)
+var verboseLogging bool
+
 // JiraSearchResponse represents the response from JIRA's /rest/api/3/search/jql API.
 // It contains a list of issues with their relevant fields.
 type JiraSearchResponse struct {
-	NextPageToken string `json:"nextPageToken,omitempty"`
-	Issues        []struct {
-		Key    string `json:"key"`
-		Fields struct {
-			Summary string `json:"summary"`
-			Status  struct {
-				Name string `json:"name"`
-			} `json:"status"`
-			Assignee *struct {
-				DisplayName string `json:"displayName"`
-			} `json:"assignee"`
-			// QAContact maps to customfield_12315948 in Red Hat JIRA
-			QAContact *struct {
-				DisplayName string `json:"displayName"`
-			} `json:"customfield_12315948"`
-			IssueType struct {
-				Name string `json:"name"`
-			} `json:"issuetype"`
-			Components []struct {
-				Name string `json:"name"`
-			} `json:"components"`
-			Labels []string `json:"labels"`
-			// GitPullRequest maps to customfield_12310220 in Red Hat JIRA
-			// Can be either a string or an array of strings
-			GitPullRequest interface{} `json:"customfield_12310220"`
-		} `json:"fields"`
-	} `json:"issues"`
+	NextPageToken string      `json:"nextPageToken,omitempty"`
+	Issues        []JiraIssue `json:"issues"`
+}
+
+// JiraIssue represents a single issue in a JIRA search response.
+type JiraIssue struct {
+	Key    string `json:"key"`
+	Fields struct {
+		Summary string `json:"summary"`
+		Status  struct {
+			Name string `json:"name"`
+		} `json:"status"`
+		Assignee *struct {
+			DisplayName string `json:"displayName"`
+			AccountID   string `json:"accountId"`
+			Name        string `json:"name"` // Username on JIRA Server, for profile links
+			TimeZone    string `json:"timeZone"`
+			// EmailAddress is only returned when the token may see it
+			EmailAddress string `json:"emailAddress"`
+		} `json:"assignee"`
+		// QAContact maps to customfield_12315948 in Red Hat JIRA
+		QAContact *struct {
+			DisplayName string `json:"displayName"`
+			AccountID   string `json:"accountId"`
+			Name        string `json:"name"` // Username on JIRA Server, for profile links
+			TimeZone    string `json:"timeZone"`
+			// EmailAddress is only returned when the token may see it
+			EmailAddress string `json:"emailAddress"`
+		} `json:"customfield_12315948"`
+		IssueType struct {
+			Name string `json:"name"`
+		} `json:"issuetype"`
+		Priority *struct {
+			Name string `json:"name"`
+		} `json:"priority"`
+		// Resolution is null while the issue is unresolved
+		Resolution *struct {
+			Name string `json:"name"`
+		} `json:"resolution"`
+		Components []struct {
+			Name string `json:"name"`
+		} `json:"components"`
+		Labels []string `json:"labels"`
+		// GitPullRequest maps to customfield_12310220 in Red Hat JIRA
+		// Can be either a string or an array of strings
+		GitPullRequest interface{} `json:"customfield_12310220"`
+		Created        string      `json:"created"`
+		Updated        string      `json:"updated"`
+		ResolutionDate string      `json:"resolutiondate"`
+		// Description is only requested with SHOW_DESCRIPTION; Atlassian Document
+		// Format (a JSON object) from the v3 API, or a wiki markup string
+		Description interface{} `json:"description"`
+		// Watches is only populated when the watches field is requested
+		Watches *struct {
+			WatchCount int  `json:"watchCount"`
+			IsWatching bool `json:"isWatching"`
+		} `json:"watches"`
+	} `json:"fields"`
+	// Changelog is only populated when the search is made with expand=changelog
+	Changelog *JiraChangelog `json:"changelog,omitempty"`
+	// RawFields keeps every returned field so instance-specific custom fields
+	// (configured by ID at runtime) can be read without a struct tag
+	RawFields map[string]json.RawMessage `json:"-"`
+}
+
+// JiraChangelog holds the history entries of an issue.
+type JiraChangelog struct {
+	MaxResults int                 `json:"maxResults"`
+	Total      int                 `json:"total"`
+	Histories  []JiraChangeHistory `json:"histories"`
+}
+
+// JiraChangeHistory is a single changelog entry, which may touch several fields at once.
+type JiraChangeHistory struct {
+	Created string           `json:"created"`
+	Items   []JiraChangeItem `json:"items"`
+}
+
+// JiraChangeItem describes the change of one field within a changelog entry.
+type JiraChangeItem struct {
+	Field      string `json:"field"`
+	FieldID    string `json:"fieldId"`
+	FromString string `json:"fromString"`
+	ToString   string `json:"toString"`
 }
 
 // IssueItem represents a simplified JIRA issue used for grouping and display.
@@ -76,12 +139,158 @@ type IssueItem struct {
 	Summary        string
 	Status         string
 	GitPullRequest []string
+	StatusSince    time.Time // When the issue entered its current status (zero if unknown)
+	EpicKey        string    // Key of the epic the issue rolls up to (empty if none)
+	EpicSummary    string    // Summary of the epic, resolved when SHOW_EPIC=true
+	IssueType      string
+	Severity       string    // Severity of Bug-type issues (empty otherwise)
+	Resolution     string    // Resolution name (empty while unresolved)
+	AssignedSince  time.Time // When the issue was assigned to its current assignee (zero if unknown)
+	Watchers       int       // Number of watchers, when SHOW_WATCHERS or SORT_BY=watchers is set
+	Description    string    // Plain-text description preview, when SHOW_DESCRIPTION is set
+	Priority       string    // Priority name (empty if none)
+	Updated        time.Time // When the issue was last updated (zero unless the updated field was fetched)
+	Resolved       time.Time // When the issue was resolved (zero unless resolved and the resolutiondate field was fetched)
+	Sprint         string    // Name of the active sprint (empty unless the sprint field was fetched)
+	CustomerBugs   int       // Linked customer bugs, when CUSTOMER_BUG_PROJECTS is set
+	Links          int       // Issue links, when SHOW_LINKS is set
+	Workstreams    []string  // Matching WORKSTREAMS in config order; the first one is where it's shown
+}
+
+// issueUpdated returns when the issue was last updated, or zero if unknown.
+func issueUpdated(issue JiraIssue) time.Time {
+	updated, err := time.Parse(jiraTimeLayout, issue.Fields.Updated)
+	if err != nil {
+		return time.Time{}
+	}
+	return updated
+}
+
+// newIssueItem converts a raw JIRA issue into the simplified form used for grouping and display.
+func newIssueItem(issue JiraIssue) IssueItem {
+	return IssueItem{
+		Key:            issue.Key,
+		Summary:        issue.Fields.Summary,
+		Status:         issue.Fields.Status.Name,
+		GitPullRequest: orderPRLinks(extractPRs(issue.Fields.GitPullRequest)),
+		StatusSince:    statusSince(issue),
+		EpicKey:        issueEpicKey(issue),
+		IssueType:      issue.Fields.IssueType.Name,
+		Severity:       issueSeverity(issue),
+		Resolution:     issueResolution(issue),
+		AssignedSince:  assignedSince(issue),
+		Watchers:       issueWatchers(issue),
+		Description:    descriptionPreview(issue),
+		Priority:       issuePriority(issue),
+		Updated:        issueUpdated(issue),
+		Resolved:       issueResolved(issue),
+		Sprint:         issueSprint(issue),
+		CustomerBugs:   issueCustomerBugs(issue),
+		Links:          issueLinkCount(issue),
+		Workstreams:    issueWorkstreams(issue.Fields.Labels),
+	}
 }
 
 func main() {
 	// Command-line flags
 	serverMode := flag.Bool("server", false, "Run as slash command server instead of daily report")
+	validateJQLOnly := flag.Bool("validate-jql", false, "Validate the report JQL against JIRA, print the match count and exit")
+	filterStats := flag.Bool("filter-stats", false, "Fetch the report's issues without posting and print how many each filter rule removed")
+	output := flag.String("output", outputSlack, "Output format: slack (post the report) or jsonl (one JSON issue per line on stdout)")
+	verbose := flag.Bool("verbose", false, "Print extra diagnostics such as the run timing breakdown")
+	mode := flag.String("mode", string(modeQA), "Report mode: qa (POST/ON_QA/MODIFIED by QA contact) or inprogress (In Progress by assignee)")
+	asOf := flag.String("as-of", "", "Generate the report as of a past time (e.g. 2024-05-02T06:00), reconstructed from the changelog")
+	flag.BoolVar(&updateRun, "update", false, "Update the thread of the last posted report, rewriting only changed person sections")
+	flag.BoolVar(&skipPreflight, "skip-preflight", false, "Don't check that the bot can post to the report channel before fetching (for tokens without channels:read)")
+	retryFrom := flag.String("retry-from", "", "Post only what a failed run couldn't deliver, from the failure manifest it wrote (e.g. failed-delivery.json)")
+	minSeverity := flag.String("min-severity", "", "Hide bugs ranked below this severity in SEVERITY_ORDER (e.g. High), counting them in the footer")
+	flag.StringVar(&fromFile, "from-file", "", "Build the report from a -dump-fetch file (or a raw JIRA search response) instead of querying JIRA")
+	dumpFetch := flag.String("dump-fetch", "", "Save the fetched JIRA search responses to this file, for use with -from-file")
+	flag.BoolVar(&dryRun, "dry-run", false, "Print the Slack messages as JSON instead of posting them")
+	flag.BoolVar(&strictFields, "strict-fields", false, "Fail instead of warning when JIRA lacks a custom field the report reads")
+	flag.StringVar(&onlyPerson, "person", "", "Post only this person's section of the report as a single message (partial names match, like /issues)")
+	dumpRaw := flag.String("dump-raw", "", "Write the raw JIRA search responses to this file (JSON Lines) for debugging")
+	flag.StringVar(&groupByOverride, "group-by", "", "Organize the thread by person, team or workstream (overrides GROUP_BY)")
+	flag.BoolVar(&jsonOutput, "json-output", false, "Log to stderr and write one JSON document with the run's results to stdout at the end")
 	flag.Parse()
+	verboseLogging = *verbose
+
+	if jsonOutput {
+		startRunResult()
+		if *serverMode || *validateJQLOnly || *filterStats || *output != outputSlack || *retryFrom != "" || dryRun {
+			logln("❌ -json-output is only supported for the Slack report, without -dry-run")
+			exitRun(1, errUsage)
+		}
+	}
+
+	if *dumpRaw != "" {
+		if err := openRawDump(*dumpRaw); err != nil {
+			logf("❌ %v\n", err)
+			exitRun(1, err)
+		}
+		// Writes aren't buffered, so runs ending in os.Exit lose nothing
+		defer closeRawDump()
+		logf("🔍 Writing raw JIRA responses to %s\n", *dumpRaw)
+	}
+
+	if err := compileExclusions(); err != nil {
+		logf("❌ Invalid exclusion configuration: %v\n", err)
+		exitRun(1, err)
+	}
+
+	var err error
+	activePriorityFilter, err = loadPriorityFilter()
+	if err != nil {
+		logf("❌ Invalid priority configuration: %v\n", err)
+		exitRun(1, err)
+	}
+
+	activeSeverityFilter, err = loadSeverityFilter(*minSeverity)
+	if err != nil {
+		logf("❌ Invalid severity configuration: %v\n", err)
+		exitRun(1, err)
+	}
+
+	activeTeamMap, err = loadTeamMap()
+	if err == nil {
+		activeTeamChannelMap, err = loadTeamChannelMap()
+	}
+	if err != nil {
+		logf("❌ Invalid team configuration: %v\n", err)
+		exitRun(1, err)
+	}
+
+	activeProjectURLMap, err = loadProjectURLMap()
+	if err == nil {
+		activeStatusAliases, err = loadStatusAliases()
+	}
+	if err != nil {
+		logf("❌ %v\n", err)
+		exitRun(1, err)
+	}
+
+	activeWorkstreams, err = loadWorkstreams()
+	if err == nil && groupByWorkstream() && len(activeWorkstreams) == 0 {
+		err = fmt.Errorf("grouping by workstream needs WORKSTREAMS")
+	}
+	if err != nil {
+		logf("❌ %v\n", err)
+		exitRun(1, err)
+	}
+
+	activeIssueTemplate, err = loadIssueTemplate()
+	if err == nil {
+		activeShadowRender, err = loadShadowFlags()
+	}
+	if err != nil {
+		logf("❌ %v\n", err)
+		exitRun(1, err)
+	}
+
+	if err := validateParentThread(); err != nil {
+		logf("❌ %v\n", err)
+		exitRun(1, err)
+	}
 
 	// Server mode: Start HTTP server for slash commands
 	if *serverMode {
@@ -89,77 +298,413 @@ func main() {
 		return
 	}
 
+	if *retryFrom != "" {
+		runRetry(*retryFrom)
+		return
+	}
+
 	// Daily report mode: Run once and exit
-	runDailyReport()
+	reportMode, err := parseReportMode(*mode)
+	if err != nil {
+		logf("❌ %v\n", err)
+		exitRun(1, err)
+	}
+
+	if *asOf != "" {
+		asOfTime, err = parseAsOf(*asOf)
+		if err != nil {
+			logf("❌ %v\n", err)
+			exitRun(1, err)
+		}
+		if *validateJQLOnly || *filterStats || *output != outputSlack || updateRun {
+			logln("❌ -as-of is only supported for the Slack report, without -update")
+			exitRun(1, errUsage)
+		}
+	}
+
+	if fromFile != "" || *dumpFetch != "" || dryRun {
+		if *validateJQLOnly || *filterStats || *output != outputSlack || updateRun || !asOfTime.IsZero() {
+			logln("❌ -from-file, -dump-fetch and -dry-run are only supported for the Slack report, without -update or -as-of")
+			exitRun(1, errUsage)
+		}
+		if fromFile != "" && *dumpFetch != "" {
+			logln("❌ -from-file and -dump-fetch can't be combined")
+			exitRun(1, errUsage)
+		}
+		fetchDumpPath = *dumpFetch
+		if dryRun {
+			// Keep stdout for the messages only
+			dryRunOutput = os.Stdout
+			os.Stdout = os.Stderr
+		}
+	}
+
+	if onlyPerson != "" && (*validateJQLOnly || *filterStats || *output != outputSlack || updateRun) {
+		logln("❌ -person is only supported for the Slack report, without -update")
+		exitRun(1, errUsage)
+	}
+
+	if *validateJQLOnly {
+		runValidateJQL(reportMode)
+		return
+	}
+
+	if *filterStats {
+		runFilterStats(reportMode)
+		return
+	}
+
+	switch *output {
+	case outputSlack:
+	case outputJSONL:
+		runJSONLReport(reportMode)
+		return
+	default:
+		logf("❌ Unknown output format %q (valid formats: %s, %s)\n", *output, outputSlack, outputJSONL)
+		exitRun(1, errUsage)
+	}
+
+	runDailyReport(reportMode)
 }
 
-// runDailyReport executes the daily JIRA report and sends to Slack
-func runDailyReport() {
-	// Configuration: Load from environment variables or use defaults
+// runDailyReport executes the daily JIRA report for the given mode and sends to Slack
+func runDailyReport(mode reportMode) {
+	if activeRunResult != nil {
+		activeRunResult.Mode = mode
+	}
+
+	// Days without a report (weekends, holidays) end successfully without posting
+	skip, err := reportRunGate(mode)
+	switch {
+	case errors.Is(err, errMissingCredentials):
+		logln("❌ Missing required credentials")
+		logln("Please set environment variables: JIRA_URL, JIRA_TOKEN, SLACK_BOT_TOKEN, SLACK_CHANNEL")
+		exitRun(1, err)
+	case err != nil:
+		logf("❌ %v\n", err)
+		exitRun(1, err)
+	case skip != "":
+		logf("⏭️  Skipping the %s report: %s\n", mode, skip)
+		if activeRunResult != nil {
+			activeRunResult.Skipped = skip
+		}
+		activeRunResult.emit(0, nil)
+		return
+	}
+
 	jiraURL := os.Getenv("JIRA_URL")
 	jiraToken := os.Getenv("JIRA_TOKEN")
 	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
-	slackChannel := os.Getenv("SLACK_CHANNEL")
+	stats := newRunStats()
+	if activeRunResult != nil {
+		activeRunResult.Timing = stats
+	}
+
+	err = sendReport(mode, stats)
+	code := reportExitCode(err)
+	if code != 0 && code != exitDegraded {
+		logf("❌ %v\n", err)
+		exitRun(code, err)
+	}
 
-	// Validate required credentials
-	if jiraURL == "" || jiraToken == "" || slackBotToken == "" || slackChannel == "" {
-		fmt.Println("❌ Missing required credentials")
-		fmt.Println("Please set environment variables: JIRA_URL, JIRA_TOKEN, SLACK_BOT_TOKEN, SLACK_CHANNEL")
-		os.Exit(1)
+	// Watchers hear about status changes once the report is out
+	if asOfTime.IsZero() && !dryRun && onlyPerson == "" {
+		if err := notifyWatchers(jiraURL, jiraToken, slackBotToken); err != nil {
+			logf("⚠️  Failed to notify watchers: %v\n", err)
+		}
 	}
 
-	// JQL Query fetches:
-	// 1. Issues with status: POST, ON_QA, or MODIFIED
-	// 2. Epics that are not Closed (will be filtered for PRs later)
-	// Excludes UI-related issues (filtered in code)
-	jql := `project = MTV AND updated >= -365d AND (status IN (POST, ON_QA, MODIFIED) OR (type = Epic AND status != Closed)) ORDER BY assignee`
+	stats.finish()
+	if verboseLogging {
+		stats.print()
+	}
 
-	issues, err := fetchJiraIssues(jiraURL, jiraToken, jql)
+	// Delivered, but not where it should be: exit non-zero so the scheduler notices
+	if code == exitDegraded {
+		logf("⚠️  %v\n", err)
+		exitRun(code, err)
+	}
+	activeRunResult.emit(0, nil)
+}
+
+// exitDegraded is the exit code of a report delivered only partly or to the
+// fallback channel.
+const exitDegraded = 2
+
+// reportExitCode returns the exit code of a report run that ended with err.
+func reportExitCode(err error) int {
+	var notFound *personNotFoundError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &notFound):
+		return exitPersonNotFound
+	case errors.Is(err, errDegradedDelivery), errors.Is(err, errPartialDelivery):
+		return exitDegraded
+	}
+	return 1
+}
+
+// errMissingCredentials reports that the JIRA or Slack settings a run needs are unset.
+var errMissingCredentials = errors.New("missing required credentials")
+
+// reportRunGate runs the checks deciding whether a report run goes ahead, for
+// scheduled runs and /report/run alike. It returns the reason when no report is
+// due (weekends, SKIP_DATES), or an error when the credentials are missing, the
+// channel preflight fails or JIRA lacks a field with -strict-fields.
+func reportRunGate(mode reportMode) (string, error) {
+	if asOfTime.IsZero() && !dryRun && onlyPerson == "" {
+		if reason := skipReason(time.Now()); reason != "" {
+			return reason, nil
+		}
+	}
+
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
+	slackChannel := reportChannel(mode)
+
+	// Offline runs need neither JIRA nor Slack access
+	missingJira := fromFile == "" && (jiraURL == "" || jiraToken == "")
+	missingSlack := !dryRun && (slackBotToken == "" || slackChannel == "")
+	if missingJira || missingSlack {
+		return "", errMissingCredentials
+	}
+
+	logf("📋 Running %s report\n", mode)
+	if !dryRun {
+		if err := runPreflight(slackBotToken, slackChannel); err != nil {
+			return "", err
+		}
+	}
+	if fromFile == "" {
+		if err := checkJiraFields(jiraURL, jiraToken, mode); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+// sendReport fetches, groups and posts the report for the mode to its Slack channel.
+// Credentials are read from the environment and assumed to be validated by the caller.
+func sendReport(mode reportMode, stats *runStats) error {
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
+	slackChannel := reportChannel(mode)
+
+	// People are looked up in Slack while the pages are still arriving
+	if slackLookupByEmailEnabled() && slackBotToken != "" {
+		activeSlackUsers = newSlackUserDirectory(slackBotToken)
+		defer activeSlackUsers.save()
+	}
+
+	// Issues are grouped page by page as they arrive so raw responses aren't kept around
+	grouper := newPersonGrouper(mode)
+	var grouping time.Duration
+
+	fetchStart := time.Now()
+	var jql string
+	var opts fetchOptions
+	var dump fetchDump
+	switch {
+	case fromFile != "":
+		var err error
+		if dump, err = loadFetchDump(fromFile, mode); err != nil {
+			return err
+		}
+		jql = dump.JQL
+		logf("📂 Loading issues from %s instead of JIRA\n", fromFile)
+	case asOfTime.IsZero():
+		jql = reportQuery(mode, jiraURL, jiraToken)
+		opts = reportFetchOptions(mode)
+	default:
+		jql, opts = asOfQuery(mode, asOfTime, jiraURL, jiraToken)
+	}
+	handlePage := func(page JiraSearchResponse) error {
+		if !asOfTime.IsZero() {
+			reconstructPage(jiraURL, jiraToken, &page, mode, asOfTime)
+		}
+		pageStart := time.Now()
+		grouper.add(page)
+		grouping += time.Since(pageStart)
+		return nil
+	}
+
+	var fetched fetchStats
+	var err error
+	if fromFile != "" {
+		fetched, err = replayFetchDump(dump, handlePage)
+	} else {
+		if fetchDumpPath != "" {
+			startFetchCapture()
+		}
+		fetched, err = streamJiraIssues(jiraURL, jiraToken, jql, opts, handlePage)
+		if fetchDumpPath != "" {
+			pages := stopFetchCapture()
+			if err == nil {
+				if err := writeFetchDump(fetchDumpPath, mode, jql, pages); err != nil {
+					logf("⚠️  %v\n", err)
+				} else {
+					logf("💾 Saved %d page(s) of JIRA responses to %s\n", len(pages), fetchDumpPath)
+				}
+			}
+		}
+	}
 	if err != nil {
-		fmt.Printf("❌ Failed to fetch JIRA issues: %v\n", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to fetch JIRA issues: %w", err)
 	}
+	stats.JiraFetchMS = (time.Since(fetchStart) - grouping).Milliseconds()
+	stats.JiraPages = fetched.Pages
 
-	fmt.Printf("📊 Fetched %d total issues from JIRA\n", countTotalIssues(issues))
+	logf("📊 Fetched %d total issues from JIRA (%d epics, %d kept)\n", fetched.Issues, grouper.epicsFetched, grouper.epicsKept)
 
 	// Group issues by person and status
-	personStatusGroups := buildPersonStatusGroups(issues)
+	groupingStart := time.Now()
+	personStatusGroups := grouper.groups()
+	stats.GroupingMS = (time.Since(groupingStart) + grouping).Milliseconds()
+	logFilterMetrics(grouper.filterMetrics())
+	activeRunResult.recordCounts(grouper.filterMetrics(), personStatusGroups)
+	if activeSeverityFilter.ranks != nil {
+		hiddenBugs = grouper.removed[activeSeverityFilter.reason()]
+	}
 
-	// Send messages as a thread
-	fmt.Printf("📤 Sending report to Slack at %s...\n", time.Now().Format("15:04:05"))
+	if envBool("SHOW_EPIC", false) && fromFile == "" {
+		resolveEpicSummaries(jiraURL, jiraToken, personStatusGroups)
+	}
 
-	// Send header as main message to create the thread
 	date := time.Now().Format("Jan 2, 2006")
-	headerBlocks := []map[string]interface{}{
-		{"type": "header", "text": map[string]string{"type": "plain_text", "text": "🧾 Daily JIRA Summary — " + date}},
-		{"type": "divider"},
+	if !asOfTime.IsZero() {
+		date = asOfTime.Format("Jan 2, 2006 15:04") + " (historical)"
 	}
 
-	fmt.Printf("   Creating thread with header...\n")
-	threadTS, err := sendToSlackAPI(slackBotToken, slackChannel, "", headerBlocks)
-	if err != nil {
-		fmt.Printf("❌ Failed to send initial message: %v\n", err)
-		os.Exit(1)
+	if onlyPerson != "" {
+		return sendPersonReport(mode, jiraURL, slackBotToken, slackChannel, date, personStatusGroups, stats)
+	}
+
+	inPlace := !updateRun && updateInPlace()
+	if (updateRun || inPlace) && len(activeTeamChannelMap) > 0 {
+		logln("⚠️  Updating in place isn't supported with TEAM_CHANNEL_MAP, posting new threads")
+	} else if (updateRun || inPlace) && groupByWorkstream() {
+		logln("⚠️  Updating in place isn't supported with workstream grouping, posting a new thread")
+	} else if (updateRun || inPlace) && asOfTime.IsZero() {
+		manifests, err := loadReportManifests()
+		if err != nil {
+			logf("⚠️  %v\n", err)
+		}
+		previous, ok := manifests[string(mode)]
+		switch {
+		case !ok || previous.ThreadTS == "":
+			logf("⚠️  No previous %s report to update, posting a new one\n", mode)
+		case inPlace && !postedToday(previous, time.Now()):
+			logf("📝 The last %s report is from an earlier day, posting today's\n", mode)
+		default:
+			return updateReport(previous, jiraURL, slackBotToken, personStatusGroups, mode, stats)
+		}
 	}
-	fmt.Printf("   ✓ Thread created\n")
+
+	// The shadow copy follows the primary post, whatever its outcome
+	if shadowChannel() != "" {
+		trend := countTrend(mode, countGroupIssues(personStatusGroups), time.Now())
+		defer sendShadowReport(mode, jiraURL, jql, slackBotToken, date, trend, personStatusGroups)
+	}
+
+	if len(activeTeamChannelMap) > 0 {
+		return sendTeamReports(mode, jiraURL, jql, slackBotToken, slackChannel, date, personStatusGroups, stats)
+	}
+
+	// Send messages as a thread
+	logf("📤 Sending report to Slack at %s...\n", time.Now().Format("15:04:05"))
+
+	// Send header as main message to create the thread
+	trend := countTrend(mode, countGroupIssues(personStatusGroups), time.Now())
+	headerBlocks := buildHeaderBlocks(mode, date, trend, personStatusGroups, envRender)
+
+	thread := newReportThread(slackBotToken, slackChannel, stats)
+	if _, parentTS := parentThread(); parentTS != "" {
+		logf("   Posting under existing message %s...\n", parentTS)
+		if err := thread.attach(parentTS, headerBlocks); err != nil {
+			saveFailedDelivery(mode, jql, date, []failedChannel{failedThread(thread, personStatusGroups, nil)}, personStatusGroups)
+			activeRunResult.recordDelivery(personStatusGroups, nil, err)
+			return fmt.Errorf("failed to send initial message: %w", err)
+		}
+	} else {
+		logf("   Creating thread with header...\n")
+		if err := thread.start(headerBlocks); err != nil {
+			saveFailedDelivery(mode, jql, date, []failedChannel{failedThread(thread, personStatusGroups, nil)}, personStatusGroups)
+			activeRunResult.recordDelivery(personStatusGroups, nil, err)
+			return fmt.Errorf("failed to send initial message: %w", err)
+		}
+		logf("   ✓ Thread created\n")
+	}
+	activeRunResult.recordThread(thread.channel, thread.threadTS)
 
 	// Send each person's issues organized by status
-	err = sendDailyReportThreaded(slackBotToken, slackChannel, threadTS, jiraURL, personStatusGroups)
+	posted, err := sendDailyReportThreaded(thread, jiraURL, jql, personStatusGroups, mode, stats, envRender)
+	activeRunResult.recordDelivery(personStatusGroups, posted, err)
 	if err != nil {
-		fmt.Printf("❌ Failed to send threaded report: %v\n", err)
-		os.Exit(1)
+		saveFailedDelivery(mode, jql, date, []failedChannel{failedThread(thread, personStatusGroups, posted)}, personStatusGroups)
+		return fmt.Errorf("failed to send threaded report: %w", err)
 	}
 
-	fmt.Printf("\n✅ Successfully sent daily report with %d issues\n", countTotalIssues(issues))
+	// Historical and dry runs don't move the "since last report" window or replace today's manifest
+	if asOfTime.IsZero() && !dryRun {
+		if err := recordSuccessfulRun(mode, stats.Started, countGroupIssues(personStatusGroups)); err != nil {
+			logf("⚠️  Failed to record successful run: %v\n", err)
+		}
+		manifest := reportManifest{
+			Mode:     mode,
+			PostedAt: time.Now().UTC(),
+			Channel:  thread.channel,
+			ThreadTS: thread.threadTS,
+			People:   posted,
+		}
+		if err := saveReportManifest(manifest); err != nil {
+			logf("⚠️  Failed to write report manifest: %v\n", err)
+		}
+	}
+
+	if thread.degraded {
+		logf("\n⚠️  Sent daily report with %d issues (%d fetched) to fallback channel %s\n", countGroupIssues(personStatusGroups), fetched.Issues, thread.channel)
+		return errDegradedDelivery
+	}
+
+	logf("\n✅ Successfully sent daily report with %d issues (%d fetched)\n", countGroupIssues(personStatusGroups), fetched.Issues)
+	return nil
 }
 
-// countTotalIssues returns the total number of issues across all responses.
-func countTotalIssues(responses []JiraSearchResponse) int {
-	count := 0
-	for _, resp := range responses {
-		count += len(resp.Issues)
+// dailyStatusOrder is the order statuses appear in within each person's thread reply.
+// Statuses not listed follow alphabetically.
+var dailyStatusOrder = []string{"In Progress", "POST", "ON_QA", "MODIFIED", "Open", "Closed", "Archived"}
+
+// buildHeaderBlocks creates the main channel message that starts the report thread.
+// With COMPACT_CHANNEL_POST=true it is a single line with the headline counts,
+// keeping the channel as quiet as possible. A non-empty trend is appended to the title.
+func buildHeaderBlocks(mode reportMode, date, trend string, personGroups []PersonStatusGroup, render renderOptions) []map[string]interface{} {
+	if trend != "" {
+		date += " · " + trend
 	}
-	return count
+
+	if render.bool("COMPACT_CHANNEL_POST", false) {
+		totalIssues := countGroupIssues(personGroups)
+
+		blocks := []map[string]interface{}{
+			{
+				"type": "section",
+				"text": map[string]string{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("%s📊 *%s ready* — %d issue(s) across %d people (%s)", environmentPrefix(), reportName(mode), totalIssues, len(personGroups), date),
+				},
+			},
+		}
+		return append(blocks, buildLegendBlocks(mode, personGroups)...)
+	}
+
+	blocks := []map[string]interface{}{
+		{"type": "header", "text": map[string]string{"type": "plain_text", "text": environmentPrefix() + reportTitle(mode) + " — " + date}},
+		{"type": "divider"},
+	}
+	return append(blocks, buildLegendBlocks(mode, personGroups)...)
 }
 
 // SlackMessageResponse represents the response from Slack's chat.postMessage API
@@ -170,14 +715,44 @@ type SlackMessageResponse struct {
 	Channel string `json:"channel"` // Channel ID
 }
 
+// slackAPIBase is the Slack Web API's base URL; tests point it at a stub.
+var slackAPIBase = "https://slack.com/api"
+
 // sendToSlackAPI sends a message to Slack using the chat.postMessage API.
 // Returns the thread timestamp (ts) for threading subsequent messages.
-func sendToSlackAPI(botToken, channel, threadTS string, blocks []map[string]interface{}) (string, error) {
+// Messages Slack rejects as msg_too_long are split (see msg-too-long.go).
+func sendToSlackAPI(botToken, channel, threadTS string, blocks []map[string]interface{}, render renderOptions) (string, error) {
+	parts, err := sendMessageParts(botToken, channel, threadTS, blocks, render)
+	if len(parts) == 0 {
+		return "", err
+	}
+	return parts[0], err
+}
+
+// sendMessageParts is sendToSlackAPI returning the ts of every message posted:
+// one, or more if the message had to be split.
+func sendMessageParts(botToken, channel, threadTS string, blocks []map[string]interface{}, render renderOptions) ([]string, error) {
+	plainBlockText(blocks, render)
+	if err := validateBlocks(blocks); err != nil {
+		return nil, fmt.Errorf("invalid message: %w", err)
+	}
+	ts, err := postSlackMessage(botToken, channel, threadTS, blocks)
+	if isMsgTooLong(err) && len(blocks) > 1 && msgTooLongSplits() {
+		return sendSplitMessage(botToken, channel, threadTS, blocks, render)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []string{ts}, nil
+}
+
+// postSlackMessage posts prepared blocks with chat.postMessage and returns the message's ts.
+func postSlackMessage(botToken, channel, threadTS string, blocks []map[string]interface{}) (string, error) {
 	payload := map[string]interface{}{
 		"channel":      channel,
 		"blocks":       blocks,
-		"unfurl_links": false, // Disable automatic link unfurling
-		"unfurl_media": false, // Disable automatic media unfurling
+		"unfurl_links": envBool("UNFURL_LINKS", false), // Link previews are off unless enabled
+		"unfurl_media": envBool("UNFURL_MEDIA", false), // Media previews are off unless enabled
 	}
 
 	// If threadTS is provided, send as a thread reply
@@ -190,7 +765,7 @@ func sendToSlackAPI(botToken, channel, threadTS string, blocks []map[string]inte
 		return "", fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", "https://slack.com/api/chat.postMessage", bytes.NewBuffer(data))
+	req, err := http.NewRequest("POST", slackAPIBase+"/chat.postMessage", bytes.NewBuffer(data))
 	if err != nil {
 		return "", fmt.Errorf("failed to create request: %w", err)
 	}
@@ -215,7 +790,7 @@ func sendToSlackAPI(botToken, channel, threadTS string, blocks []map[string]inte
 	}
 
 	if !slackResp.OK {
-		return "", fmt.Errorf("Slack API error: %s", slackResp.Error)
+		return "", &SlackAPIError{Code: slackResp.Error}
 	}
 
 	return slackResp.TS, nil
@@ -246,29 +821,62 @@ func extractPRs(prField interface{}) []string {
 }
 
 // shouldFilterOut checks if an issue should be excluded from the report.
-// Uses the global excludedComponents and excludedLabels variables defined at the top of the file.
+// Uses the global excludedComponents and excludedLabels variables defined at the top of the file,
+// compiled at startup by compileExclusions so entries may be glob patterns.
 func shouldFilterOut(components []struct {
 	Name string `json:"name"`
 }, labels []string) bool {
+	return filterOutReason(components, labels) != ""
+}
+
+// filterOutReason returns the exclusion rule that removes an issue with these
+// components and labels (e.g. "component 'User Interface'"), or "" if none does.
+func filterOutReason(components []struct {
+	Name string `json:"name"`
+}, labels []string) string {
+	// With an allowlist, keep only issues with at least one listed component
+	if len(includedComponentPatterns) > 0 {
+		included := false
+		for _, comp := range components {
+			if matchesAny(includedComponentPatterns, comp.Name) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return "component not in INCLUDED_COMPONENTS"
+		}
+	}
+
 	// Check if any component matches excluded list
 	for _, comp := range components {
-		for _, excluded := range excludedComponents {
-			if comp.Name == excluded {
-				return true
+		if rule, ok := matchingPattern(excludedComponentPatterns, comp.Name); ok {
+			return fmt.Sprintf("component '%s'", rule)
+		}
+	}
+
+	// With an allowlist, keep only issues with at least one listed label
+	if len(includedLabelPatterns) > 0 {
+		included := false
+		for _, label := range labels {
+			if matchesAny(includedLabelPatterns, label) {
+				included = true
+				break
 			}
 		}
+		if !included {
+			return "label not in INCLUDED_LABELS"
+		}
 	}
 
 	// Check if any label matches excluded list
 	for _, label := range labels {
-		for _, excluded := range excludedLabels {
-			if label == excluded {
-				return true
-			}
+		if rule, ok := matchingPattern(excludedLabelPatterns, label); ok {
+			return fmt.Sprintf("label '%s'", rule)
 		}
 	}
 
-	return false
+	return ""
 }
 
 // setJiraAuth sets the appropriate Authorization header for the JIRA request.
@@ -284,33 +892,67 @@ func setJiraAuth(req *http.Request, jiraToken string) {
 	}
 }
 
-// fetchJiraIssues queries JIRA's /rest/api/3/search/jql endpoint and returns matching issues.
-// Parameters:
-//   - jiraURL: Base URL of the JIRA instance (e.g., https://redhat.atlassian.net)
-//   - jiraToken: API token for authentication
-//   - jql: JQL query string to filter issues
+// fetchOptions tweaks what a JIRA search request asks for beyond the default fields.
+type fetchOptions struct {
+	Expand      string   // e.g. "changelog" to include issue history
+	Fields      []string // Fields to request instead of the defaults (nil keeps the defaults)
+	ExtraFields []string // Additional fields to request on top of the defaults
+	MaxResults  int      // Page size (0 for 100)
+}
+
+// fetchJiraIssuesFields queries JIRA's /rest/api/3/search/jql endpoint and returns
+// the matching issues with only the given fields, for callers that need a fraction
+// of an issue (e.g. epic summaries). Reports stream their pages instead (see
+// streamJiraIssues) rather than holding every response in memory.
 //
-// Paginates using nextPageToken until all results are fetched.
-func fetchJiraIssues(jiraURL, jiraToken, jql string) ([]JiraSearchResponse, error) {
+// Paginates using nextPageToken until all results are fetched. The endpoint is
+// cursor based, so a page smaller than maxResults never causes issues to be skipped.
+func fetchJiraIssuesFields(jiraURL, jiraToken, jql string, fields []string) ([]JiraSearchResponse, error) {
 	var allResults []JiraSearchResponse
+	_, err := streamJiraIssues(jiraURL, jiraToken, jql, fetchOptions{Fields: fields}, func(page JiraSearchResponse) error {
+		allResults = append(allResults, page)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allResults, nil
+}
+
+// fetchStats summarizes a streamed JIRA search.
+type fetchStats struct {
+	Pages  int
+	Issues int
+}
+
+// streamJiraIssues runs a JIRA search and calls handlePage for each page as soon as it
+// has been decoded, so callers can reduce issues page by page instead of holding every
+// raw response in memory. Stops at the first error returned by handlePage.
+func streamJiraIssues(jiraURL, jiraToken, jql string, opts fetchOptions, handlePage func(JiraSearchResponse) error) (fetchStats, error) {
+	return streamJiraIssuesContext(context.Background(), jiraURL, jiraToken, jql, opts, handlePage)
+}
+
+// streamJiraIssuesContext is streamJiraIssues for request handling: requests are
+// canceled with ctx and progress lines carry its request ID.
+func streamJiraIssuesContext(ctx context.Context, jiraURL, jiraToken, jql string, opts fetchOptions, handlePage func(JiraSearchResponse) error) (fetchStats, error) {
+	var stats fetchStats
+	fields := searchFields(opts)
+
 	maxResults := 100
+	if opts.MaxResults > 0 {
+		maxResults = opts.MaxResults
+	}
 	nextPageToken := ""
-	totalFetched := 0
 
 	for {
 		requestBody := map[string]interface{}{
 			"jql":        jql,
 			"maxResults": maxResults,
-			"fields": []string{
-				"summary",
-				"status",
-				"assignee",
-				"customfield_12315948", // QA Contact
-				"issuetype",
-				"components",
-				"labels",
-				"customfield_12310220", // Git Pull Request
-			},
+			"fields":     fields,
+		}
+
+		if opts.Expand != "" {
+			requestBody["expand"] = opts.Expand
 		}
 
 		if nextPageToken != "" {
@@ -319,12 +961,12 @@ func fetchJiraIssues(jiraURL, jiraToken, jql string) ([]JiraSearchResponse, erro
 
 		body, err := json.Marshal(requestBody)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request: %w", err)
+			return stats, fmt.Errorf("failed to marshal request: %w", err)
 		}
 
-		req, err := http.NewRequest("POST", fmt.Sprintf("%s/rest/api/3/search/jql", jiraURL), bytes.NewBuffer(body))
+		req, err := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/rest/api/3/search/jql", jiraURL), bytes.NewBuffer(body))
 		if err != nil {
-			return nil, fmt.Errorf("failed to create request: %w", err)
+			return stats, fmt.Errorf("failed to create request: %w", err)
 		}
 
 		setJiraAuth(req, jiraToken)
@@ -332,275 +974,500 @@ func fetchJiraIssues(jiraURL, jiraToken, jql string) ([]JiraSearchResponse, erro
 
 		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
-			return nil, fmt.Errorf("failed to execute request: %w", err)
+			return stats, fmt.Errorf("failed to execute request: %w", err)
 		}
 
 		responseBody, err := io.ReadAll(resp.Body)
 		resp.Body.Close()
 		if err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
+			return stats, fmt.Errorf("failed to read response: %w", err)
 		}
+		dumpRawResponse(responseBody)
 
 		if resp.StatusCode != 200 {
-			return nil, fmt.Errorf("JIRA API returned %d: %s", resp.StatusCode, string(responseBody))
+			return stats, &jqlRejectedError{StatusCode: resp.StatusCode, message: fmt.Sprintf("JIRA API returned %d: %s", resp.StatusCode, redactSecrets(string(responseBody)))}
 		}
+		captureFetchResponse(responseBody)
 
 		var result JiraSearchResponse
 		if err := json.Unmarshal(responseBody, &result); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+			return stats, fmt.Errorf("failed to unmarshal response: %w", err)
 		}
+		normalizeIssues(&result)
+		warnMissingFields(ctx, result.Issues, fields)
+
+		// Progress is based on what JIRA actually returned, not on maxResults,
+		// since the server may cap a page below the requested size.
+		stats.Pages++
+		stats.Issues += len(result.Issues)
+		totalFetched := stats.Issues
 
-		allResults = append(allResults, result)
-		totalFetched += len(result.Issues)
+		if err := handlePage(result); err != nil {
+			return stats, err
+		}
 
 		if result.NextPageToken == "" {
-			fmt.Printf("      Fetched all %d issues from JIRA\n", totalFetched)
+			ctxLogf(ctx, "      Fetched all %d issues from JIRA\n", totalFetched)
+			break
+		}
+
+		// Guard against a server that keeps handing out a token without
+		// returning issues (or repeats the same token), which would loop forever.
+		if len(result.Issues) == 0 || result.NextPageToken == nextPageToken {
+			ctxLogf(ctx, "      JIRA returned no progress after %d issues, stopping pagination\n", totalFetched)
 			break
 		}
 
-		fmt.Printf("      Fetched %d issues so far, continuing...\n", totalFetched)
+		ctxLogf(ctx, "      Fetched %d issues so far, continuing...\n", totalFetched)
 		nextPageToken = result.NextPageToken
 	}
 
-	return allResults, nil
+	return stats, nil
 }
 
-// buildSlackBlocks creates Slack Block Kit payloads for the daily report.
-// Returns multiple payloads if the report is too large for a single message.
-//
-// Filtering rules:
-//   - UI-related issues are excluded
-//   - Epics without PRs are excluded
-//   - ON_QA and MODIFIED issues are grouped by QA Contact (if available)
-//   - Other issues are grouped by Assignee
-//
-// Slack limits messages to 50 blocks, so we cap at 48 per message.
+// searchFields lists the fields a search with opts requests.
+func searchFields(opts fetchOptions) []string {
+	fields := []string{
+		"summary",
+		"status",
+		"assignee",
+		qaContactFieldID,
+		"issuetype",
+		"priority",
+		"resolution",
+		"components",
+		"labels",
+		pullRequestFieldID,
+		severityField(),
+	}
+	if opts.Fields != nil {
+		fields = append([]string(nil), opts.Fields...)
+	}
+	return append(fields, opts.ExtraFields...)
+}
 
 // PersonStatusGroup represents issues for one person, grouped by status
 type PersonStatusGroup struct {
 	Person       string
 	StatusGroups map[string][]IssueItem
 	TotalIssues  int
+	TimeZone     string // IANA name, empty if unknown
+	AccountID    string // JIRA account ID, empty if unknown
+	Username     string // JIRA Server username, empty if unknown
+	Workstream   string // Set with GROUP_BY=workstream, whose groups hold one workstream's issues
 }
 
-// buildPersonStatusGroups groups issues by person, then by status
-func buildPersonStatusGroups(responses []JiraSearchResponse) []PersonStatusGroup {
-	// First group by person
-	personIssues := make(map[string][]IssueItem)
+// includeInReport applies the report filters to an issue:
+//   - Excluded components/labels are dropped
+//   - Issues below MIN_PRIORITY are dropped
+//   - Bugs below -min-severity are dropped
+//   - Epics without PRs are dropped (qa mode only)
+func includeInReport(issue JiraIssue, mode reportMode) bool {
+	return exclusionReason(issue, mode) == ""
+}
 
-	for _, resp := range responses {
-		for _, issue := range resp.Issues {
-			if shouldFilterOut(issue.Fields.Components, issue.Fields.Labels) {
-				continue
-			}
+// exclusionReason returns the filter rule that removes the issue from the report,
+// or "" if the issue is included.
+func exclusionReason(issue JiraIssue, mode reportMode) string {
+	if reason := filterOutReason(issue.Fields.Components, issue.Fields.Labels); reason != "" {
+		return reason
+	}
+
+	if priority := issuePriority(issue); activePriorityFilter.drops(priority) {
+		if priority == "" {
+			return "no priority"
+		}
+		return fmt.Sprintf("priority below %s", os.Getenv("MIN_PRIORITY"))
+	}
 
-			prs := extractPRs(issue.Fields.GitPullRequest)
+	if activeSeverityFilter.hides(issue) {
+		return activeSeverityFilter.reason()
+	}
 
-			if issue.Fields.IssueType.Name == "Epic" && len(prs) == 0 {
-				continue
-			}
+	if mode == modeQA && issue.Fields.IssueType.Name == "Epic" && len(extractPRs(issue.Fields.GitPullRequest)) == 0 {
+		return "epic-without-PR"
+	}
 
-			assignee := "Unassigned"
-			if (issue.Fields.Status.Name == "ON_QA" || issue.Fields.Status.Name == "MODIFIED") && issue.Fields.QAContact != nil {
-				assignee = issue.Fields.QAContact.DisplayName
-			} else if issue.Fields.Assignee != nil {
-				assignee = issue.Fields.Assignee.DisplayName
-			}
+	return ""
+}
 
-			personIssues[assignee] = append(personIssues[assignee], IssueItem{
-				Key:            issue.Key,
-				Summary:        issue.Fields.Summary,
-				Status:         issue.Fields.Status.Name,
-				GitPullRequest: prs,
-			})
+// reportPerson returns who an issue is listed under in the report.
+// In qa mode ON_QA and MODIFIED issues go to the QA Contact (if any), everything else to the Assignee.
+func reportPerson(issue JiraIssue, mode reportMode) string {
+	if user := reportUser(issue, mode); user != nil {
+		return user.DisplayName
+	}
+	return "Unassigned"
+}
+
+// reportUser returns the JIRA user an issue is listed under, or nil if it is unassigned.
+func reportUser(issue JiraIssue, mode reportMode) *jiraUserRef {
+	if mode == modeQA && (issue.Fields.Status.Name == "ON_QA" || issue.Fields.Status.Name == "MODIFIED") && issue.Fields.QAContact != nil {
+		return issue.Fields.QAContact
+	}
+	return issue.Fields.Assignee
+}
+
+// personGrouper accumulates filtered issues per person page by page, keeping only
+// the reduced IssueItem data so raw responses can be discarded as they arrive.
+type personGrouper struct {
+	mode         reportMode
+	personIssues map[string][]IssueItem
+	timeZones    map[string]string      // JIRA profile timezone, by person
+	users        map[string]jiraUserRef // JIRA user, by person
+	fetched      int                    // Issues seen
+	included     int                    // Issues that passed the report filters
+	removed      map[string]int         // Issues removed, by exclusionReason rule
+	epicsFetched int                    // Epics seen
+	epicsKept    int                    // Epics that passed the report filters
+}
+
+// newPersonGrouper creates an empty grouper for the report mode.
+func newPersonGrouper(mode reportMode) *personGrouper {
+	return &personGrouper{
+		mode:         mode,
+		personIssues: make(map[string][]IssueItem),
+		timeZones:    make(map[string]string),
+		users:        make(map[string]jiraUserRef),
+		removed:      make(map[string]int),
+	}
+}
+
+// add files the issues of one search page that pass the report filters under their person.
+func (g *personGrouper) add(page JiraSearchResponse) {
+	for _, issue := range page.Issues {
+		g.fetched++
+		isEpic := issue.Fields.IssueType.Name == "Epic"
+		if isEpic {
+			g.epicsFetched++
+		}
+		if reason := exclusionReason(issue, g.mode); reason != "" {
+			g.removed[reason]++
+			continue
+		}
+		g.included++
+		if isEpic {
+			g.epicsKept++
+		}
+
+		person := reportPerson(issue, g.mode)
+		g.personIssues[person] = append(g.personIssues[person], newIssueItem(issue))
+		if user := reportUser(issue, g.mode); user != nil {
+			if user.TimeZone != "" {
+				g.timeZones[person] = user.TimeZone
+			}
+			g.users[person] = *user
+			activeSlackUsers.prefetch(person, user.EmailAddress)
 		}
 	}
+}
 
+// groups returns the accumulated issues grouped by person (alphabetically), then by status.
+func (g *personGrouper) groups() []PersonStatusGroup {
 	// Sort people alphabetically
 	var people []string
-	for person := range personIssues {
+	for person := range g.personIssues {
 		people = append(people, person)
 	}
 	sort.Strings(people)
 
+	// Optionally move high-severity bugs to the top of each status
+	var ranks map[string]int
+	if g.mode == modeQA && envBool("SORT_BY_SEVERITY", false) {
+		ranks = severityRanks()
+	}
+
 	// Group each person's issues by status
 	var result []PersonStatusGroup
 	for _, person := range people {
-		issues := personIssues[person]
-		statusGroups := make(map[string][]IssueItem)
+		issues := g.personIssues[person]
+		if g.mode == modeInProgress {
+			sortByStatusAge(issues)
+		}
 
+		statusGroups := make(map[string][]IssueItem)
 		for _, issue := range issues {
 			statusGroups[issue.Status] = append(statusGroups[issue.Status], issue)
 		}
 
+		// Watchers first so severity, when enabled, stays the primary order
+		if sortByWatchersEnabled() {
+			for _, group := range statusGroups {
+				sortByWatchers(group)
+			}
+		}
+		if ranks != nil {
+			for _, group := range statusGroups {
+				sortBySeverity(group, ranks)
+			}
+		}
+
 		result = append(result, PersonStatusGroup{
 			Person:       person,
 			StatusGroups: statusGroups,
 			TotalIssues:  len(issues),
+			TimeZone:     personTimeZone(person, g.timeZones[person]),
+			AccountID:    g.users[person].AccountID,
+			Username:     g.users[person].Name,
 		})
 	}
 
-	return result
+	return dropSmallGroups(result)
 }
 
-// sendDailyReportThreaded sends the daily report as threaded messages per person/status
-func sendDailyReportThreaded(botToken, channel, threadTS, jiraURL string, personGroups []PersonStatusGroup) error {
-	statusOrder := []string{"In Progress", "Modified", "POST", "ON_QA", "MODIFIED", "Open", "Closed", "Archived"}
-
-	messageCount := 0
-	separator := "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
-
-	for _, group := range personGroups {
-		// Build ONE message with person header + all their statuses
-		blocks := []map[string]interface{}{}
+// dropSmallGroups omits people with fewer issues than MIN_ISSUES_PER_PERSON (default 0, show everyone).
+func dropSmallGroups(groups []PersonStatusGroup) []PersonStatusGroup {
+	minIssues := envInt("MIN_ISSUES_PER_PERSON", 0)
+	if minIssues <= 0 {
+		return groups
+	}
 
-		// Add top separator for first person only
-		if messageCount == 0 {
-			blocks = append(blocks, map[string]interface{}{
-				"type": "section",
-				"text": map[string]string{
-					"type": "mrkdwn",
-					"text": separator,
-				},
-			})
+	var kept []PersonStatusGroup
+	for _, group := range groups {
+		if group.TotalIssues >= minIssues {
+			kept = append(kept, group)
 		}
+	}
 
-		// Add person header with bottom separator
-		blocks = append(blocks, map[string]interface{}{
-			"type": "section",
-			"text": map[string]string{
-				"type": "mrkdwn",
-				"text": fmt.Sprintf("*👤 %s* (%d issue(s))\n%s", group.Person, group.TotalIssues, separator),
-			},
-		})
-		// Add all statuses and their issues to the blocks
-		for _, status := range statusOrder {
-			issues, exists := group.StatusGroups[status]
-			if !exists {
-				continue
-			}
+	if hidden := len(groups) - len(kept); hidden > 0 {
+		logf("   Hiding %d people with fewer than %d issue(s)\n", hidden, minIssues)
+	}
+	return kept
+}
 
-			// Add status header (indented with non-breaking spaces)
-			blocks = append(blocks, map[string]interface{}{
-				"type": "section",
-				"text": map[string]string{
-					"type": "mrkdwn",
-					"text": fmt.Sprintf("\n\u00A0\u00A0\u00A0📂 *%s* (%d)", status, len(issues)),
-				},
-			})
+// countGroupIssues returns the number of issues across all person groups.
+func countGroupIssues(groups []PersonStatusGroup) int {
+	count := 0
+	for _, group := range groups {
+		count += group.TotalIssues
+	}
+	return count
+}
 
-			// Add issues for this status (more indented with non-breaking spaces)
-			for _, issue := range issues {
-				pr := "–"
-				if len(issue.GitPullRequest) > 0 {
-					var prLinks []string
-					for i, prURL := range issue.GitPullRequest {
-						prLinks = append(prLinks, fmt.Sprintf("<%s|PR%d>", prURL, i+1))
-					}
-					pr = strings.Join(prLinks, " ")
-				}
+// sendDailyReportThreaded sends the daily report as threaded messages per person/status.
+// Consecutive small person sections are packed into shared replies (see reply-packing.go).
+// Returns where each person's section was posted.
+func sendDailyReportThreaded(thread *reportThread, jiraURL, jql string, personGroups []PersonStatusGroup, mode reportMode, stats *runStats, render renderOptions) (map[string]manifestPerson, error) {
+	// Counted before the overflow is cut off, for the footer
+	statusTotals := buildStatusTotalsBlocks(jiraURL, jql, personGroups)
+	customerBugs := buildCustomerBugsBlocks(personGroups)
+
+	// Cap how many people get a full reply; the rest are summarized at the end
+	personGroups, overflow := splitPersonOverflow(personGroups, maxPeople(), overflowByName())
+	byTeam, byWorkstream := groupByTeam(), groupByWorkstream()
+	switch {
+	case byTeam:
+		sortByTeam(personGroups)
+	case byWorkstream:
+		personGroups = splitByWorkstream(personGroups)
+	}
 
-				summary := escapeSlackText(issue.Summary)
-				if len(summary) > 65 {
-					summary = summary[:65] + "..."
-				}
+	var sections []personSection
+	hashes := make(map[string]string, len(personGroups))
+	issues := make(map[string]map[string]string, len(personGroups))
+	for i, group := range personGroups {
+		hashes[group.Person] = sectionHash(thread, jiraURL, group, mode, render)
+		// A person has a section per workstream; the manifest keeps all their issues
+		if issues[group.Person] == nil {
+			issues[group.Person] = make(map[string]string)
+		}
+		for key, status := range groupIssueStatuses(group) {
+			issues[group.Person][key] = status
+		}
+		section := personSection{
+			person: group.Person,
+			blocks: buildPersonBlocks(thread, jiraURL, group, mode, i, render),
+		}
+		// Each team (workstream) opens with its header; packing starts a new reply there
+		switch {
+		case byTeam:
+			section.team = teamOf(group.Person)
+			if i == 0 || teamOf(personGroups[i-1].Person) != section.team {
+				section.blocks = append(buildTeamHeaderBlocks(section.team, personGroups), section.blocks...)
+			}
+		case byWorkstream:
+			section.team = group.Workstream
+			if i == 0 || personGroups[i-1].Workstream != section.team {
+				section.blocks = append(buildWorkstreamHeaderBlocks(section.team, personGroups), section.blocks...)
+			}
+		}
+		sections = append(sections, section)
+	}
+	replies := packPersonSections(sections, envInt("MAX_PEOPLE_PER_MESSAGE", defaultPeoplePerMessage), dailyReplyBlockLimit)
 
-				text := fmt.Sprintf("\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0• <%s/browse/%s|*%s*> — %s\n\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0*Status:* %s  |  *PR:* %s",
-					jiraURL, issue.Key, issue.Key, summary, issue.Status, pr)
+	posted := make(map[string]manifestPerson, len(sections))
+	for i, reply := range replies {
+		logf("   Sending reply %d/%d: %s with all statuses...\n", i+1, len(replies), strings.Join(reply.people, ", "))
+		parts, err := thread.reply(reply.blocks)
+		if err != nil {
+			return posted, fmt.Errorf("failed to send message for %s: %w", strings.Join(reply.people, ", "), err)
+		}
+		for _, person := range reply.people {
+			posted[person] = manifestPerson{Channel: thread.channel, ThreadTS: thread.threadTS, TS: parts[0], Parts: parts[1:], Hash: hashes[person], Issues: issues[person]}
+		}
+		logf("   ✓ Reply %d/%d sent\n", i+1, len(replies))
 
-				blocks = append(blocks, map[string]interface{}{
-					"type": "section",
-					"text": map[string]string{
-						"type": "mrkdwn",
-						"text": text,
-					},
-				})
-			}
+		// Small delay between replies
+		if i+1 < len(replies) || len(overflow) > 0 {
+			stats.sleep(500 * time.Millisecond)
 		}
+	}
 
-		// Add any statuses not in predefined order
-		for status, issues := range group.StatusGroups {
-			found := false
-			for _, s := range statusOrder {
-				if s == status {
-					found = true
-					break
-				}
-			}
-			if found {
-				continue
-			}
+	if len(overflow) > 0 {
+		logf("   Sending overflow reply for %d more people...\n", len(overflow))
+		if _, err := thread.reply(buildOverflowBlocks(jiraURL, overflow)); err != nil {
+			return posted, fmt.Errorf("failed to send overflow message: %w", err)
+		}
+		logf("   ✓ Overflow reply sent\n")
+	}
 
-			// Add status header (indented with non-breaking spaces)
-			blocks = append(blocks, map[string]interface{}{
-				"type": "section",
-				"text": map[string]string{
-					"type": "mrkdwn",
-					"text": fmt.Sprintf("\n\u00A0\u00A0\u00A0📂 *%s* (%d)", status, len(issues)),
-				},
-			})
+	// The footer lists the status totals, notes hidden bugs and customer bugs, links
+	// to the report's query and hints at the slash command
+	footer := append(statusTotals, buildHiddenBugsBlocks(hiddenBugs)...)
+	footer = append(footer, customerBugs...)
+	footer = append(footer, buildReportLinkBlocks(jiraURL, jql)...)
+	if footer = append(footer, buildCommandHintBlocks()...); len(footer) > 0 {
+		stats.sleep(500 * time.Millisecond)
+		if _, err := thread.reply(footer); err != nil {
+			return posted, fmt.Errorf("failed to send footer: %w", err)
+		}
+	}
 
-			// Add issues for this status (more indented with non-breaking spaces)
-			for _, issue := range issues {
-				pr := "–"
-				if len(issue.GitPullRequest) > 0 {
-					var prLinks []string
-					for i, prURL := range issue.GitPullRequest {
-						prLinks = append(prLinks, fmt.Sprintf("<%s|PR%d>", prURL, i+1))
-					}
-					pr = strings.Join(prLinks, " ")
-				}
+	return posted, nil
+}
 
-				summary := escapeSlackText(issue.Summary)
-				if len(summary) > 65 {
-					summary = summary[:65] + "..."
-				}
+// buildPersonBlocks renders the index-th person's section of the thread: a header,
+// their issues by status and a closing separator. The first section also opens with
+// a separator. The header's block_id marks where the section starts in a packed reply.
+func buildPersonBlocks(thread *reportThread, jiraURL string, group PersonStatusGroup, mode reportMode, index int, render renderOptions) []map[string]interface{} {
+	statusOrder := dailyStatusOrder
+	separator := "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━"
 
-				text := fmt.Sprintf("\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0• <%s/browse/%s|*%s*> — %s\n\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0\u00A0*Status:* %s  |  *PR:* %s",
-					jiraURL, issue.Key, issue.Key, summary, issue.Status, pr)
+	// Build ONE message with person header + all their statuses
+	blocks := []map[string]interface{}{}
 
-				blocks = append(blocks, map[string]interface{}{
-					"type": "section",
-					"text": map[string]string{
-						"type": "mrkdwn",
-						"text": text,
-					},
-				})
-			}
-		}
+	// Add top separator for first person only
+	if index == 0 {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": separator,
+			},
+		})
+	}
 
-		// Add closing separator
+	// Add person header with bottom separator
+	blocks = append(blocks, map[string]interface{}{
+		"type":     "section",
+		"block_id": fmt.Sprintf("%s%d", personBlockIDPrefix, index),
+		"text": map[string]string{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("*👤 %s* (%d issue(s))%s%s\n%s", personHeaderName(jiraURL, group, render), group.TotalIssues, workSplitSuffix(group), localTimeSuffix(group), separator),
+		},
+	})
+	// Add all statuses and their issues to the blocks, unlisted statuses last
+	for _, status := range statusesInOrder(presentStatuses(group.StatusGroups), statusOrder) {
+		issues := group.StatusGroups[status]
+
+		// Add status header (indented with non-breaking spaces)
 		blocks = append(blocks, map[string]interface{}{
 			"type": "section",
 			"text": map[string]string{
 				"type": "mrkdwn",
-				"text": fmt.Sprintf("\n%s", separator),
+				"text": fmt.Sprintf("\n%s📂 *%s* (%d)", render.indentPrefix(indentStatus), escapeSlackMrkdwn(status), len(issues)),
 			},
 		})
 
-		// Send the complete message for this person
-		messageCount++
-		fmt.Printf("   Sending reply %d/%d: %s with all statuses...\n", messageCount, len(personGroups), group.Person)
-		_, err := sendToSlackAPI(botToken, channel, threadTS, blocks)
-		if err != nil {
-			return fmt.Errorf("failed to send message for %s: %w", group.Person, err)
+		// Add issues for this status (more indented with non-breaking spaces)
+		for _, issue := range issues {
+			text := formatDailyIssueText(jiraURL, issue, mode, render)
+
+			blocks = append(blocks, map[string]interface{}{
+				"type": "section",
+				"text": map[string]string{
+					"type": "mrkdwn",
+					"text": text,
+				},
+			})
 		}
-		fmt.Printf("   ✓ Reply %d/%d sent\n", messageCount, len(personGroups))
+	}
 
-		// Small delay between people
-		if messageCount < len(personGroups) {
-			time.Sleep(500 * time.Millisecond)
+	if remind := buildRemindButtonBlock(group.Person, thread.channel, thread.threadTS); remind != nil {
+		blocks = append(blocks, remind)
+	}
+
+	// Add closing separator
+	blocks = append(blocks, map[string]interface{}{
+		"type": "section",
+		"text": map[string]string{
+			"type": "mrkdwn",
+			"text": fmt.Sprintf("\n%s", separator),
+		},
+	})
+
+	// Issue lines come from JIRA and ISSUE_TEMPLATE, so their length isn't bounded
+	limitBlockText(blocks)
+	return blocks
+}
+
+// formatDailyIssueText renders one issue line of the daily report thread.
+// The in-progress mode swaps the status for the time spent in it and calls out missing PRs.
+func formatDailyIssueText(jiraURL string, issue IssueItem, mode reportMode, render renderOptions) string {
+	data := newIssueLineData(jiraURL, issue, 65, render)
+	data.Ownership = ownershipSuffix(issue)
+	data.Watchers = watchersSuffix(issue)
+	data.Workstreams = workstreamNoteSuffix(issue)
+	if mode == modeInProgress {
+		if len(issue.GitPullRequest) == 0 {
+			data.PRs = "❌ none yet"
+		}
+		data.Age = "?"
+		if !issue.StatusSince.IsZero() {
+			data.Age = formatAge(reportNow().Sub(issue.StatusSince))
 		}
 	}
+	text := renderIssueLine(data, render)
 
-	return nil
+	if epic := formatEpicLine(jiraURL, issue); epic != "" {
+		text += "\n" + render.indentPrefix(indentDetail) + epic
+	}
+
+	if issue.Description != "" {
+		text = appendDescriptionLine(text, issue.Description, render)
+	}
+
+	return text
 }
 
-// escapeSlackText escapes special characters that have meaning in Slack's mrkdwn format.
-// This prevents issues with < and > characters in issue summaries breaking Slack links.
+// escapeSlackText escapes JIRA-sourced text for Slack's mrkdwn format: the control
+// characters (see escapeSlackControl) and emoji shortcodes, so a summary like
+// "Fix :port: mapping" isn't shown with an emoji in the middle.
 func escapeSlackText(text string) string {
+	return emojiShortcodePattern.ReplaceAllString(escapeSlackControl(text), "$1:\u200b$2")
+}
+
+// escapeSlackControl escapes special characters that have meaning in Slack's mrkdwn format.
+// This prevents issues with < and > characters in issue summaries breaking Slack links.
+func escapeSlackControl(text string) string {
 	text = strings.ReplaceAll(text, "&", "&amp;")
 	text = strings.ReplaceAll(text, "<", "&lt;")
 	text = strings.ReplaceAll(text, ">", "&gt;")
 	return text
 }
+
+// emojiShortcodePattern matches a colon that could open an emoji shortcode: at the
+// start of a word and followed by a shortcode character. Times ("10:30") and
+// "key:value" text don't match. A zero-width space after the colon keeps Slack from
+// reading a shortcode.
+var emojiShortcodePattern = regexp.MustCompile(`(^|[^\p{L}\p{N}]):([\p{L}\p{N}_+\-])`)
+
+// mrkdwnFormattingReplacer swaps mrkdwn's formatting characters for look-alikes.
+var mrkdwnFormattingReplacer = strings.NewReplacer("*", "∗", "_", "ˍ", "~", "∼", "`", "ˋ")
+
+// escapeSlackMrkdwn escapes JIRA-sourced names (people, statuses) for mrkdwn. Besides
+// what escapeSlackText handles, a "*" or "_" in a name would close the bold or italic
+// span around it, so formatting characters are replaced with look-alikes.
+func escapeSlackMrkdwn(text string) string {
+	return mrkdwnFormattingReplacer.Replace(escapeSlackText(text))
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// jiraSearchStub serves the given pages in order from /rest/api/3/search/jql and
+// records the nextPageToken of every request.
+func jiraSearchStub(t *testing.T, pages []JiraSearchResponse) (*httptest.Server, *[]string) {
+	t.Helper()
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/jql" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		tokens = append(tokens, body.NextPageToken)
+		if len(tokens) > len(pages) {
+			t.Errorf("unexpected request %d for token %q", len(tokens), body.NextPageToken)
+			json.NewEncoder(w).Encode(JiraSearchResponse{})
+			return
+		}
+		json.NewEncoder(w).Encode(pages[len(tokens)-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &tokens
+}
+
+// stubIssues returns n issues with keys starting at MTV-first.
+func stubIssues(first, n int) []JiraIssue {
+	issues := make([]JiraIssue, n)
+	for i := range issues {
+		issues[i].Key = fmt.Sprintf("MTV-%d", first+i)
+	}
+	return issues
+}
+
+func TestStreamJiraIssuesPagination(t *testing.T) {
+	tests := []struct {
+		name       string
+		pages      []JiraSearchResponse
+		wantPages  int
+		wantIssues int
+		wantTokens []string
+	}{
+		{
+			name: "short pages keep paginating until there is no token",
+			pages: []JiraSearchResponse{
+				{Issues: stubIssues(1, 2), NextPageToken: "a"},
+				{Issues: stubIssues(3, 1), NextPageToken: "b"},
+				{Issues: stubIssues(4, 3)},
+			},
+			wantPages:  3,
+			wantIssues: 6,
+			wantTokens: []string{"", "a", "b"},
+		},
+		{
+			name: "a repeated token stops pagination",
+			pages: []JiraSearchResponse{
+				{Issues: stubIssues(1, 2), NextPageToken: "same"},
+				{Issues: stubIssues(3, 2), NextPageToken: "same"},
+			},
+			wantPages:  2,
+			wantIssues: 4,
+			wantTokens: []string{"", "same"},
+		},
+		{
+			name: "an empty page with a token stops pagination",
+			pages: []JiraSearchResponse{
+				{Issues: stubIssues(1, 1), NextPageToken: "a"},
+				{NextPageToken: "b"},
+			},
+			wantPages:  2,
+			wantIssues: 1,
+			wantTokens: []string{"", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, tokens := jiraSearchStub(t, tt.pages)
+
+			var keys []string
+			stats, err := streamJiraIssues(server.URL, "token", "project = MTV", fetchOptions{}, func(page JiraSearchResponse) error {
+				for _, issue := range page.Issues {
+					keys = append(keys, issue.Key)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("streamJiraIssues: %v", err)
+			}
+			if stats.Pages != tt.wantPages || stats.Issues != tt.wantIssues {
+				t.Errorf("stats = %+v, want %d pages and %d issues", stats, tt.wantPages, tt.wantIssues)
+			}
+			if len(keys) != tt.wantIssues {
+				t.Errorf("handled %d issues, want %d", len(keys), tt.wantIssues)
+			}
+			if fmt.Sprint(*tokens) != fmt.Sprint(tt.wantTokens) {
+				t.Errorf("requested tokens %q, want %q", *tokens, tt.wantTokens)
+			}
+		})
+	}
+}
+
+func TestStreamJiraIssuesStopsAtFirstError(t *testing.T) {
+	tests := []struct {
+		name       string
+		failPage   bool // JIRA answers page 3 with a 500
+		failHandle bool // The page handler fails on page 3
+		wantErr    string
+	}{
+		{name: "JIRA error on page 3", failPage: true, wantErr: "JIRA API returned 500"},
+		{name: "handler error on page 3", failHandle: true, wantErr: "handler failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Pages 1-5 chain with tokens p2..p5; any request for p4 or p5 is wasted work
+			var requested []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					NextPageToken string `json:"nextPageToken"`
+				}
+				json.NewDecoder(r.Body).Decode(&body)
+				requested = append(requested, body.NextPageToken)
+				page := len(requested)
+				if tt.failPage && page == 3 {
+					http.Error(w, `{"errorMessages": ["Internal error"]}`, http.StatusInternalServerError)
+					return
+				}
+				json.NewEncoder(w).Encode(JiraSearchResponse{Issues: stubIssues(page*10, 1), NextPageToken: fmt.Sprintf("p%d", page+1)})
+			}))
+			t.Cleanup(server.Close)
+
+			handled := 0
+			stats, err := streamJiraIssuesContext(context.Background(), server.URL, "token", "project = MTV", fetchOptions{}, func(page JiraSearchResponse) error {
+				handled++
+				if tt.failHandle && handled == 3 {
+					return errors.New("handler failed")
+				}
+				return nil
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if fmt.Sprint(requested) != "[ p2 p3]" {
+				t.Errorf("requested tokens %q, want only the first three pages", requested)
+			}
+			if stats.Pages > 3 {
+				t.Errorf("stats = %+v, want at most 3 pages", stats)
+			}
+		})
+	}
+}
+
+func TestStreamJiraIssuesCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(JiraSearchResponse{Issues: stubIssues(requests, 1), NextPageToken: fmt.Sprintf("p%d", requests+1)})
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := streamJiraIssuesContext(ctx, server.URL, "token", "project = MTV", fetchOptions{}, func(JiraSearchResponse) error {
+		if requests == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if requests != 2 {
+		t.Errorf("%d pages requested, want none after the cancel on page 2", requests)
+	}
+}
+
+func TestDropSmallGroups(t *testing.T) {
+	groups := []PersonStatusGroup{
+		{Person: "Ann", TotalIssues: 3},
+		{Person: "Bob", TotalIssues: 1},
+		{Person: "Cy", TotalIssues: 2},
+	}
+
+	tests := []struct {
+		name      string
+		minIssues string
+		want      string
+	}{
+		{name: "unset shows everyone", minIssues: "", want: "[Ann Bob Cy]"},
+		{name: "zero shows everyone", minIssues: "0", want: "[Ann Bob Cy]"},
+		{name: "invalid shows everyone", minIssues: "two", want: "[Ann Bob Cy]"},
+		{name: "threshold is inclusive", minIssues: "2", want: "[Ann Cy]"},
+		{name: "above everyone hides all", minIssues: "4", want: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MIN_ISSUES_PER_PERSON", tt.minIssues)
+			var people []string
+			for _, group := range dropSmallGroups(groups) {
+				people = append(people, group.Person)
+			}
+			if got := fmt.Sprint(people); got != tt.want {
+				t.Errorf("kept %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// benchmarkSearchServer serves pages of 100 issues, each with a long summary, the
+// last without a next page token.
+func benchmarkSearchServer(b *testing.B, pages int) *httptest.Server {
+	b.Helper()
+	bodies := make([][]byte, pages)
+	for p := range bodies {
+		page := JiraSearchResponse{Issues: stubIssues(p*100+1, 100)}
+		for i := range page.Issues {
+			page.Issues[i].Fields.Summary = strings.Repeat("summary text ", 40)
+			page.Issues[i].Fields.Status.Name = "POST"
+		}
+		if p+1 < pages {
+			page.NextPageToken = fmt.Sprint(p + 1)
+		}
+		data, err := json.Marshal(page)
+		if err != nil {
+			b.Fatal(err)
+		}
+		bodies[p] = data
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			NextPageToken string `json:"nextPageToken"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		p := 0
+		fmt.Sscan(body.NextPageToken, &p)
+		w.Write(bodies[p])
+	}))
+	b.Cleanup(server.Close)
+	return server
+}
+
+// liveHeap returns the bytes of reachable heap objects after a collection.
+func liveHeap() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapAlloc
+}
+
+// BenchmarkStreamJiraIssues compares grouping pages as they arrive with
+// buffering every response first, as the report did before streaming. Besides
+// the allocations, retained-B is the heap still held when the fetch ends, which
+// is what streaming saves.
+func BenchmarkStreamJiraIssues(b *testing.B) {
+	server := benchmarkSearchServer(b, 20)
+	// Pagination progress is logged for every page
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		b.Fatal(err)
+	}
+	savedStdout := os.Stdout
+	os.Stdout = devNull
+	defer func() { os.Stdout = savedStdout; devNull.Close() }()
+
+	streaming := func(b *testing.B) interface{} {
+		grouper := newPersonGrouper(modeQA)
+		_, err := streamJiraIssues(server.URL, "token", "project = MTV", fetchOptions{}, func(page JiraSearchResponse) error {
+			grouper.add(page)
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		return grouper
+	}
+	buffered := func(b *testing.B) interface{} {
+		var responses []JiraSearchResponse
+		_, err := streamJiraIssues(server.URL, "token", "project = MTV", fetchOptions{}, func(page JiraSearchResponse) error {
+			responses = append(responses, page)
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		return responses
+	}
+
+	for _, bm := range []struct {
+		name  string
+		fetch func(*testing.B) interface{}
+	}{
+		{"streaming", streaming},
+		{"buffered", buffered},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				bm.fetch(b)
+			}
+
+			b.StopTimer()
+			before := liveHeap()
+			held := bm.fetch(b)
+			after := liveHeap()
+			runtime.KeepAlive(held)
+			if after > before {
+				b.ReportMetric(float64(after-before), "retained-B")
+			}
+		})
+	}
+}
+
+func TestEscapeSlackText(t *testing.T) {
+	const zwsp = "\u200b"
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "shortcode", in: ":white_check_mark:", want: ":" + zwsp + "white_check_mark:"},
+		{name: "shortcode in a summary", in: "Fix :port: mapping", want: "Fix :" + zwsp + "port: mapping"},
+		{name: "shortcode in brackets", in: "(:smile:)", want: "(:" + zwsp + "smile:)"},
+		{name: "adjacent shortcodes", in: ":a::b:", want: ":" + zwsp + "a::" + zwsp + "b:"},
+		{name: "shortcode with plus and dash", in: "vote :+1: or :-1:", want: "vote :" + zwsp + "+1: or :" + zwsp + "-1:"},
+		{name: "colons inside a word", in: "a:b:c", want: "a:b:c"},
+		{name: "time", in: "Failed at 10:30:45", want: "Failed at 10:30:45"},
+		{name: "key value", in: "mode:warm, size: 5", want: "mode:warm, size: 5"},
+		{name: "URL", in: "see https://example.com:8443/path", want: "see https://example.com:8443/path"},
+		{name: "control characters", in: "a <b> & c", want: "a &lt;b&gt; &amp; c"},
+		{name: "empty", in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeSlackText(tt.in); got != tt.want {
+				t.Errorf("escapeSlackText(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscapeSlackMrkdwn(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "bold", in: "*Jane*", want: "∗Jane∗"},
+		{name: "italic", in: "ON_QA", want: "ONˍQA"},
+		{name: "strikethrough", in: "~old~ name", want: "∼old∼ name"},
+		{name: "code", in: "`cmd`", want: "ˋcmdˋ"},
+		{name: "control characters", in: "R&D <QE>", want: "R&amp;D &lt;QE&gt;"},
+		{name: "shortcode", in: "Team :rocket:", want: "Team :\u200brocket:"},
+		{name: "everything at once", in: "*a_b~c`<d>&", want: "∗aˍb∼cˋ&lt;d&gt;&amp;"},
+		{name: "plain name", in: "Jane Doe", want: "Jane Doe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := escapeSlackMrkdwn(tt.in); got != tt.want {
+				t.Errorf("escapeSlackMrkdwn(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+// Missing PR badge
+//
+// Issues past code review should have a PR linked. With FLAG_MISSING_PR=true,
+// issues in MISSING_PR_STATUSES (comma-separated, default POST and ON_QA) that
+// have no Git Pull Request show "⚠️ no PR linked" in place of the PR links, in
+// the daily report and slash command results alike. Unlike epics without PRs,
+// which the report drops, these issues are only flagged. Epics are never flagged.
+package main
+
+import "strings"
+
+// defaultMissingPRStatuses are the statuses in which an issue should have a PR.
+var defaultMissingPRStatuses = []string{"POST", "ON_QA"}
+
+// missingPRBadge replaces the PR links of a flagged issue.
+const missingPRBadge = "⚠️ no PR linked"
+
+// missingPRFlagged reports whether the issue should be flagged for having no PR.
+func missingPRFlagged(issue IssueItem) bool {
+	if !envBool("FLAG_MISSING_PR", false) || len(issue.GitPullRequest) > 0 || strings.EqualFold(issue.IssueType, "Epic") {
+		return false
+	}
+	statuses := envList("MISSING_PR_STATUSES")
+	if len(statuses) == 0 {
+		statuses = defaultMissingPRStatuses
+	}
+	for _, status := range statuses {
+		if strings.EqualFold(canonicalStatus(status), issue.Status) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+// Oversized messages
+//
+// The block limits keep messages under Slack's block count and text lengths, but
+// a message can still exceed the overall payload size and be rejected with
+// msg_too_long. By default (SLACK_MSG_TOO_LONG=split) such a message is split in
+// half by blocks and each half is posted separately, halving again as needed.
+// Replies stay in their thread; a top-level message continues as a reply to its
+// first half. The ts of every part is kept in the report manifest, so updates and
+// 👀 reactions find the later parts too. SLACK_MSG_TOO_LONG=fail keeps the error.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// isMsgTooLong reports whether err is Slack's msg_too_long error.
+func isMsgTooLong(err error) bool {
+	var apiErr *SlackAPIError
+	return errors.As(err, &apiErr) && apiErr.Code == "msg_too_long"
+}
+
+// msgTooLongSplits reports whether oversized messages are split (the default).
+func msgTooLongSplits() bool {
+	return !strings.EqualFold(strings.TrimSpace(os.Getenv("SLACK_MSG_TOO_LONG")), "fail")
+}
+
+// sendSplitMessage posts the two halves of blocks as separate messages and
+// returns the ts of every message posted, in order (more than two if a half had
+// to be split again). If only some parts could be posted, their ts values are
+// returned with the error and logged, since callers drop them on failure.
+func sendSplitMessage(botToken, channel, threadTS string, blocks []map[string]interface{}, render renderOptions) ([]string, error) {
+	half := len(blocks) / 2
+	logf("   ⚠️  Slack rejected a %d-block message as msg_too_long, sending it in two parts\n", len(blocks))
+
+	parts, err := sendMessageParts(botToken, channel, threadTS, blocks[:half], render)
+	if err != nil {
+		return parts, err
+	}
+	if threadTS == "" {
+		threadTS = parts[0]
+	}
+	rest, err := sendMessageParts(botToken, channel, threadTS, blocks[half:], render)
+	parts = append(parts, rest...)
+	if err != nil {
+		logf("   ⚠️  Part(s) %s of the split message were posted, the rest failed: %v\n", strings.Join(parts, ", "), err)
+		return parts, err
+	}
+	return parts, nil
+}
+
+// updateSplitMessage replaces the blocks of a message posted in parts (see
+// sendSplitMessage), spreading them evenly over the parts in order. A part left
+// without blocks says so rather than keeping stale ones.
+func (t *reportThread) updateSplitMessage(parts []string, blocks []map[string]interface{}) error {
+	size := (len(blocks) + len(parts) - 1) / len(parts)
+	for i, ts := range parts {
+		start, end := min(i*size, len(blocks)), min((i+1)*size, len(blocks))
+		chunk := blocks[start:end]
+		if len(chunk) == 0 {
+			chunk = []map[string]interface{}{
+				{
+					"type": "context",
+					"elements": []map[string]string{
+						{"type": "mrkdwn", "text": "_(continued above)_"},
+					},
+				},
+			}
+		}
+		if err := t.update(ts, chunk); err != nil {
+			return fmt.Errorf("part %d/%d: %w", i+1, len(parts), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// slackPost is a chat.postMessage call received by the Slack stub.
+type slackPost struct {
+	ThreadTS string
+	Blocks   int
+}
+
+// slackPostStub serves chat.postMessage, answering with respond and recording
+// every call. A successful post's ts is its call number.
+func slackPostStub(t *testing.T, respond func(post slackPost, call int) string) *[]slackPost {
+	t.Helper()
+	var posts []slackPost
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			ThreadTS string            `json:"thread_ts"`
+			Blocks   []json.RawMessage `json:"blocks"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		post := slackPost{ThreadTS: payload.ThreadTS, Blocks: len(payload.Blocks)}
+		posts = append(posts, post)
+		if code := respond(post, len(posts)); code != "" {
+			json.NewEncoder(w).Encode(SlackMessageResponse{Error: code})
+			return
+		}
+		json.NewEncoder(w).Encode(SlackMessageResponse{OK: true, TS: fmt.Sprint(len(posts))})
+	}))
+	t.Cleanup(server.Close)
+
+	previous := slackAPIBase
+	slackAPIBase = server.URL
+	t.Cleanup(func() { slackAPIBase = previous })
+	return &posts
+}
+
+// textBlocks returns n section blocks.
+func textBlocks(n int) []map[string]interface{} {
+	blocks := make([]map[string]interface{}, n)
+	for i := range blocks {
+		blocks[i] = map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": fmt.Sprintf("block %d", i)},
+		}
+	}
+	return blocks
+}
+
+func TestSendSplitMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		blocks    int
+		maxBlocks int // Slack rejects messages with more blocks as msg_too_long
+		threadTS  string
+		wantParts []string // ts of each message posted, the first being the message's
+		wantPosts []slackPost
+	}{
+		{
+			name:      "fits without splitting",
+			blocks:    4,
+			maxBlocks: 4,
+			threadTS:  "100.1",
+			wantParts: []string{"1"},
+			wantPosts: []slackPost{{"100.1", 4}},
+		},
+		{
+			name:      "reply split in half stays in the thread",
+			blocks:    8,
+			maxBlocks: 4,
+			threadTS:  "100.1",
+			wantParts: []string{"2", "3"},
+			wantPosts: []slackPost{{"100.1", 8}, {"100.1", 4}, {"100.1", 4}},
+		},
+		{
+			name:      "halves are split again",
+			blocks:    8,
+			maxBlocks: 2,
+			threadTS:  "100.1",
+			wantParts: []string{"3", "4", "6", "7"},
+			wantPosts: []slackPost{
+				{"100.1", 8},
+				{"100.1", 4}, {"100.1", 2}, {"100.1", 2},
+				{"100.1", 4}, {"100.1", 2}, {"100.1", 2},
+			},
+		},
+		{
+			name:      "top-level message continues as a reply to its first half",
+			blocks:    6,
+			maxBlocks: 3,
+			threadTS:  "",
+			wantParts: []string{"2", "3"},
+			wantPosts: []slackPost{{"", 6}, {"", 3}, {"2", 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			posts := slackPostStub(t, func(post slackPost, call int) string {
+				if post.Blocks > tt.maxBlocks {
+					return "msg_too_long"
+				}
+				return ""
+			})
+
+			parts, err := sendMessageParts("xoxb-test", "C1", tt.threadTS, textBlocks(tt.blocks), envRender)
+			if err != nil {
+				t.Fatalf("sendMessageParts: %v", err)
+			}
+			if fmt.Sprint(parts) != fmt.Sprint(tt.wantParts) {
+				t.Errorf("parts = %v, want %v", parts, tt.wantParts)
+			}
+			if fmt.Sprint(*posts) != fmt.Sprint(tt.wantPosts) {
+				t.Errorf("posts = %v, want %v", *posts, tt.wantPosts)
+			}
+		})
+	}
+}
+
+func TestSendSplitMessageSecondHalfFails(t *testing.T) {
+	slackPostStub(t, func(post slackPost, call int) string {
+		switch {
+		case post.Blocks > 2:
+			return "msg_too_long"
+		case call > 2:
+			return "ratelimited"
+		}
+		return ""
+	})
+
+	ts, err := sendToSlackAPI("xoxb-test", "C1", "", textBlocks(4), envRender)
+	if !isSlackError(err, "ratelimited") {
+		t.Fatalf("err = %v, want ratelimited", err)
+	}
+	if ts != "2" {
+		t.Errorf("ts = %q, want the first half's ts 2", ts)
+	}
+}
+
+func TestSendSplitMessageFailSetting(t *testing.T) {
+	t.Setenv("SLACK_MSG_TOO_LONG", "fail")
+	posts := slackPostStub(t, func(post slackPost, call int) string { return "msg_too_long" })
+
+	if _, err := sendToSlackAPI("xoxb-test", "C1", "", textBlocks(4), envRender); !isMsgTooLong(err) {
+		t.Fatalf("err = %v, want msg_too_long", err)
+	}
+	if len(*posts) != 1 {
+		t.Errorf("posted %d times, want 1", len(*posts))
+	}
+}
+
+// isSlackError reports whether err is the Slack API error code.
+func isSlackError(err error, code string) bool {
+	apiErr, ok := err.(*SlackAPIError)
+	return ok && apiErr.Code == code
+}
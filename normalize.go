@@ -0,0 +1,81 @@
+// Issue normalization
+//
+// JIRA may return null or omit fields entirely (e.g. null labels, no components
+// array, an empty status name). normalizeIssues runs on every fetched page so the
+// rest of the code can rely on non-nil slices, trimmed names and non-empty
+// status/issue type names.
+package main
+
+import "strings"
+
+// unknownValue substitutes for required names JIRA returned empty.
+const unknownValue = "Unknown"
+
+// normalizeIssues cleans up every issue of a search response in place.
+func normalizeIssues(resp *JiraSearchResponse) {
+	if resp.Issues == nil {
+		resp.Issues = []JiraIssue{}
+	}
+	for i := range resp.Issues {
+		normalizeIssue(&resp.Issues[i])
+	}
+}
+
+// normalizeIssue guarantees non-nil slices, trims whitespace in names, maps status
+// aliases to their canonical spelling (see statusAliases), and substitutes
+// "Unknown" for missing status and issue type names.
+func normalizeIssue(issue *JiraIssue) {
+	fields := &issue.Fields
+
+	fields.Summary = strings.TrimSpace(fields.Summary)
+
+	fields.Status.Name = canonicalStatus(strings.TrimSpace(fields.Status.Name))
+	if fields.Status.Name == "" {
+		logf("      ⚠️  %s has no status name, using %q\n", issue.Key, unknownValue)
+		fields.Status.Name = unknownValue
+	}
+
+	fields.IssueType.Name = strings.TrimSpace(fields.IssueType.Name)
+	if fields.IssueType.Name == "" {
+		logf("      ⚠️  %s has no issue type name, using %q\n", issue.Key, unknownValue)
+		fields.IssueType.Name = unknownValue
+	}
+
+	if fields.Assignee != nil {
+		fields.Assignee.DisplayName = strings.TrimSpace(fields.Assignee.DisplayName)
+		if fields.Assignee.DisplayName == "" {
+			fields.Assignee = nil
+		}
+	}
+	if fields.QAContact != nil {
+		fields.QAContact.DisplayName = strings.TrimSpace(fields.QAContact.DisplayName)
+		if fields.QAContact.DisplayName == "" {
+			fields.QAContact = nil
+		}
+	}
+	if fields.Priority != nil {
+		fields.Priority.Name = strings.TrimSpace(fields.Priority.Name)
+	}
+
+	components := fields.Components[:0]
+	for _, comp := range fields.Components {
+		comp.Name = strings.TrimSpace(comp.Name)
+		if comp.Name != "" {
+			components = append(components, comp)
+		}
+	}
+	if components == nil {
+		components = make([]struct {
+			Name string `json:"name"`
+		}, 0)
+	}
+	fields.Components = components
+
+	labels := make([]string, 0, len(fields.Labels))
+	for _, label := range fields.Labels {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	fields.Labels = labels
+}
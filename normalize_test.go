@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNormalizeIssue(t *testing.T) {
+	useStatusAliases(t, "Code Review=POST")
+
+	tests := []struct {
+		name  string
+		issue string
+		check func(JiraIssue) (got, want string)
+	}{
+		{
+			name:  "summary is trimmed",
+			issue: `{"key": "MTV-1", "fields": {"summary": "  Fix it \n", "status": {"name": "POST"}, "issuetype": {"name": "Bug"}}}`,
+			check: func(i JiraIssue) (string, string) { return i.Fields.Summary, "Fix it" },
+		},
+		{
+			name:  "status alias maps to the canonical name",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": " on qa "}, "issuetype": {"name": "Bug"}}}`,
+			check: func(i JiraIssue) (string, string) { return i.Fields.Status.Name, "ON_QA" },
+		},
+		{
+			name:  "configured status alias",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "code review"}, "issuetype": {"name": "Bug"}}}`,
+			check: func(i JiraIssue) (string, string) { return i.Fields.Status.Name, "POST" },
+		},
+		{
+			name:  "unknown status keeps its spelling",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "Triage "}, "issuetype": {"name": "Bug"}}}`,
+			check: func(i JiraIssue) (string, string) { return i.Fields.Status.Name, "Triage" },
+		},
+		{
+			name:  "empty status name",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "  "}, "issuetype": {"name": "Bug"}}}`,
+			check: func(i JiraIssue) (string, string) { return i.Fields.Status.Name, unknownValue },
+		},
+		{
+			name:  "missing issue type",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}}}`,
+			check: func(i JiraIssue) (string, string) { return i.Fields.IssueType.Name, unknownValue },
+		},
+		{
+			name:  "assignee name is trimmed",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "assignee": {"displayName": " Jane Doe "}}}`,
+			check: func(i JiraIssue) (string, string) { return displayName(i.Fields.Assignee), "Jane Doe" },
+		},
+		{
+			name:  "assignee without a name is unassigned",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "assignee": {"displayName": " ", "accountId": "abc"}}}`,
+			check: func(i JiraIssue) (string, string) { return fmt.Sprint(i.Fields.Assignee == nil), "true" },
+		},
+		{
+			name:  "QA contact without a name is unset",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "ON_QA"}, "customfield_12315948": {"displayName": ""}}}`,
+			check: func(i JiraIssue) (string, string) { return fmt.Sprint(i.Fields.QAContact == nil), "true" },
+		},
+		{
+			name:  "priority name is trimmed",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "priority": {"name": "Major "}}}`,
+			check: func(i JiraIssue) (string, string) { return i.Fields.Priority.Name, "Major" },
+		},
+		{
+			name:  "blank components are dropped",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "components": [{"name": " UI "}, {"name": ""}, {"name": "Backend"}]}}`,
+			check: func(i JiraIssue) (string, string) { return fmt.Sprint(componentNames(i)), "[UI Backend]" },
+		},
+		{
+			name:  "null components become empty",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "components": null}}`,
+			check: func(i JiraIssue) (string, string) {
+				return fmt.Sprint(i.Fields.Components != nil, len(i.Fields.Components)), "true 0"
+			},
+		},
+		{
+			name:  "blank labels are dropped",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "labels": [" mtv-ui ", " ", "triaged"]}}`,
+			check: func(i JiraIssue) (string, string) { return fmt.Sprintf("%q", i.Fields.Labels), `["mtv-ui" "triaged"]` },
+		},
+		{
+			name:  "null labels become empty",
+			issue: `{"key": "MTV-1", "fields": {"status": {"name": "POST"}, "labels": null}}`,
+			check: func(i JiraIssue) (string, string) {
+				return fmt.Sprint(i.Fields.Labels != nil, len(i.Fields.Labels)), "true 0"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := parseTestIssue(t, tt.issue)
+			normalizeIssue(&issue)
+			if got, want := tt.check(issue); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNormalizeIssuesWithoutIssues(t *testing.T) {
+	var resp JiraSearchResponse
+	normalizeIssues(&resp)
+	if resp.Issues == nil || len(resp.Issues) != 0 {
+		t.Errorf("Issues = %#v, want an empty slice", resp.Issues)
+	}
+}
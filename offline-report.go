@@ -0,0 +1,178 @@
+// Offline reports
+//
+// Formatting work shouldn't need live JIRA access. -dump-fetch=issues.json saves
+// the search responses of a normal report run to a file, and
+// -from-file=issues.json builds the report from such a file instead of querying
+// JIRA. The file may also be a single raw response of the search API, e.g. saved
+// with curl. Add -dry-run to print the Slack messages as JSON on stdout (logs go
+// to stderr) rather than posting them; dry runs don't touch the run state, the
+// manifests or watchers.
+//
+// Dumps carry a schema version. Files written by an incompatible version are
+// rejected with a message asking to capture them again.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// fetchDumpSchemaVersion is the version of the -dump-fetch file format.
+const fetchDumpSchemaVersion = 1
+
+// fromFile is the -from-file path; empty means issues are fetched from JIRA.
+var fromFile string
+
+// fetchDumpPath is the -dump-fetch path; empty means responses aren't saved.
+var fetchDumpPath string
+
+// dryRun prints the Slack messages instead of posting them (set by -dry-run).
+var dryRun bool
+
+// fetchDump is the -dump-fetch file: the search responses of one report run.
+type fetchDump struct {
+	SchemaVersion int               `json:"schema_version"`
+	Mode          reportMode        `json:"mode"`
+	JQL           string            `json:"jql"`
+	FetchedAt     time.Time         `json:"fetched_at"`
+	Pages         []json.RawMessage `json:"pages"`
+}
+
+// fetchCapture collects successful search responses while a dump is being recorded.
+var fetchCapture struct {
+	sync.Mutex
+	enabled bool
+	pages   []json.RawMessage
+}
+
+// startFetchCapture starts collecting search responses.
+func startFetchCapture() {
+	fetchCapture.Lock()
+	defer fetchCapture.Unlock()
+	fetchCapture.enabled = true
+	fetchCapture.pages = nil
+}
+
+// stopFetchCapture stops collecting and returns the responses collected since start.
+func stopFetchCapture() []json.RawMessage {
+	fetchCapture.Lock()
+	defer fetchCapture.Unlock()
+	pages := fetchCapture.pages
+	fetchCapture.enabled = false
+	fetchCapture.pages = nil
+	return pages
+}
+
+// captureFetchResponse keeps a search response body while capturing.
+func captureFetchResponse(body []byte) {
+	fetchCapture.Lock()
+	defer fetchCapture.Unlock()
+	if fetchCapture.enabled {
+		fetchCapture.pages = append(fetchCapture.pages, json.RawMessage(append([]byte(nil), body...)))
+	}
+}
+
+// writeFetchDump saves the captured responses of a report run to path.
+func writeFetchDump(path string, mode reportMode, jql string, pages []json.RawMessage) error {
+	dump := fetchDump{
+		SchemaVersion: fetchDumpSchemaVersion,
+		Mode:          mode,
+		JQL:           jql,
+		FetchedAt:     time.Now().UTC(),
+		Pages:         pages,
+	}
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal fetch dump: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write fetch dump: %w", err)
+	}
+	return nil
+}
+
+// loadFetchDump reads a -dump-fetch file, or a single raw search response, for the mode.
+func loadFetchDump(path string, mode reportMode) (fetchDump, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fetchDump{}, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var probe struct {
+		SchemaVersion *int            `json:"schema_version"`
+		Issues        json.RawMessage `json:"issues"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return fetchDump{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	switch {
+	case probe.SchemaVersion != nil:
+		if *probe.SchemaVersion != fetchDumpSchemaVersion {
+			return fetchDump{}, fmt.Errorf("%s has schema version %d, this build reads version %d; capture it again with -dump-fetch", path, *probe.SchemaVersion, fetchDumpSchemaVersion)
+		}
+	case probe.Issues != nil:
+		// A raw search response, saved from the API directly
+		return fetchDump{SchemaVersion: fetchDumpSchemaVersion, Mode: mode, Pages: []json.RawMessage{data}}, nil
+	default:
+		return fetchDump{}, fmt.Errorf("%s is neither a -dump-fetch file nor a JIRA search response", path)
+	}
+
+	var dump fetchDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return fetchDump{}, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if dump.Mode != mode {
+		return fetchDump{}, fmt.Errorf("%s holds issues of the %s report, run with -mode=%s", path, dump.Mode, dump.Mode)
+	}
+	return dump, nil
+}
+
+// replayFetchDump passes the dump's pages to handlePage as if they were just fetched.
+func replayFetchDump(dump fetchDump, handlePage func(JiraSearchResponse) error) (fetchStats, error) {
+	var stats fetchStats
+	for i, raw := range dump.Pages {
+		var page JiraSearchResponse
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return stats, fmt.Errorf("failed to parse page %d: %w", i+1, err)
+		}
+		normalizeIssues(&page)
+
+		stats.Pages++
+		stats.Issues += len(page.Issues)
+		if err := handlePage(page); err != nil {
+			return stats, err
+		}
+	}
+	logf("      Loaded %d issues from %s\n", stats.Issues, fromFile)
+	return stats, nil
+}
+
+// dryRunOutput receives the -dry-run messages; logs move to stderr during dry runs.
+var dryRunOutput = os.Stdout
+
+// dryRunMessages counts the messages printed by -dry-run, for their stand-in ts.
+var dryRunMessages int
+
+// printDryRunMessage writes a message that would have been posted to stdout and
+// returns a stand-in ts for it.
+func printDryRunMessage(channel, threadTS string, blocks []map[string]interface{}) (string, error) {
+	plainBlockText(blocks, envRender)
+	if err := validateBlocks(blocks); err != nil {
+		return "", fmt.Errorf("invalid message: %w", err)
+	}
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"channel":   channel,
+		"thread_ts": threadTS,
+		"blocks":    blocks,
+	}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal message: %w", err)
+	}
+	fmt.Fprintln(dryRunOutput, string(data))
+	dryRunMessages++
+	return fmt.Sprintf("dry-run.%06d", dryRunMessages), nil
+}
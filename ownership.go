@@ -0,0 +1,51 @@
+// Ownership age
+//
+// With SHOW_OWNERSHIP_AGE=true each issue in the daily report shows how long it
+// has been assigned to its current assignee ("(owned 4d)"). The start is the most
+// recent assignee change in the changelog, or the creation date for issues that
+// were never reassigned.
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// assignedSince returns when the issue was last assigned.
+// Issues never reassigned count from their creation date. Zero if the changelog
+// wasn't fetched, since the creation date alone says nothing about reassignments.
+func assignedSince(issue JiraIssue) time.Time {
+	if issue.Changelog == nil {
+		return time.Time{}
+	}
+
+	var since time.Time
+	for _, history := range issue.Changelog.Histories {
+		for _, item := range history.Items {
+			if item.Field != "assignee" {
+				continue
+			}
+			created, err := time.Parse(jiraTimeLayout, history.Created)
+			if err == nil && created.After(since) {
+				since = created
+			}
+		}
+	}
+
+	if since.IsZero() && issue.Fields.Created != "" {
+		if created, err := time.Parse(jiraTimeLayout, issue.Fields.Created); err == nil {
+			since = created
+		}
+	}
+
+	return since
+}
+
+// ownershipSuffix renders the ownership age shown after an issue's status, or "" if
+// SHOW_OWNERSHIP_AGE is off or the age is unknown.
+func ownershipSuffix(issue IssueItem) string {
+	if issue.AssignedSince.IsZero() || !envBool("SHOW_OWNERSHIP_AGE", false) {
+		return ""
+	}
+	return fmt.Sprintf(" (owned %s)", formatAge(reportNow().Sub(issue.AssignedSince)))
+}
@@ -0,0 +1,35 @@
+// Existing parent thread
+//
+// With PARENT_CHANNEL and PARENT_TS set (the channel ID and ts of an existing
+// message, e.g. an announcement), no header message is posted: the whole report,
+// header included, is posted as replies under that message. Both must be set together.
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// parentThread returns the configured parent message, or empty strings if none is set.
+func parentThread() (channel, ts string) {
+	return strings.TrimSpace(os.Getenv("PARENT_CHANNEL")), strings.TrimSpace(os.Getenv("PARENT_TS"))
+}
+
+// validateParentThread checks that PARENT_CHANNEL and PARENT_TS are set together.
+func validateParentThread() error {
+	channel, ts := parentThread()
+	if (channel == "") != (ts == "") {
+		return errors.New("PARENT_CHANNEL and PARENT_TS must be set together")
+	}
+	return nil
+}
+
+// attach continues the thread of an existing message instead of posting a header
+// message; the header is posted as the first reply.
+func (t *reportThread) attach(threadTS string, header []map[string]interface{}) error {
+	t.header = header
+	t.threadTS = threadTS
+	_, err := t.reply(header)
+	return err
+}
@@ -0,0 +1,70 @@
+// Person links
+//
+// The name in each person's header links to JIRA. PERSON_LINK picks the target:
+// "filter" (the default) searches the project for the person's open issues as
+// assignee or QA contact, "profile" opens their JIRA profile page (falling back
+// to the filter when the search didn't return their account ID or username), and
+// "none" leaves the name as plain text. "Unassigned" is never linked.
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// personLinkStyle returns PERSON_LINK, defaulting to "filter".
+func (r renderOptions) personLinkStyle() string {
+	switch style := strings.ToLower(strings.TrimSpace(r.setting("PERSON_LINK"))); style {
+	case "", "filter":
+		return "filter"
+	case "profile", "none":
+		return style
+	default:
+		logf("⚠️  Unknown PERSON_LINK %q, linking to the issue filter\n", style)
+		return "filter"
+	}
+}
+
+// personOpenIssuesJQL returns the query for a person's open issues in the
+// project, by account ID, username or display name, whichever is known.
+func personOpenIssuesJQL(group PersonStatusGroup) string {
+	value := sanitizeJQLValue(group.Person)
+	switch {
+	case group.AccountID != "":
+		value = sanitizeJQLValue(group.AccountID)
+	case group.Username != "":
+		value = sanitizeJQLValue(group.Username)
+	}
+	return fmt.Sprintf("project = MTV AND statusCategory != Done AND (assignee = %s OR %s = %s)", value, qaContactJQLField, value)
+}
+
+// personLinkURL returns where the person's name links to, or "" for no link.
+func personLinkURL(jiraURL string, group PersonStatusGroup, render renderOptions) string {
+	if group.Person == "Unassigned" || jiraURL == "" {
+		return ""
+	}
+	base := strings.TrimRight(jiraURL, "/")
+	switch render.personLinkStyle() {
+	case "none":
+		return ""
+	case "profile":
+		if group.AccountID != "" {
+			return base + "/jira/people/" + url.PathEscape(group.AccountID)
+		}
+		if group.Username != "" {
+			return base + "/secure/ViewProfile.jspa?name=" + url.QueryEscape(group.Username)
+		}
+	}
+	return jiraSearchURL(jiraURL, personOpenIssuesJQL(group))
+}
+
+// personHeaderName returns the escaped name for the person's header, linked when
+// PERSON_LINK asks for it.
+func personHeaderName(jiraURL string, group PersonStatusGroup, render renderOptions) string {
+	name := escapeSlackMrkdwn(shortDisplayName(group.Person))
+	if link := personLinkURL(jiraURL, group, render); link != "" {
+		return fmt.Sprintf("<%s|%s>", link, name)
+	}
+	return name
+}
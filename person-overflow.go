@@ -0,0 +1,105 @@
+// Person overflow
+//
+// On days when many people have items, MAX_PEOPLE caps how many people get their
+// own section in the thread (MAX_PERSONS_PER_THREAD is the older name). By
+// default the cutoff keeps the people with the most issues (ties broken by name,
+// so it is deterministic); MAX_PEOPLE_SORT=name keeps the first people
+// alphabetically instead. Everyone else is rolled into a single summary reply
+// that links to their issues in JIRA.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// overflowLinkMaxIssues caps the issue keys put in the overflow reply's JIRA
+// link, keeping the URL well within Slack's text limit.
+const overflowLinkMaxIssues = 100
+
+// maxPeople returns the configured cap on people shown in full (0 for unlimited).
+func maxPeople() int {
+	return envInt("MAX_PEOPLE", envInt("MAX_PERSONS_PER_THREAD", 0))
+}
+
+// overflowByName reports whether the cutoff keeps people alphabetically rather than by issue count.
+func overflowByName() bool {
+	switch sortBy := strings.ToLower(strings.TrimSpace(os.Getenv("MAX_PEOPLE_SORT"))); sortBy {
+	case "name":
+		return true
+	case "", "issues":
+		return false
+	default:
+		logf("⚠️  Unknown MAX_PEOPLE_SORT %q, keeping the people with the most issues\n", sortBy)
+		return false
+	}
+}
+
+// splitPersonOverflow returns the groups to render in full and the ones to summarize.
+// A limit of 0 or less means unlimited. Shown groups keep their original order.
+// With byName the first people alphabetically are shown instead of those with the most issues.
+func splitPersonOverflow(groups []PersonStatusGroup, limit int, byName bool) ([]PersonStatusGroup, []PersonStatusGroup) {
+	if limit <= 0 || len(groups) <= limit {
+		return groups, nil
+	}
+
+	ranked := make([]PersonStatusGroup, len(groups))
+	copy(ranked, groups)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if !byName && ranked[i].TotalIssues != ranked[j].TotalIssues {
+			return ranked[i].TotalIssues > ranked[j].TotalIssues
+		}
+		return ranked[i].Person < ranked[j].Person
+	})
+
+	keep := make(map[string]bool, limit)
+	for _, group := range ranked[:limit] {
+		keep[group.Person] = true
+	}
+
+	var shown []PersonStatusGroup
+	for _, group := range groups {
+		if keep[group.Person] {
+			shown = append(shown, group)
+		}
+	}
+
+	return shown, ranked[limit:]
+}
+
+// buildOverflowBlocks creates the final reply summarizing people that were not
+// rendered in full, with a link to their issues in JIRA.
+func buildOverflowBlocks(jiraURL string, overflow []PersonStatusGroup) []map[string]interface{} {
+	totalIssues := 0
+	var names, keys []string
+	for _, group := range overflow {
+		totalIssues += group.TotalIssues
+		names = append(names, fmt.Sprintf("%s (%d)", escapeSlackMrkdwn(shortDisplayName(group.Person)), group.TotalIssues))
+		for _, status := range orderedStatuses([]PersonStatusGroup{group}) {
+			for _, issue := range group.StatusGroups[status] {
+				keys = append(keys, issue.Key)
+			}
+		}
+	}
+
+	link := ""
+	if len(keys) > 0 && len(keys) <= overflowLinkMaxIssues {
+		jql := fmt.Sprintf("key in (%s) ORDER BY assignee", strings.Join(keys, ", "))
+		link = fmt.Sprintf(" — <%s|open in JIRA>", jiraSearchURL(jiraURL, jql))
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("*…and %d more people with %d issue(s)*%s, or run `/issues <name>` for details\n%s",
+					len(overflow), totalIssues, link, strings.Join(names, ", ")),
+			},
+		},
+	}
+	limitBlockText(blocks)
+	return blocks
+}
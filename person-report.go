@@ -0,0 +1,111 @@
+// Single-person report
+//
+// For 1:1s, -person="John Doe" runs the normal report (same query, filters and
+// grouping) but posts only that person's section, as a single message in the
+// report channel rather than a thread. With -dry-run the message is printed.
+// The name is matched like the slash command does: case-insensitive, ignoring
+// Slack decorations, and as a partial match. An exact name wins over partial
+// matches; several partial matches are listed so the name can be narrowed.
+// Matching nobody exits with exitPersonNotFound and the names in the report.
+//
+// Single-person runs don't touch the run state, the manifests or watchers.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// exitPersonNotFound is the exit code of a -person run that matched nobody.
+const exitPersonNotFound = 3
+
+// onlyPerson is the -person name; empty means the whole team's report.
+var onlyPerson string
+
+// personNotFoundError reports a -person name that matched nobody in the report.
+type personNotFoundError struct {
+	name      string
+	available []string
+}
+
+func (e *personNotFoundError) Error() string {
+	if len(e.available) == 0 {
+		return fmt.Sprintf("-person %q matched nobody: the report has no issues", e.name)
+	}
+	return fmt.Sprintf("-person %q matched nobody in the report. Available names:\n   %s", e.name, strings.Join(e.available, "\n   "))
+}
+
+// findPersonGroup picks the person group matching name. Each name variant is
+// tried in turn (see userNameVariants), first as an exact match and then as a
+// partial one.
+func findPersonGroup(groups []PersonStatusGroup, name string) (PersonStatusGroup, error) {
+	for _, variant := range userNameVariants(name) {
+		variantLower := strings.ToLower(variant)
+		var partial []PersonStatusGroup
+		for _, group := range groups {
+			personLower := strings.ToLower(group.Person)
+			if personLower == variantLower {
+				return group, nil
+			}
+			if strings.Contains(personLower, variantLower) {
+				partial = append(partial, group)
+			}
+		}
+
+		switch len(partial) {
+		case 0:
+			continue
+		case 1:
+			return partial[0], nil
+		}
+		var names []string
+		for _, group := range partial {
+			names = append(names, group.Person)
+		}
+		sort.Strings(names)
+		return PersonStatusGroup{}, fmt.Errorf("-person %q matches %d people, use a longer name:\n   %s", name, len(names), strings.Join(names, "\n   "))
+	}
+
+	var available []string
+	for _, group := range groups {
+		available = append(available, group.Person)
+	}
+	sort.Strings(available)
+	return PersonStatusGroup{}, &personNotFoundError{name: name, available: available}
+}
+
+// sendPersonReport posts the section of the -person match as one message, headed
+// by the report title and date.
+func sendPersonReport(mode reportMode, jiraURL, slackBotToken, slackChannel, date string, personGroups []PersonStatusGroup, stats *runStats) error {
+	group, err := findPersonGroup(personGroups, onlyPerson)
+	if err != nil {
+		return err
+	}
+	logf("👤 Matched -person %q to %s (%d issue(s))\n", onlyPerson, group.Person, group.TotalIssues)
+
+	thread := newReportThread(slackBotToken, slackChannel, stats)
+	blocks := []map[string]interface{}{
+		{"type": "header", "text": map[string]string{"type": "plain_text", "text": environmentPrefix() + reportTitle(mode) + " — " + date}},
+	}
+	blocks = append(blocks, buildPersonBlocks(thread, jiraURL, group, mode, 0, envRender)...)
+
+	logf("📤 Sending %s's section to Slack at %s...\n", group.Person, time.Now().Format("15:04:05"))
+	var posted map[string]manifestPerson
+	if err = thread.start(blocks); err == nil {
+		posted = map[string]manifestPerson{group.Person: {Channel: thread.channel, TS: thread.threadTS}}
+	}
+	activeRunResult.recordThread(thread.channel, thread.threadTS)
+	activeRunResult.recordDelivery([]PersonStatusGroup{group}, posted, err)
+	if err != nil {
+		return fmt.Errorf("failed to send %s's section: %w", group.Person, err)
+	}
+
+	if thread.degraded {
+		logf("\n⚠️  Sent %s's section to fallback channel %s\n", group.Person, thread.channel)
+		return errDegradedDelivery
+	}
+	logf("\n✅ Successfully sent %s's section with %d issue(s)\n", group.Person, group.TotalIssues)
+	return nil
+}
@@ -0,0 +1,121 @@
+// Emoji-free output
+//
+// Screen readers announce every emoji, which makes the report tedious to listen
+// to. With NO_EMOJI=true every message is passed through plainBlockText right
+// before it is sent (next to validateBlocks), which drops decorative emoji from
+// headers, status markers and issue lines and replaces the few that carry
+// meaning with words ("Warning:", "off hours"). Links and the layout are kept.
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// emojiWords replaces emoji that carry meaning with plain text equivalents.
+var emojiWords = strings.NewReplacer(
+	"⚠️", "Warning:",
+	"⚠", "Warning:",
+	"✅", "Done:",
+	"🌙", "off hours",
+)
+
+// noEmoji reports whether emoji should be left out of Slack output.
+func (r renderOptions) noEmoji() bool {
+	return r.bool("NO_EMOJI", false)
+}
+
+// isEmoji reports whether r is an emoji or a character only used inside emoji
+// sequences (variation selectors, joiners, keycaps). Box drawing, arrows and
+// bullets are plain text and are kept.
+func isEmoji(r rune) bool {
+	switch {
+	case r >= 0x1F000 && r <= 0x1FAFF: // Pictographs, emoticons, flags, ...
+		return true
+	case r >= 0x2600 && r <= 0x27BF: // Miscellaneous symbols and dingbats
+		return true
+	case r == 0x231A || r == 0x231B || (r >= 0x23E9 && r <= 0x23FA): // Watches, hourglasses, media controls
+		return true
+	case r == 0x2B50 || r == 0x2B55 || (r >= 0x2B05 && r <= 0x2B07) || r == 0x2B1B || r == 0x2B1C:
+		return true
+	case r == 0xFE0F || r == 0x200D || r == 0x20E3:
+		return true
+	}
+	return false
+}
+
+// stripEmoji replaces meaningful emoji with words and removes the rest, along
+// with the space that separated them from the text.
+func stripEmoji(text string) string {
+	text = emojiWords.Replace(text)
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if !isEmoji(r) {
+			b.WriteRune(r)
+			continue
+		}
+		// "📂 *ON_QA*" becomes "*ON_QA*" rather than " *ON_QA*"
+		if i < len(text) && text[i] == ' ' {
+			i++
+		}
+	}
+	return b.String()
+}
+
+// plainText returns text without emoji when NO_EMOJI is set.
+func plainText(text string) string {
+	if !envRender.noEmoji() {
+		return text
+	}
+	return stripEmoji(text)
+}
+
+// plainBlockText removes emoji from the text of section, header, context and
+// button elements in place when NO_EMOJI is set.
+func plainBlockText(blocks []map[string]interface{}, render renderOptions) {
+	if !render.noEmoji() {
+		return
+	}
+	for _, block := range blocks {
+		switch block["type"] {
+		case "section", "header":
+			plainTextObject(block["text"])
+		case "context", "actions":
+			switch elements := block["elements"].(type) {
+			case []map[string]string:
+				for _, element := range elements {
+					plainTextObject(element)
+				}
+			case []map[string]interface{}:
+				for _, element := range elements {
+					plainTextObject(element)
+					plainTextObject(element["text"])
+				}
+			case []interface{}:
+				for _, element := range elements {
+					plainTextObject(element)
+					if fields, ok := element.(map[string]interface{}); ok {
+						plainTextObject(fields["text"])
+					}
+				}
+			}
+		}
+	}
+}
+
+// plainTextObject strips emoji from the "text" of a Slack text object, which is a
+// map[string]string when built here or a map[string]interface{} when decoded.
+func plainTextObject(object interface{}) {
+	switch fields := object.(type) {
+	case map[string]string:
+		fields["text"] = stripEmoji(fields["text"])
+	case map[string]interface{}:
+		if value, ok := fields["text"].(string); ok {
+			fields["text"] = stripEmoji(value)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// PR link ordering
+//
+// Issues with several PRs list them as PR1, PR2, ... and PR1 is often an old,
+// abandoned attempt. PR_ORDER selects how the links are ordered before rendering:
+//
+//	reverse         - newest link first; JIRA appends new links at the end (default)
+//	as-is           - the order of the JIRA field
+//	github-activity - GitHub PRs by activity: open ones first (most recently
+//	                  updated first), then closed, then merged. Uses GITHUB_TOKEN if
+//	                  set. Falls back to reverse for links GitHub can't resolve.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PR ordering strategies accepted by PR_ORDER
+const (
+	prOrderAsIs           = "as-is"
+	prOrderReverse        = "reverse"
+	prOrderGitHubActivity = "github-activity"
+)
+
+// githubPRPattern extracts owner, repo and number from a GitHub pull request URL.
+var githubPRPattern = regexp.MustCompile(`^https?://github\.com/([^/]+)/([^/]+)/pull/(\d+)`)
+
+// githubPRActivity is what the ordering needs to know about a GitHub pull request.
+type githubPRActivity struct {
+	State     string    `json:"state"` // "open" or "closed"
+	Merged    bool      `json:"merged"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// githubPRCache keeps looked up PRs (nil for failed lookups) for the lifetime of the process.
+var (
+	githubPRCache   = make(map[string]*githubPRActivity)
+	githubPRCacheMu sync.Mutex
+)
+
+// prOrder returns the configured ordering strategy.
+func prOrder() string {
+	switch order := strings.ToLower(strings.TrimSpace(os.Getenv("PR_ORDER"))); order {
+	case prOrderAsIs, prOrderReverse, prOrderGitHubActivity:
+		return order
+	case "":
+		return prOrderReverse
+	default:
+		logf("⚠️  Unknown PR_ORDER %q, using %s\n", order, prOrderReverse)
+		return prOrderReverse
+	}
+}
+
+// orderPRLinks returns the PR links in the configured order.
+func orderPRLinks(prs []string) []string {
+	if len(prs) < 2 {
+		return prs
+	}
+
+	switch prOrder() {
+	case prOrderAsIs:
+		return prs
+	case prOrderGitHubActivity:
+		return orderPRsByActivity(prs, lookupGitHubPR)
+	default:
+		return reversePRs(prs)
+	}
+}
+
+// reversePRs returns the links newest first.
+func reversePRs(prs []string) []string {
+	reversed := make([]string, len(prs))
+	for i, pr := range prs {
+		reversed[len(prs)-1-i] = pr
+	}
+	return reversed
+}
+
+// orderPRsByActivity sorts links by their GitHub state and last update. Links that
+// can't be looked up keep the reverse order and follow the resolved ones.
+func orderPRsByActivity(prs []string, lookup func(string) *githubPRActivity) []string {
+	ordered := reversePRs(prs)
+	activity := make(map[string]*githubPRActivity, len(ordered))
+	for _, pr := range ordered {
+		activity[pr] = lookup(pr)
+	}
+
+	// open < closed < merged < unknown
+	rank := func(a *githubPRActivity) int {
+		switch {
+		case a == nil:
+			return 3
+		case a.Merged:
+			return 2
+		case a.State == "closed":
+			return 1
+		}
+		return 0
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := activity[ordered[i]], activity[ordered[j]]
+		if rank(a) != rank(b) {
+			return rank(a) < rank(b)
+		}
+		if a == nil {
+			return false
+		}
+		return a.UpdatedAt.After(b.UpdatedAt)
+	})
+	return ordered
+}
+
+// lookupGitHubPR returns the cached activity of a GitHub PR link, fetching it on
+// first use. Returns nil for non-GitHub links and failed lookups.
+func lookupGitHubPR(prURL string) *githubPRActivity {
+	match := githubPRPattern.FindStringSubmatch(prURL)
+	if match == nil {
+		return nil
+	}
+
+	githubPRCacheMu.Lock()
+	cached, ok := githubPRCache[prURL]
+	githubPRCacheMu.Unlock()
+	if ok {
+		return cached
+	}
+
+	activity, err := fetchGitHubPR(match[1], match[2], match[3])
+	if err != nil {
+		logf("      ⚠️  Failed to look up %s: %v\n", prURL, err)
+		activity = nil
+	}
+
+	githubPRCacheMu.Lock()
+	githubPRCache[prURL] = activity
+	githubPRCacheMu.Unlock()
+	return activity
+}
+
+// fetchGitHubPR fetches a pull request from the GitHub API.
+func fetchGitHubPR(owner, repo, number string) (*githubPRActivity, error) {
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/pulls/%s", owner, repo, number)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("GitHub API returned %d: %s", resp.StatusCode, redactSecrets(string(body)))
+	}
+
+	var activity githubPRActivity
+	if err := json.Unmarshal(body, &activity); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return &activity, nil
+}
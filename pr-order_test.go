@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestOrderPRLinks(t *testing.T) {
+	prs := []string{"https://example.com/pr/1", "https://example.com/pr/2", "https://example.com/pr/3"}
+
+	tests := []struct {
+		name  string
+		order string
+		prs   []string
+		want  string
+	}{
+		{name: "default is reverse", order: "", prs: prs, want: "[https://example.com/pr/3 https://example.com/pr/2 https://example.com/pr/1]"},
+		{name: "reverse", order: "reverse", prs: prs, want: "[https://example.com/pr/3 https://example.com/pr/2 https://example.com/pr/1]"},
+		{name: "as-is", order: "as-is", prs: prs, want: "[https://example.com/pr/1 https://example.com/pr/2 https://example.com/pr/3]"},
+		{name: "case and spaces are ignored", order: " AS-IS ", prs: prs, want: "[https://example.com/pr/1 https://example.com/pr/2 https://example.com/pr/3]"},
+		{name: "unknown order falls back to reverse", order: "newest", prs: prs, want: "[https://example.com/pr/3 https://example.com/pr/2 https://example.com/pr/1]"},
+		{name: "single link", order: "reverse", prs: prs[:1], want: "[https://example.com/pr/1]"},
+		{name: "no links", order: "reverse", prs: nil, want: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PR_ORDER", tt.order)
+			if got := fmt.Sprint(orderPRLinks(tt.prs)); got != tt.want {
+				t.Errorf("orderPRLinks = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderPRsByActivity(t *testing.T) {
+	now := time.Now()
+	activity := map[string]*githubPRActivity{
+		"open-old":    {State: "open", UpdatedAt: now.Add(-48 * time.Hour)},
+		"open-new":    {State: "open", UpdatedAt: now},
+		"closed":      {State: "closed", UpdatedAt: now},
+		"merged-old":  {State: "closed", Merged: true, UpdatedAt: now.Add(-48 * time.Hour)},
+		"merged-new":  {State: "closed", Merged: true, UpdatedAt: now},
+		"unknown-1st": nil,
+		"unknown-2nd": nil,
+	}
+	lookup := func(pr string) *githubPRActivity { return activity[pr] }
+
+	tests := []struct {
+		name string
+		prs  []string
+		want string
+	}{
+		{
+			name: "open, closed, merged",
+			prs:  []string{"merged-new", "closed", "open-old"},
+			want: "[open-old closed merged-new]",
+		},
+		{
+			name: "most recently updated first within a state",
+			prs:  []string{"open-new", "open-old", "merged-old", "merged-new"},
+			want: "[open-new open-old merged-new merged-old]",
+		},
+		{
+			name: "unresolved links follow in reverse order",
+			prs:  []string{"unknown-1st", "open-old", "unknown-2nd"},
+			want: "[open-old unknown-2nd unknown-1st]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fmt.Sprint(orderPRsByActivity(tt.prs, lookup)); got != tt.want {
+				t.Errorf("orderPRsByActivity = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrderPRLinksByGitHubActivity(t *testing.T) {
+	t.Setenv("PR_ORDER", "github-activity")
+	merged := "https://github.com/kubev2v/forklift/pull/1"
+	open := "https://github.com/kubev2v/forklift/pull/2"
+	other := "https://gitlab.com/kubev2v/forklift/-/merge_requests/3"
+
+	// Cached lookups keep the test off the network; other hosts aren't looked up
+	githubPRCacheMu.Lock()
+	githubPRCache[merged] = &githubPRActivity{State: "closed", Merged: true}
+	githubPRCache[open] = &githubPRActivity{State: "open"}
+	githubPRCacheMu.Unlock()
+	t.Cleanup(func() {
+		githubPRCacheMu.Lock()
+		delete(githubPRCache, merged)
+		delete(githubPRCache, open)
+		githubPRCacheMu.Unlock()
+	})
+
+	got := fmt.Sprint(orderPRLinks([]string{open, other, merged}))
+	if want := fmt.Sprint([]string{open, merged, other}); got != want {
+		t.Errorf("orderPRLinks = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,121 @@
+// Channel preflight
+//
+// The most common deployment failure is the bot not being a member of the report
+// channel, which used to surface only after the JIRA fetch, when the first post
+// failed. Before fetching, the daily report checks the channel with
+// conversations.info and stops with the fix ("/invite @bot in #channel").
+// This needs the channels:read (and groups:read for private channels) scope;
+// -skip-preflight bypasses the check for tokens without it. With
+// SLACK_FALLBACK_CHANNEL set, a failed check only warns, since the report can
+// still be delivered there.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// skipPreflight disables the channel check (set by -skip-preflight).
+var skipPreflight bool
+
+// slackChannelInfo is the part of conversations.info the preflight needs.
+type slackChannelInfo struct {
+	OK      bool   `json:"ok"`
+	Error   string `json:"error"`
+	Channel struct {
+		Name       string `json:"name"`
+		IsMember   bool   `json:"is_member"`
+		IsArchived bool   `json:"is_archived"`
+	} `json:"channel"`
+}
+
+// preflightChannel verifies that the bot can post to the report channel.
+func preflightChannel(botToken, channel string) error {
+	// DMs and user IDs are opened on demand and need no membership
+	if strings.HasPrefix(channel, "D") || strings.HasPrefix(channel, "U") {
+		return nil
+	}
+
+	var info slackChannelInfo
+	if err := getSlackAPI(botToken, "conversations.info", url.Values{"channel": {channel}}, &info); err != nil {
+		return fmt.Errorf("failed to check channel %s: %w", channel, err)
+	}
+	if !info.OK {
+		switch info.Error {
+		case "missing_scope":
+			return fmt.Errorf("can't check channel %s: the bot token lacks channels:read/groups:read (add the scope or run with -skip-preflight)", channel)
+		case "channel_not_found":
+			return fmt.Errorf("channel %s not found: check SLACK_CHANNEL, and for a private channel invite the bot first (/invite @%s)", channel, botDisplayName(botToken))
+		}
+		return fmt.Errorf("failed to check channel %s: Slack API error: %s", channel, info.Error)
+	}
+
+	name := "#" + info.Channel.Name
+	if info.Channel.IsArchived {
+		return fmt.Errorf("channel %s (%s) is archived: unarchive it or point SLACK_CHANNEL elsewhere", name, channel)
+	}
+	if !info.Channel.IsMember {
+		return fmt.Errorf("the bot isn't a member of %s (%s): run /invite @%s in %s", name, channel, botDisplayName(botToken), name)
+	}
+	return nil
+}
+
+// runPreflight checks the report channel before anything is fetched. A failure is
+// only returned if the report can't fall back to SLACK_FALLBACK_CHANNEL.
+func runPreflight(botToken, channel string) error {
+	if skipPreflight {
+		logln("⏭️  Skipping channel preflight")
+		return nil
+	}
+	err := preflightChannel(botToken, channel)
+	if err == nil {
+		logf("   ✓ Bot can post to %s\n", channel)
+		return nil
+	}
+	if os.Getenv("SLACK_FALLBACK_CHANNEL") != "" {
+		logf("⚠️  %v (continuing, SLACK_FALLBACK_CHANNEL is set)\n", err)
+		return nil
+	}
+	return err
+}
+
+// botDisplayName returns the bot's user name from auth.test, for /invite hints.
+func botDisplayName(botToken string) string {
+	var result struct {
+		OK   bool   `json:"ok"`
+		User string `json:"user"`
+	}
+	if err := getSlackAPI(botToken, "auth.test", nil, &result); err != nil || !result.OK || result.User == "" {
+		return "<bot name>"
+	}
+	return result.User
+}
+
+// getSlackAPI calls a read-only Slack Web API method with query parameters and
+// decodes the response into result.
+func getSlackAPI(botToken, method string, params url.Values, result interface{}) error {
+	endpoint := slackAPIBase + "/" + method
+	if len(params) > 0 {
+		endpoint += "?" + params.Encode()
+	}
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", botToken))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Slack API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
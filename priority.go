@@ -0,0 +1,80 @@
+// Priority filtering
+//
+// Teams can limit the report to important issues with MIN_PRIORITY (e.g. "Major").
+// Priorities are ranked by PRIORITY_ORDER, a comma-separated list from most to
+// least important, so instances with custom priority schemes can define their own
+// ranking. Issues without a priority (or with one missing from the ordering) are
+// kept unless KEEP_UNPRIORITIZED=false.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// defaultPriorityOrder is Red Hat JIRA's priority scheme, most important first.
+var defaultPriorityOrder = []string{"Blocker", "Critical", "Major", "Normal", "Minor", "Trivial"}
+
+// priorityFilter drops issues ranked below a minimum priority.
+type priorityFilter struct {
+	enabled           bool
+	minRank           int
+	ranks             map[string]int // lowercased priority name -> rank (0 is most important)
+	keepUnprioritized bool
+}
+
+// activePriorityFilter is loaded at startup by loadPriorityFilter and applied when grouping issues.
+var activePriorityFilter priorityFilter
+
+// loadPriorityFilter reads MIN_PRIORITY, PRIORITY_ORDER and KEEP_UNPRIORITIZED.
+// Returns an error if MIN_PRIORITY isn't part of the ordering.
+func loadPriorityFilter() (priorityFilter, error) {
+	order := envList("PRIORITY_ORDER")
+	if len(order) == 0 {
+		order = defaultPriorityOrder
+	}
+
+	filter := priorityFilter{
+		ranks:             make(map[string]int),
+		keepUnprioritized: envBool("KEEP_UNPRIORITIZED", true),
+	}
+	for i, name := range order {
+		filter.ranks[strings.ToLower(name)] = i
+	}
+
+	minPriority := strings.TrimSpace(os.Getenv("MIN_PRIORITY"))
+	if minPriority == "" {
+		return filter, nil
+	}
+
+	rank, ok := filter.ranks[strings.ToLower(minPriority)]
+	if !ok {
+		return filter, fmt.Errorf("MIN_PRIORITY %q is not in the priority order (%s)", minPriority, strings.Join(order, ", "))
+	}
+	filter.enabled = true
+	filter.minRank = rank
+
+	return filter, nil
+}
+
+// drops reports whether an issue with the given priority name falls below the threshold.
+func (f priorityFilter) drops(priority string) bool {
+	if !f.enabled {
+		return false
+	}
+
+	rank, ok := f.ranks[strings.ToLower(strings.TrimSpace(priority))]
+	if !ok {
+		return !f.keepUnprioritized
+	}
+	return rank > f.minRank
+}
+
+// issuePriority returns the priority name of an issue, or "" if it has none.
+func issuePriority(issue JiraIssue) string {
+	if issue.Fields.Priority == nil {
+		return ""
+	}
+	return issue.Fields.Priority.Name
+}
@@ -0,0 +1,52 @@
+// Per-project browse URLs
+//
+// Issue links normally point at JIRA_URL. When the report merges projects whose
+// issues live on other JIRA instances, PROJECT_URL_MAP sends each project's
+// /browse/ links to its own base URL, as a JSON object of project key to URL:
+//
+//	PROJECT_URL_MAP='{"MTV": "https://issues.redhat.com", "PARTNER": "https://partner.atlassian.net"}'
+//
+// Project keys are matched case-insensitively; unmapped projects use JIRA_URL.
+// Only issue links move: queries, report links and API calls stay on JIRA_URL.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// activeProjectURLMap holds PROJECT_URL_MAP keyed by upper-cased project key, loaded at startup.
+var activeProjectURLMap map[string]string
+
+// loadProjectURLMap parses PROJECT_URL_MAP. An unset PROJECT_URL_MAP yields an empty map.
+func loadProjectURLMap() (map[string]string, error) {
+	urls := make(map[string]string)
+	value := strings.TrimSpace(os.Getenv("PROJECT_URL_MAP"))
+	if value == "" {
+		return urls, nil
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return urls, fmt.Errorf("PROJECT_URL_MAP is not a JSON object of project key to URL: %w", err)
+	}
+	for project, base := range raw {
+		base = strings.TrimRight(strings.TrimSpace(base), "/")
+		if !strings.HasPrefix(base, "https://") && !strings.HasPrefix(base, "http://") {
+			return urls, fmt.Errorf("PROJECT_URL_MAP: %q for %s is not an http(s) URL", base, project)
+		}
+		urls[strings.ToUpper(strings.TrimSpace(project))] = base
+	}
+	return urls, nil
+}
+
+// issueBrowseURL returns the link to an issue, on its project's JIRA from
+// PROJECT_URL_MAP or else on jiraURL.
+func issueBrowseURL(jiraURL, key string) string {
+	if base, ok := activeProjectURLMap[issueProject(key)]; ok {
+		jiraURL = base
+	}
+	return fmt.Sprintf("%s/browse/%s", jiraURL, key)
+}
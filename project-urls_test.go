@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIssueBrowseURLMixedProjects(t *testing.T) {
+	t.Setenv("PROJECT_URL_MAP", `{"partner": "https://partner.atlassian.net/", " OCP ": "http://jira.internal"}`)
+	urls, err := loadProjectURLMap()
+	if err != nil {
+		t.Fatalf("loadProjectURLMap: %v", err)
+	}
+	saved := activeProjectURLMap
+	activeProjectURLMap = urls
+	defer func() { activeProjectURLMap = saved }()
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{key: "MTV-1", want: "https://issues.redhat.com/browse/MTV-1"},
+		{key: "PARTNER-22", want: "https://partner.atlassian.net/browse/PARTNER-22"},
+		{key: "OCP-333", want: "http://jira.internal/browse/OCP-333"},
+		{key: "PARTNERS-4", want: "https://issues.redhat.com/browse/PARTNERS-4"},
+	}
+	for _, tt := range tests {
+		if got := issueBrowseURL("https://issues.redhat.com", tt.key); got != tt.want {
+			t.Errorf("issueBrowseURL(%s) = %s, want %s", tt.key, got, tt.want)
+		}
+	}
+
+	// A report mixing projects links each issue line to its own instance
+	data := newIssueLineData("https://issues.redhat.com", IssueItem{Key: "PARTNER-22", Summary: "Partner bug"}, 100, envRender)
+	if line := renderIssueLine(data, envRender); !strings.Contains(line, "<https://partner.atlassian.net/browse/PARTNER-22|") {
+		t.Errorf("issue line doesn't link to the partner instance: %s", line)
+	}
+}
+
+func TestLoadProjectURLMapErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{name: "not an object", value: `["MTV"]`, want: "not a JSON object"},
+		{name: "not a URL", value: `{"MTV": "issues.redhat.com"}`, want: "not an http(s) URL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("PROJECT_URL_MAP", tt.value)
+			if _, err := loadProjectURLMap(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("loadProjectURLMap error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
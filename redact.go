@@ -0,0 +1,43 @@
+// Log redaction
+//
+// Everything the tool logs goes through logf/logln (see log.go), which mask the
+// configured credentials and common token shapes before output. JIRA and Slack error bodies
+// are redacted the same way before they end up in errors, since those are logged
+// and sometimes shown to Slack users.
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+)
+
+// redactedText replaces masked secrets.
+const redactedText = "[REDACTED]"
+
+// secretEnvVars are the environment variables whose values must never be logged.
+var secretEnvVars = []string{"JIRA_TOKEN", "SLACK_BOT_TOKEN", "SLACK_SIGNING_SECRET", "REPORT_TRIGGER_TOKEN", "REPORT_API_TOKEN", "GITHUB_TOKEN"}
+
+// secretPatterns match credentials that aren't configured here but still shouldn't be logged.
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=-]+`), "$1 " + redactedText},
+	{regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]+`), redactedText},
+	{regexp.MustCompile(`(?i)([?&](?:token|access_token|api_key|apikey|key|secret|password|signature)=)[^&\s"']+`), "${1}" + redactedText},
+}
+
+// redactSecrets masks configured secrets and token-like strings in s.
+func redactSecrets(s string) string {
+	for _, name := range secretEnvVars {
+		// Very short values would mask unrelated text and can't be real tokens anyway
+		if value := os.Getenv(name); len(value) >= 8 {
+			s = strings.ReplaceAll(s, value, redactedText)
+		}
+	}
+	for _, pattern := range secretPatterns {
+		s = pattern.re.ReplaceAllString(s, pattern.repl)
+	}
+	return s
+}
@@ -0,0 +1,257 @@
+// Reminder buttons
+//
+// Each person's section in the daily thread gets a "⏰ Remind me at 15:00" button
+// when the person is mapped to a Slack user in SLACK_USER_IDS
+// (comma-separated "JIRA Name=U0123ABC" pairs). Clicking it schedules a DM to that
+// user with the section's content at REMINDER_TIME (default 15:00, in the user's
+// Slack timezone), linking back to the report thread. Requires the interactions
+// endpoint and the im:write scope.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// remindActionID identifies the "Remind me" button on person sections
+const remindActionID = "remind_me"
+
+// defaultReminderTime is when reminders are delivered unless REMINDER_TIME is set.
+const defaultReminderTime = "15:00"
+
+// remindAction is the state encoded into the "Remind me" button
+type remindAction struct {
+	Person   string `json:"p"`
+	UserID   string `json:"u"`
+	Channel  string `json:"c"`
+	ThreadTS string `json:"t"`
+}
+
+// slackUserIDFor returns the Slack user ID mapped to a JIRA display name, or "".
+// People without a SLACK_USER_IDS entry are looked up by email when
+// SLACK_LOOKUP_BY_EMAIL is on (see slack-users.go).
+func slackUserIDFor(person string) string {
+	for _, entry := range envList("SLACK_USER_IDS") {
+		name, id, ok := strings.Cut(entry, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(name), person) {
+			return strings.TrimSpace(id)
+		}
+	}
+	return activeSlackUsers.idFor(person)
+}
+
+// reminderTime returns the configured reminder time of day as "HH:MM".
+func reminderTime() string {
+	value := strings.TrimSpace(os.Getenv("REMINDER_TIME"))
+	if _, err := time.Parse("15:04", value); err != nil {
+		return defaultReminderTime
+	}
+	return value
+}
+
+// buildRemindButtonBlock creates the "Remind me" actions block for a person's section.
+// Returns nil if the person isn't mapped to a Slack user or the state can't be encoded.
+func buildRemindButtonBlock(person, channel, threadTS string) map[string]interface{} {
+	userID := slackUserIDFor(person)
+	if userID == "" {
+		return nil
+	}
+
+	value, err := encodeActionValue(remindAction{
+		Person:   person,
+		UserID:   userID,
+		Channel:  channel,
+		ThreadTS: threadTS,
+	})
+	if err != nil {
+		logf("   ⚠️  Omitting remind button for %s: %v\n", person, err)
+		return nil
+	}
+
+	return map[string]interface{}{
+		"type": "actions",
+		"elements": []map[string]interface{}{
+			{
+				"type":      "button",
+				"action_id": remindActionID,
+				"text": map[string]string{
+					"type": "plain_text",
+					"text": "⏰ Remind me at " + reminderTime(),
+				},
+				"value": value,
+			},
+		},
+	}
+}
+
+// nextReminderAt returns the next occurrence of the "HH:MM" time of day in loc after now.
+func nextReminderAt(now time.Time, timeOfDay string, loc *time.Location) time.Time {
+	clock, _ := time.Parse("15:04", timeOfDay)
+	local := now.In(loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// processRemindAction schedules the reminder DM for a "Remind me" click and
+// confirms privately to the clicking user.
+func processRemindAction(payload SlackInteractionPayload, value string) {
+	var remind remindAction
+	if err := decodeActionValue(value, &remind); err != nil {
+		logf("   ❌ %v\n", err)
+		sendErrorResponse(payload.ResponseURL, "Couldn't read the reminder. Please try again.")
+		return
+	}
+
+	if payload.User.ID != remind.UserID {
+		sendErrorResponse(payload.ResponseURL, fmt.Sprintf("This reminder is for *%s*.", escapeSlackMrkdwn(remind.Person)))
+		return
+	}
+
+	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
+	if slackBotToken == "" {
+		sendErrorResponse(payload.ResponseURL, "Configuration error: SLACK_BOT_TOKEN not set")
+		return
+	}
+
+	loc := time.UTC
+	if info, err := fetchSlackUserInfo(slackBotToken, remind.UserID); err == nil {
+		loc = time.FixedZone(info.User.TZ, info.User.TZOffset)
+	} else {
+		logf("   ⚠️  Failed to get timezone of %s, using UTC: %v\n", remind.UserID, err)
+	}
+	postAt := nextReminderAt(time.Now(), reminderTime(), loc)
+
+	dmChannel, err := openDirectMessage(slackBotToken, remind.UserID)
+	if err != nil {
+		logf("   ❌ Failed to open DM: %v\n", err)
+		sendErrorResponse(payload.ResponseURL, fmt.Sprintf("Failed to schedule the reminder: %v", err))
+		return
+	}
+
+	// The reminder carries the person's section as it was posted, minus the button itself
+	header := "⏰ *Reminder:* your items from today's report"
+	if permalink, err := slackPermalink(slackBotToken, remind.Channel, remind.ThreadTS); err == nil {
+		header += fmt.Sprintf(" (<%s|open thread>)", permalink)
+	} else {
+		logf("   ⚠️  Failed to get thread permalink: %v\n", err)
+	}
+	blocks := []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": header},
+		},
+	}
+	// Replies can hold several people, so only this person's section is taken
+	blocks = append(blocks, personSectionBlocks(payload.Message.Blocks, value)...)
+
+	if err := scheduleSlackMessage(slackBotToken, dmChannel, postAt, "Reminder: your items from today's report", blocks); err != nil {
+		logf("   ❌ Failed to schedule reminder: %v\n", err)
+		sendErrorResponse(payload.ResponseURL, fmt.Sprintf("Failed to schedule the reminder: %v", err))
+		return
+	}
+
+	err = sendSlackResponse(payload.ResponseURL, SlackSlashResponse{
+		ResponseType: "ephemeral",
+		Text:         fmt.Sprintf("⏰ Got it, I'll DM you at %s.", postAt.Format("15:04 Mon")),
+	})
+	if err != nil {
+		logf("   ❌ ERROR confirming reminder: %v\n", err)
+	}
+
+	logf("✅ Scheduled reminder for %s at %s\n", remind.Person, postAt.Format(time.RFC3339))
+}
+
+// scheduleSlackMessage schedules a message with chat.scheduleMessage.
+func scheduleSlackMessage(botToken, channel string, postAt time.Time, text string, blocks []map[string]interface{}) error {
+	plainBlockText(blocks, envRender)
+	text = plainText(text)
+	if err := validateBlocks(blocks); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	payload := map[string]interface{}{
+		"channel": channel,
+		"post_at": postAt.Unix(),
+		"text":    text,
+		"blocks":  blocks,
+	}
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := callSlackAPI(botToken, "chat.scheduleMessage", payload, &result); err != nil {
+		return err
+	}
+	if !result.OK {
+		return fmt.Errorf("Slack API error: %s", result.Error)
+	}
+	return nil
+}
+
+// slackPermalink returns the permalink of a message.
+func slackPermalink(botToken, channel, messageTS string) (string, error) {
+	endpoint := fmt.Sprintf("%s/chat.getPermalink?channel=%s&message_ts=%s", slackAPIBase, url.QueryEscape(channel), url.QueryEscape(messageTS))
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", botToken))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call Slack API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK        bool   `json:"ok"`
+		Error     string `json:"error"`
+		Permalink string `json:"permalink"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("Slack API error: %s", result.Error)
+	}
+	return result.Permalink, nil
+}
+
+// callSlackAPI posts a JSON payload to a Slack Web API method and decodes the response into result.
+func callSlackAPI(botToken, method string, payload interface{}, result interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", slackAPIBase+"/"+method, bytes.NewBuffer(data))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", botToken))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Slack API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
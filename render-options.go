@@ -0,0 +1,53 @@
+// Render options
+//
+// The settings read while rendering the report thread (INDENT_STYLE, NO_EMOJI,
+// PERSON_LINK, ISSUE_TEMPLATE and COMPACT_CHANNEL_POST) come from a renderOptions
+// value rather than straight from the environment, so one process can render the
+// same report two ways (see shadow.go) without touching the environment or the
+// globals the -server handlers read concurrently. envRender renders with the
+// environment's settings, as every report except the shadow copy does.
+package main
+
+import (
+	"os"
+	"text/template"
+)
+
+// renderSettings are the settings a renderOptions can override.
+var renderSettings = map[string]bool{
+	"INDENT_STYLE":         true,
+	"NO_EMOJI":             true,
+	"PERSON_LINK":          true,
+	"ISSUE_TEMPLATE":       true,
+	"COMPACT_CHANNEL_POST": true,
+}
+
+// renderOptions overrides rendering settings. The zero value uses the environment.
+type renderOptions struct {
+	settings map[string]string  // Setting to value, taking precedence over the environment
+	template *template.Template // Issue line template; nil uses activeIssueTemplate
+}
+
+// envRender renders with the environment's settings.
+var envRender renderOptions
+
+// setting returns the overridden value of name, or else its environment variable.
+func (r renderOptions) setting(name string) string {
+	if value, ok := r.settings[name]; ok {
+		return value
+	}
+	return os.Getenv(name)
+}
+
+// bool reads a boolean setting like envBool, returning def when unset or invalid.
+func (r renderOptions) bool(name string, def bool) bool {
+	return parseBool(r.setting(name), def)
+}
+
+// issueTemplate returns the issue line template to render with.
+func (r renderOptions) issueTemplate() *template.Template {
+	if r.template != nil {
+		return r.template
+	}
+	return activeIssueTemplate
+}
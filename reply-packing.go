@@ -0,0 +1,100 @@
+// Reply packing
+//
+// Thirty people with one issue each used to mean thirty thread replies. Consecutive
+// person sections are now packed into one reply as long as the reply stays under
+// Slack's block limit and holds at most MAX_PEOPLE_PER_MESSAGE people (default 4,
+// 1 restores one reply per person). Large sections still get a reply of their own.
+// The separators between sections are unchanged, so only the message boundaries move.
+package main
+
+import "strings"
+
+// personBlockIDPrefix starts the block_id of each person header.
+const personBlockIDPrefix = "person-"
+
+// defaultPeoplePerMessage is how many people share a reply unless MAX_PEOPLE_PER_MESSAGE is set.
+const defaultPeoplePerMessage = 4
+
+// dailyReplyBlockLimit keeps packed replies below Slack's 50 blocks per message.
+const dailyReplyBlockLimit = 48
+
+// personSection is the rendered thread section of one person.
+type personSection struct {
+	person string
+	team   string // Set with GROUP_BY=team (or workstream); sections of different teams aren't packed together
+	blocks []map[string]interface{}
+}
+
+// packedReply is one thread reply holding one or more person sections.
+type packedReply struct {
+	people []string
+	team   string
+	blocks []map[string]interface{}
+}
+
+// packPersonSections combines consecutive sections of the same team into replies of
+// at most maxPeople people and blockLimit blocks, keeping the sections and their
+// blocks in order.
+// A section over the block limit on its own is sent alone, unchanged.
+func packPersonSections(sections []personSection, maxPeople, blockLimit int) []packedReply {
+	if maxPeople < 1 {
+		maxPeople = 1
+	}
+
+	var replies []packedReply
+	for _, section := range sections {
+		if n := len(replies); n > 0 {
+			last := &replies[n-1]
+			if last.team == section.team && len(last.people) < maxPeople && len(last.blocks)+len(section.blocks) <= blockLimit {
+				last.people = append(last.people, section.person)
+				last.blocks = append(last.blocks, section.blocks...)
+				continue
+			}
+		}
+		replies = append(replies, packedReply{
+			people: []string{section.person},
+			team:   section.team,
+			blocks: append([]map[string]interface{}{}, section.blocks...),
+		})
+	}
+	return replies
+}
+
+// personSectionBlocks returns the blocks of the person section whose actions block
+// holds the button with the given value, without action blocks. If the button
+// can't be found, all non-action blocks are returned.
+func personSectionBlocks(blocks []map[string]interface{}, buttonValue string) []map[string]interface{} {
+	start, end := 0, len(blocks)
+	for i, block := range blocks {
+		if blockID, _ := block["block_id"].(string); strings.HasPrefix(blockID, personBlockIDPrefix) {
+			start = i
+		}
+		if block["type"] == "actions" && hasButtonValue(block, buttonValue) {
+			end = i
+			break
+		}
+	}
+	if end == len(blocks) {
+		start = 0
+	}
+
+	var section []map[string]interface{}
+	for _, block := range blocks[start:end] {
+		if block["type"] != "actions" {
+			section = append(section, block)
+		}
+	}
+	return section
+}
+
+// hasButtonValue reports whether an actions block (as decoded from an interaction
+// payload) contains an element with the value.
+func hasButtonValue(block map[string]interface{}, value string) bool {
+	elements, _ := block["elements"].([]interface{})
+	for _, element := range elements {
+		if fields, ok := element.(map[string]interface{}); ok && fields["value"] == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testSection returns a section of n blocks whose text names the person and block.
+func testSection(person, team string, n int) personSection {
+	section := personSection{person: person, team: team}
+	for i := 0; i < n; i++ {
+		section.blocks = append(section.blocks, map[string]interface{}{"type": "section", "text": fmt.Sprintf("%s/%d", person, i)})
+	}
+	return section
+}
+
+// packedLayout describes replies as "people:blocks" entries, e.g. "a,b:4".
+func packedLayout(replies []packedReply) string {
+	var parts []string
+	for _, reply := range replies {
+		parts = append(parts, fmt.Sprintf("%s:%d", strings.Join(reply.people, ","), len(reply.blocks)))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestPackPersonSections(t *testing.T) {
+	tests := []struct {
+		name       string
+		sections   []personSection
+		maxPeople  int
+		blockLimit int
+		want       string
+	}{
+		{
+			name:       "maxPeople 0 behaves like 1",
+			sections:   []personSection{testSection("a", "", 2), testSection("b", "", 2)},
+			maxPeople:  0,
+			blockLimit: 48,
+			want:       "a:2 b:2",
+		},
+		{
+			name:       "maxPeople 1 gives each person a reply",
+			sections:   []personSection{testSection("a", "", 1), testSection("b", "", 1), testSection("c", "", 1)},
+			maxPeople:  1,
+			blockLimit: 48,
+			want:       "a:1 b:1 c:1",
+		},
+		{
+			name:       "packs up to maxPeople",
+			sections:   []personSection{testSection("a", "", 2), testSection("b", "", 2), testSection("c", "", 2)},
+			maxPeople:  2,
+			blockLimit: 48,
+			want:       "a,b:4 c:2",
+		},
+		{
+			name:       "team boundaries start a new reply",
+			sections:   []personSection{testSection("a", "x", 2), testSection("b", "y", 2), testSection("c", "y", 2)},
+			maxPeople:  4,
+			blockLimit: 48,
+			want:       "a:2 b,c:4",
+		},
+		{
+			name:       "block limit starts a new reply",
+			sections:   []personSection{testSection("a", "", 3), testSection("b", "", 3), testSection("c", "", 2)},
+			maxPeople:  4,
+			blockLimit: 6,
+			want:       "a,b:6 c:2",
+		},
+		{
+			name:       "section over the block limit is sent alone",
+			sections:   []personSection{testSection("a", "", 1), testSection("b", "", 10), testSection("c", "", 1)},
+			maxPeople:  4,
+			blockLimit: 6,
+			want:       "a:1 b:10 c:1",
+		},
+		{
+			name:       "no sections",
+			maxPeople:  4,
+			blockLimit: 48,
+			want:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			replies := packPersonSections(tt.sections, tt.maxPeople, tt.blockLimit)
+			if got := packedLayout(replies); got != tt.want {
+				t.Errorf("packed %q, want %q", got, tt.want)
+			}
+
+			// Every block is kept, in order
+			var want, got []interface{}
+			for _, section := range tt.sections {
+				for _, block := range section.blocks {
+					want = append(want, block["text"])
+				}
+			}
+			for _, reply := range replies {
+				for _, block := range reply.blocks {
+					got = append(got, block["text"])
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(want) {
+				t.Errorf("blocks = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestPackPersonSectionsLeavesSectionsUnchanged(t *testing.T) {
+	a, b := testSection("a", "", 2), testSection("b", "", 2)
+	packPersonSections([]personSection{a, b}, 4, 48)
+	if len(a.blocks) != 2 || a.blocks[1]["text"] != "a/1" {
+		t.Errorf("packing changed the first section: %v", a.blocks)
+	}
+}
+
+func TestPersonSectionBlocks(t *testing.T) {
+	// A packed reply as decoded from an interaction payload: two sections, each
+	// with a remind button
+	reply := []map[string]interface{}{
+		{"type": "section", "text": "separator"},
+		{"type": "section", "block_id": personBlockIDPrefix + "0", "text": "a header"},
+		{"type": "section", "text": "a issue"},
+		{"type": "actions", "elements": []interface{}{map[string]interface{}{"value": "remind:a"}}},
+		{"type": "section", "text": "a closing"},
+		{"type": "section", "block_id": personBlockIDPrefix + "1", "text": "b header"},
+		{"type": "section", "text": "b issue"},
+		{"type": "actions", "elements": []interface{}{map[string]interface{}{"value": "remind:b"}}},
+		{"type": "section", "text": "b closing"},
+	}
+
+	tests := []struct {
+		name   string
+		button string
+		want   []string
+	}{
+		{name: "first section", button: "remind:a", want: []string{"a header", "a issue"}},
+		{name: "second section", button: "remind:b", want: []string{"b header", "b issue"}},
+		{
+			name:   "button not found returns every non-action block",
+			button: "remind:c",
+			want:   []string{"separator", "a header", "a issue", "a closing", "b header", "b issue", "b closing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, block := range personSectionBlocks(reply, tt.button) {
+				got = append(got, block["text"].(string))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("personSectionBlocks = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
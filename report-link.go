@@ -0,0 +1,53 @@
+// Report query link
+//
+// The daily thread's footer links to the report's query in JIRA so readers can
+// open the live results. Queries that are just a saved filter ("filter = 12345")
+// link to the filter itself. The link text can be replaced with REPORT_LINK_TEXT,
+// and SHOW_REPORT_LINK=false leaves the link out.
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultReportLinkText is the text of the footer link.
+const defaultReportLinkText = "🔗 Open this report's query in JIRA"
+
+// savedFilterPattern matches a query that is only a saved filter reference, by ID.
+var savedFilterPattern = regexp.MustCompile(`(?i)^\s*filter\s*=\s*"?(\d+)"?\s*$`)
+
+// reportQueryURL returns the JIRA URL showing the results of jql.
+func reportQueryURL(jiraURL, jql string) string {
+	if match := savedFilterPattern.FindStringSubmatch(jql); match != nil {
+		return strings.TrimRight(jiraURL, "/") + "/issues/?filter=" + url.QueryEscape(match[1])
+	}
+	return jiraSearchURL(jiraURL, jql)
+}
+
+// buildReportLinkBlocks creates the footer context block linking to the report's
+// query, or nil if the link is disabled.
+func buildReportLinkBlocks(jiraURL, jql string) []map[string]interface{} {
+	if !envBool("SHOW_REPORT_LINK", true) || jql == "" {
+		return nil
+	}
+	text := os.Getenv("REPORT_LINK_TEXT")
+	if text == "" {
+		text = defaultReportLinkText
+	}
+
+	return []map[string]interface{}{
+		{
+			"type": "context",
+			"elements": []map[string]string{
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("<%s|%s>", reportQueryURL(jiraURL, jql), escapeSlackControl(text)),
+				},
+			},
+		},
+	}
+}
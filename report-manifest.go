@@ -0,0 +1,101 @@
+// Report manifest
+//
+// After posting, the report writes report-manifest.json to STATE_DIR mapping each
+// person to the message holding their section, so other automation (e.g. a bot
+// nagging about stale ON_QA items) can reply in context. The latest manifest of
+// each mode is kept, and it is served at GET /api/report/manifest?mode=qa,
+// authenticated with REPORT_API_TOKEN like /api/report.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// reportManifestFile is the name of the manifest file inside STATE_DIR
+const reportManifestFile = "report-manifest.json"
+
+// reportManifest locates the messages of one posted report.
+type reportManifest struct {
+	Mode     reportMode                `json:"mode"`
+	PostedAt time.Time                 `json:"posted_at"`
+	Channel  string                    `json:"channel"`   // Channel of the thread (the fallback channel after a fallback)
+	ThreadTS string                    `json:"thread_ts"` // ts of the message the report is threaded under
+	People   map[string]manifestPerson `json:"people"`
+}
+
+// manifestPerson is where one person's section was posted. People packed into
+// the same reply share its ts.
+type manifestPerson struct {
+	Channel  string            `json:"channel"`
+	ThreadTS string            `json:"thread_ts"`
+	TS       string            `json:"ts"`
+	Parts    []string          `json:"parts,omitempty"`   // ts of the later parts of a split reply
+	Hash     string            `json:"hash,omitempty"`    // Hash of the rendered section, compared by -update
+	Issues   map[string]string `json:"issues,omitempty"`  // Key to status of the section's issues
+	Removed  bool              `json:"removed,omitempty"` // Struck through by -update after leaving the report
+}
+
+// loadReportManifests reads the manifests by mode. A missing file yields none.
+func loadReportManifests() (map[string]reportManifest, error) {
+	manifests := make(map[string]reportManifest)
+
+	data, err := os.ReadFile(filepath.Join(stateDir(), reportManifestFile))
+	if os.IsNotExist(err) {
+		return manifests, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &manifests); err != nil {
+		return nil, fmt.Errorf("failed to parse report manifest: %w", err)
+	}
+	return manifests, nil
+}
+
+// saveReportManifest replaces the stored manifest of the manifest's mode.
+func saveReportManifest(manifest reportManifest) error {
+	manifests, err := loadReportManifests()
+	if err != nil {
+		return err
+	}
+	manifests[string(manifest.Mode)] = manifest
+	return writeStateFile(reportManifestFile, manifests)
+}
+
+// handleReportManifest serves the latest manifest of a report mode
+func handleReportManifest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	modeValue := r.URL.Query().Get("mode")
+	if modeValue == "" {
+		modeValue = string(modeQA)
+	}
+	mode, err := parseReportMode(modeValue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	manifests, err := loadReportManifests()
+	if err != nil {
+		logf("❌ %v\n", err)
+		http.Error(w, "Failed to read report manifest", http.StatusInternalServerError)
+		return
+	}
+	manifest, ok := manifests[string(mode)]
+	if !ok {
+		http.Error(w, fmt.Sprintf("No %s report has been posted yet", mode), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(manifest)
+}
@@ -0,0 +1,164 @@
+// Report Modes
+//
+// The daily report can run in different modes, each covering a different slice
+// of the project:
+//
+//	qa         - POST/ON_QA/MODIFIED issues grouped by QA Contact or Assignee (default)
+//	inprogress - In Progress issues grouped by Assignee, with age-in-status and PR presence
+//
+// Each mode can post to its own channel via SLACK_CHANNEL_<MODE> (e.g.
+// SLACK_CHANNEL_INPROGRESS), falling back to SLACK_CHANNEL.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// reportMode selects which issues the daily report covers and how they are grouped.
+type reportMode string
+
+const (
+	modeQA         reportMode = "qa"
+	modeInProgress reportMode = "inprogress"
+)
+
+// jiraTimeLayout is the timestamp format used by JIRA for created dates and changelog entries.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// parseReportMode validates the -mode flag value.
+func parseReportMode(value string) (reportMode, error) {
+	switch mode := reportMode(strings.ToLower(strings.TrimSpace(value))); mode {
+	case modeQA, modeInProgress:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown report mode %q (valid modes: %s, %s)", value, modeQA, modeInProgress)
+}
+
+// reportChannel returns the Slack channel for the mode.
+// A per-mode SLACK_CHANNEL_<MODE> variable takes precedence over SLACK_CHANNEL,
+// and PARENT_CHANNEL over both when posting under an existing message.
+func reportChannel(mode reportMode) string {
+	if channel, _ := parentThread(); channel != "" {
+		return channel
+	}
+	if channel := os.Getenv("SLACK_CHANNEL_" + strings.ToUpper(string(mode))); channel != "" {
+		return channel
+	}
+	return os.Getenv("SLACK_CHANNEL")
+}
+
+// reportJQL returns the JQL query for the mode.
+// JIRA_JQL overrides the default (qa) report query.
+func reportJQL(mode reportMode) string {
+	if mode == modeInProgress {
+		return `project = MTV AND status = "In Progress" ORDER BY assignee`
+	}
+
+	if jql := strings.TrimSpace(os.Getenv("JIRA_JQL")); jql != "" {
+		return jql
+	}
+
+	// JQL Query fetches:
+	// 1. Issues with status: POST, ON_QA, or MODIFIED
+	// 2. Epics that are not Closed (will be filtered for PRs later)
+	// Excludes UI-related issues (filtered in code)
+	return `project = MTV AND updated >= -365d AND (status IN (POST, ON_QA, MODIFIED) OR ` + reportEpicClause + `) ORDER BY assignee`
+}
+
+// reportQuery returns the JQL a report run for the mode fetches: reportJQL with the
+// ${LAST_RUN} placeholder expanded and the epic clause narrowed to epics with PRs.
+func reportQuery(mode reportMode, jiraURL, jiraToken string) string {
+	return narrowEpicClause(expandLastRunPlaceholder(reportJQL(mode), mode, jiraURL, jiraToken), jiraURL, jiraToken)
+}
+
+// reportFetchOptions returns the extra fetch options the mode and enabled features need.
+// The in-progress mode needs the changelog to work out how long each issue has been in progress.
+func reportFetchOptions(mode reportMode) fetchOptions {
+	var opts fetchOptions
+	if mode == modeInProgress || envBool("SHOW_OWNERSHIP_AGE", false) {
+		opts = fetchOptions{Expand: "changelog", ExtraFields: []string{"created"}}
+	}
+	if envBool("SHOW_EPIC", false) {
+		opts.ExtraFields = append(opts.ExtraFields, epicLinkField())
+	}
+	if watchersEnabled() {
+		opts.ExtraFields = append(opts.ExtraFields, "watches")
+	}
+	if envBool("SHOW_DESCRIPTION", false) {
+		opts.ExtraFields = append(opts.ExtraFields, "description")
+	}
+	if issueLinksNeeded() {
+		opts.ExtraFields = append(opts.ExtraFields, "issuelinks")
+	}
+	return opts
+}
+
+// reportTitle returns the header text of the report thread for the mode.
+func reportTitle(mode reportMode) string {
+	if mode == modeInProgress {
+		return "🛠️ In Progress Summary"
+	}
+	return "🧾 Daily JIRA Summary"
+}
+
+// reportName returns a short plain name of the report for one-line messages.
+func reportName(mode reportMode) string {
+	if mode == modeInProgress {
+		return "In Progress report"
+	}
+	return "Daily report"
+}
+
+// sortByStatusAge orders issues by how long they have been in their status, oldest first.
+// Issues with an unknown age sort last.
+func sortByStatusAge(issues []IssueItem) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].StatusSince.IsZero() != issues[j].StatusSince.IsZero() {
+			return !issues[i].StatusSince.IsZero()
+		}
+		return issues[i].StatusSince.Before(issues[j].StatusSince)
+	})
+}
+
+// statusSince returns when the issue last transitioned into its current status.
+// Falls back to the creation date when the changelog has no such transition
+// (e.g. issues created directly in the status), and zero if neither is known.
+func statusSince(issue JiraIssue) time.Time {
+	var since time.Time
+	if issue.Changelog != nil {
+		for _, history := range issue.Changelog.Histories {
+			for _, item := range history.Items {
+				if item.Field != "status" || canonicalStatus(item.ToString) != issue.Fields.Status.Name {
+					continue
+				}
+				created, err := time.Parse(jiraTimeLayout, history.Created)
+				if err == nil && created.After(since) {
+					since = created
+				}
+			}
+		}
+	}
+
+	if since.IsZero() && issue.Fields.Created != "" {
+		if created, err := time.Parse(jiraTimeLayout, issue.Fields.Created); err == nil {
+			since = created
+		}
+	}
+
+	return since
+}
+
+// formatAge renders a duration as a compact age like "5d" or "3h".
+func formatAge(d time.Duration) string {
+	if d >= 24*time.Hour {
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+	if d >= time.Hour {
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return "<1h"
+}
@@ -0,0 +1,293 @@
+// Update runs
+//
+// -update re-runs the report into the thread of the mode's last posted report
+// (see report-manifest.go) instead of starting a new one. Each person's section
+// is hashed when it is posted, so an update only rewrites the replies holding a
+// changed section, with chat.update. Replies are keyed by person, so people moving
+// up or down leave them alone. People who dropped out of the report are struck
+// through as no longer having tracked issues and stay in the manifest as removed
+// (coming back in their old reply if they return), and people new to it are
+// posted in a "changes" reply at the end of the thread. The header message is
+// left as it is. Without a manifest for the mode, a new report is posted.
+//
+// UPDATE_IN_PLACE=true does the same for runs without -update, as long as the
+// mode's last report was posted the same day (in REPORT_TIMEZONE): a frequent cron
+// or trigger then keeps one evolving thread per day instead of re-posting, and the
+// first run of a day starts a new thread. The message ts values come from the
+// manifest, so this works across processes.
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// updateRun is set by -update.
+var updateRun bool
+
+// updateInPlace reports whether UPDATE_IN_PLACE turns runs into same-day updates.
+// Dry runs always post (print) a full report.
+func updateInPlace() bool {
+	return envBool("UPDATE_IN_PLACE", false) && !dryRun
+}
+
+// postedToday reports whether the report of the manifest was posted on now's day
+// in the report's timezone.
+func postedToday(manifest reportManifest, now time.Time) bool {
+	loc := reportLocation()
+	return manifest.PostedAt.In(loc).Format("2006-01-02") == now.In(loc).Format("2006-01-02")
+}
+
+// sectionHash returns a stable hash of a person's rendered section. The section is
+// rendered at a fixed position, so people moving up or down don't count as changed.
+func sectionHash(thread *reportThread, jiraURL string, group PersonStatusGroup, mode reportMode, render renderOptions) string {
+	// The local time annotation changes on every run
+	group.TimeZone = ""
+	data, err := json.Marshal(buildPersonBlocks(thread, jiraURL, group, mode, 1, render))
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// removedPersonBlocks replaces the section of a person who left the report.
+func removedPersonBlocks(person string) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("~👤 %s~ — no longer has tracked issues", escapeSlackMrkdwn(shortDisplayName(person))),
+			},
+		},
+	}
+}
+
+// movedPersonBlocks stands in for a changed section that no longer fits its reply.
+func movedPersonBlocks(person string) []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("_👤 %s — updated further down the thread_", escapeSlackMrkdwn(shortDisplayName(person))),
+			},
+		},
+	}
+}
+
+// update replaces the blocks of a message in the thread.
+func (t *reportThread) update(ts string, blocks []map[string]interface{}) error {
+	plainBlockText(blocks, t.render)
+	if err := validateBlocks(blocks); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	payload := map[string]interface{}{
+		"channel": t.channel,
+		"ts":      ts,
+		"blocks":  blocks,
+	}
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	start := time.Now()
+	err := callSlackAPI(t.botToken, "chat.update", payload, &result)
+	t.stats.recordSlack(time.Since(start))
+	if err != nil {
+		return err
+	}
+	if !result.OK {
+		return &SlackAPIError{Code: result.Error}
+	}
+	return nil
+}
+
+// personChanged reports whether the person's reply needs an update: their section
+// changed, they left the report, or they came back after being struck through.
+func personChanged(entry manifestPerson, current map[string]PersonStatusGroup, hashes map[string]string, person string) bool {
+	if _, ok := current[person]; !ok {
+		return !entry.Removed
+	}
+	return entry.Removed || hashes[person] != entry.Hash
+}
+
+// updateDailyReportThread brings the thread of a previous report up to date with
+// personGroups and returns the manifest entries of the updated thread.
+func updateDailyReportThread(thread *reportThread, jiraURL string, personGroups []PersonStatusGroup, mode reportMode, previous map[string]manifestPerson, stats *runStats) (map[string]manifestPerson, error) {
+	// People beyond MAX_PEOPLE keep whatever they had, they are neither new nor gone
+	personGroups, overflow := splitPersonOverflow(personGroups, maxPeople(), overflowByName())
+	updated := make(map[string]manifestPerson, len(previous))
+	for _, group := range overflow {
+		if entry, ok := previous[group.Person]; ok {
+			updated[group.Person] = entry
+		}
+	}
+
+	current := make(map[string]PersonStatusGroup, len(personGroups))
+	position := make(map[string]int, len(personGroups))
+	hashes := make(map[string]string, len(personGroups))
+	for i, group := range personGroups {
+		current[group.Person] = group
+		position[group.Person] = i
+		hashes[group.Person] = sectionHash(thread, jiraURL, group, mode, envRender)
+	}
+
+	// The previous replies, each with the people it holds in report order. Replies
+	// are keyed by person, so people moving up or down stay in their reply.
+	byTS := make(map[string][]string)
+	for person, entry := range previous {
+		if _, kept := updated[person]; !kept {
+			byTS[entry.TS] = append(byTS[entry.TS], person)
+		}
+	}
+	var replyTSs []string
+	for ts, people := range byTS {
+		replyTSs = append(replyTSs, ts)
+		sort.Slice(people, func(i, j int) bool {
+			pi, iok := position[people[i]]
+			pj, jok := position[people[j]]
+			if iok != jok {
+				return iok // People still in the report first
+			}
+			if iok {
+				return pi < pj
+			}
+			return people[i] < people[j]
+		})
+	}
+	sort.Strings(replyTSs)
+
+	var pending []PersonStatusGroup // Sections posted in the "changes" reply
+	for _, ts := range replyTSs {
+		people := byTS[ts]
+		var changed []string
+		for _, person := range people {
+			if personChanged(previous[person], current, hashes, person) {
+				changed = append(changed, person)
+			}
+		}
+		if len(changed) == 0 {
+			for _, person := range people {
+				updated[person] = previous[person]
+			}
+			continue
+		}
+
+		// Sections are numbered within the reply, so a person added or removed
+		// elsewhere in the report doesn't change how this reply renders
+		var blocks []map[string]interface{}
+		for i, person := range people {
+			if group, ok := current[person]; ok {
+				blocks = append(blocks, buildPersonBlocks(thread, jiraURL, group, mode, i+1, envRender)...)
+			} else {
+				blocks = append(blocks, removedPersonBlocks(person)...)
+			}
+		}
+
+		// A section that grew past the reply's block limit moves to the "changes" reply
+		if len(blocks) > dailyReplyBlockLimit {
+			blocks = nil
+			for i, person := range people {
+				group, ok := current[person]
+				switch {
+				case !ok:
+					blocks = append(blocks, removedPersonBlocks(person)...)
+				case personChanged(previous[person], current, hashes, person):
+					blocks = append(blocks, movedPersonBlocks(person)...)
+					pending = append(pending, group)
+				default:
+					blocks = append(blocks, buildPersonBlocks(thread, jiraURL, group, mode, i+1, envRender)...)
+				}
+			}
+		}
+
+		// A reply that was split when posted is updated across its parts
+		parts := previous[people[0]].Parts
+		logf("   Updating reply %s for changes to %v...\n", ts, changed)
+		if err := thread.updateSplitMessage(append([]string{ts}, parts...), blocks); err != nil {
+			return updated, fmt.Errorf("failed to update reply %s: %w", ts, err)
+		}
+		for _, person := range people {
+			entry := manifestPerson{Channel: thread.channel, ThreadTS: thread.threadTS, TS: ts, Parts: parts}
+			if group, ok := current[person]; ok {
+				entry.Hash, entry.Issues = hashes[person], groupIssueStatuses(group)
+			} else {
+				// Kept so the struck section isn't edited again and comes back in place
+				entry.Removed = true
+			}
+			updated[person] = entry
+		}
+		stats.sleep(500 * time.Millisecond)
+	}
+
+	for _, group := range personGroups {
+		if _, ok := previous[group.Person]; !ok {
+			pending = append(pending, group)
+		}
+	}
+	if len(pending) == 0 {
+		return updated, nil
+	}
+
+	var sections []personSection
+	for i, group := range pending {
+		sections = append(sections, personSection{
+			person: group.Person,
+			blocks: buildPersonBlocks(thread, jiraURL, group, mode, i+1, envRender),
+		})
+	}
+	replies := packPersonSections(sections, envInt("MAX_PEOPLE_PER_MESSAGE", defaultPeoplePerMessage), dailyReplyBlockLimit-1)
+	replies[0].blocks = append([]map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("🔄 *Changes since the report was posted* (%s)", time.Now().Format("15:04")),
+			},
+		},
+	}, replies[0].blocks...)
+
+	for i, reply := range replies {
+		logf("   Sending changes reply %d/%d: %v...\n", i+1, len(replies), reply.people)
+		parts, err := thread.reply(reply.blocks)
+		if err != nil {
+			return updated, fmt.Errorf("failed to send changes for %v: %w", reply.people, err)
+		}
+		for _, person := range reply.people {
+			updated[person] = manifestPerson{Channel: thread.channel, ThreadTS: thread.threadTS, TS: parts[0], Parts: parts[1:], Hash: hashes[person], Issues: groupIssueStatuses(current[person])}
+		}
+		if i+1 < len(replies) {
+			stats.sleep(500 * time.Millisecond)
+		}
+	}
+	return updated, nil
+}
+
+// updateReport updates the thread of the previous report of the mode. Update runs
+// don't move the "since last report" window, which belongs to the posted report.
+func updateReport(previous reportManifest, jiraURL, slackBotToken string, personGroups []PersonStatusGroup, mode reportMode, stats *runStats) error {
+	logf("🔄 Updating the %s report posted at %s (thread %s)...\n", mode, previous.PostedAt.Local().Format("15:04"), previous.ThreadTS)
+
+	thread := newReportThread(slackBotToken, previous.Channel, stats)
+	thread.threadTS = previous.ThreadTS
+	people, err := updateDailyReportThread(thread, jiraURL, personGroups, mode, previous.People, stats)
+	activeRunResult.recordThread(thread.channel, thread.threadTS)
+	activeRunResult.recordDelivery(personGroups, people, err)
+	if err != nil {
+		return fmt.Errorf("failed to update report thread: %w", err)
+	}
+
+	previous.People = people
+	if err := saveReportManifest(previous); err != nil {
+		logf("⚠️  Failed to write report manifest: %v\n", err)
+	}
+
+	logf("\n✅ Updated the report with %d issues\n", countGroupIssues(personGroups))
+	return nil
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// hashTestGroup returns a person group with one POST issue.
+func hashTestGroup() PersonStatusGroup {
+	return PersonStatusGroup{
+		Person:       "Jane Doe",
+		StatusGroups: map[string][]IssueItem{"POST": {{Key: "MTV-1", Summary: "Fix it", Status: "POST"}}},
+		TotalIssues:  1,
+		TimeZone:     "Europe/Prague",
+	}
+}
+
+func TestSectionHash(t *testing.T) {
+	t.Setenv("SHOW_LOCAL_TIME", "true")
+	thread := &reportThread{channel: "C1", threadTS: "1.0"}
+	base := sectionHash(thread, "https://jira.example.com", hashTestGroup(), modeQA, envRender)
+	if base == "" {
+		t.Fatal("sectionHash returned no hash")
+	}
+
+	tests := []struct {
+		name     string
+		change   func(*PersonStatusGroup)
+		wantSame bool
+	}{
+		{name: "unchanged section", change: func(*PersonStatusGroup) {}, wantSame: true},
+		{name: "local time is ignored", change: func(g *PersonStatusGroup) { g.TimeZone = "America/New_York" }, wantSame: true},
+		{name: "summary change", change: func(g *PersonStatusGroup) { g.StatusGroups["POST"][0].Summary = "Fix it properly" }},
+		{name: "new PR", change: func(g *PersonStatusGroup) {
+			g.StatusGroups["POST"][0].GitPullRequest = []string{"https://github.com/x/y/pull/1"}
+		}},
+		{
+			name: "status change",
+			change: func(g *PersonStatusGroup) {
+				g.StatusGroups = map[string][]IssueItem{"ON_QA": {{Key: "MTV-1", Summary: "Fix it", Status: "ON_QA"}}}
+			},
+		},
+		{
+			name: "added issue",
+			change: func(g *PersonStatusGroup) {
+				g.StatusGroups["POST"] = append(g.StatusGroups["POST"], IssueItem{Key: "MTV-2", Summary: "Another", Status: "POST"})
+				g.TotalIssues++
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			group := hashTestGroup()
+			tt.change(&group)
+			got := sectionHash(thread, "https://jira.example.com", group, modeQA, envRender)
+			if (got == base) != tt.wantSame {
+				t.Errorf("hash %s vs base %s, want same = %v", got, base, tt.wantSame)
+			}
+		})
+	}
+}
+
+// slackCall is one chat.postMessage or chat.update seen by updateSlackStub.
+type slackCall struct {
+	Method string
+	TS     string // Updated message, empty for posts
+	Text   string // Text of the section blocks
+}
+
+// updateSlackStub points the Slack API at a server that accepts posts and updates
+// and records them. Posted messages get ts values from "100" on. Posts of more
+// than maxBlocks blocks are rejected as msg_too_long, unless maxBlocks is 0.
+func updateSlackStub(t *testing.T, maxBlocks int) *[]slackCall {
+	t.Helper()
+	var calls []slackCall
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			TS     string `json:"ts"`
+			Blocks []struct {
+				Text struct {
+					Text string `json:"text"`
+				} `json:"text"`
+			} `json:"blocks"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		var texts []string
+		for _, block := range payload.Blocks {
+			texts = append(texts, block.Text.Text)
+		}
+		calls = append(calls, slackCall{Method: strings.TrimPrefix(r.URL.Path, "/"), TS: payload.TS, Text: strings.Join(texts, "\n")})
+		if maxBlocks > 0 && len(payload.Blocks) > maxBlocks {
+			json.NewEncoder(w).Encode(SlackMessageResponse{Error: "msg_too_long"})
+			return
+		}
+		json.NewEncoder(w).Encode(SlackMessageResponse{OK: true, TS: fmt.Sprint(99 + len(calls))})
+	}))
+	t.Cleanup(server.Close)
+
+	previous := slackAPIBase
+	slackAPIBase = server.URL
+	t.Cleanup(func() { slackAPIBase = previous })
+	return &calls
+}
+
+// updateTestGroup returns a person group with one POST issue per key.
+func updateTestGroup(person string, keys ...string) PersonStatusGroup {
+	group := PersonStatusGroup{Person: person, StatusGroups: map[string][]IssueItem{}}
+	for _, key := range keys {
+		group.StatusGroups["POST"] = append(group.StatusGroups["POST"], IssueItem{Key: key, Summary: "Work on " + key, Status: "POST"})
+		group.TotalIssues++
+	}
+	return group
+}
+
+// postedManifest returns the manifest entries of groups as if they had been
+// posted in the replies given by person.
+func postedManifest(thread *reportThread, groups []PersonStatusGroup, replies map[string]string) map[string]manifestPerson {
+	people := make(map[string]manifestPerson, len(groups))
+	for _, group := range groups {
+		people[group.Person] = manifestPerson{
+			Channel:  thread.channel,
+			ThreadTS: thread.threadTS,
+			TS:       replies[group.Person],
+			Hash:     sectionHash(thread, "https://jira.example.com", group, modeQA, envRender),
+			Issues:   groupIssueStatuses(group),
+		}
+	}
+	return people
+}
+
+func TestUpdateDailyReportThread(t *testing.T) {
+	alice := updateTestGroup("Alice Smith", "MTV-1")
+	bob := updateTestGroup("Bob Jones", "MTV-2")
+	carol := updateTestGroup("Carol White", "MTV-3")
+	replies := map[string]string{"Alice Smith": "10", "Bob Jones": "10", "Carol White": "11"}
+
+	tests := []struct {
+		name        string
+		groups      []PersonStatusGroup
+		wantCalls   []string // Method and ts (or posted text) of each call
+		wantText    []string // Text expected in the calls, in order
+		wantRemoved []string
+	}{
+		{
+			name:      "nothing changed",
+			groups:    []PersonStatusGroup{alice, bob, carol},
+			wantCalls: nil,
+		},
+		{
+			name:      "changed person updates only their reply",
+			groups:    []PersonStatusGroup{alice, bob, updateTestGroup("Carol White", "MTV-3", "MTV-4")},
+			wantCalls: []string{"chat.update 11"},
+			wantText:  []string{"MTV-4"},
+		},
+		{
+			name:      "person added at the top doesn't re-render the others",
+			groups:    []PersonStatusGroup{updateTestGroup("Aaron Adams", "MTV-9"), alice, bob, carol},
+			wantCalls: []string{"chat.postMessage"},
+			wantText:  []string{"Changes since the report was posted"},
+		},
+		{
+			name:        "removed person is struck through in their reply",
+			groups:      []PersonStatusGroup{alice, carol},
+			wantCalls:   []string{"chat.update 10"},
+			wantText:    []string{"~👤 Bob"},
+			wantRemoved: []string{"Bob Jones"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := updateSlackStub(t, 0)
+			thread := &reportThread{botToken: "xoxb-test", channel: "C1", threadTS: "1.0"}
+			previous := postedManifest(thread, []PersonStatusGroup{alice, bob, carol}, replies)
+
+			updated, err := updateDailyReportThread(thread, "https://jira.example.com", tt.groups, modeQA, previous, nil)
+			if err != nil {
+				t.Fatalf("updateDailyReportThread: %v", err)
+			}
+
+			var got []string
+			for _, call := range *calls {
+				got = append(got, strings.TrimSpace(call.Method+" "+call.TS))
+			}
+			if strings.Join(got, ", ") != strings.Join(tt.wantCalls, ", ") {
+				t.Fatalf("calls = %v, want %v", got, tt.wantCalls)
+			}
+			for i, text := range tt.wantText {
+				if !strings.Contains((*calls)[i].Text, text) {
+					t.Errorf("call %d text %q doesn't contain %q", i, (*calls)[i].Text, text)
+				}
+			}
+
+			for _, group := range tt.groups {
+				if entry, ok := updated[group.Person]; !ok || entry.Removed {
+					t.Errorf("manifest entry of %s = %+v, want a current section", group.Person, entry)
+				}
+			}
+			for _, person := range tt.wantRemoved {
+				if entry := updated[person]; !entry.Removed || entry.TS != replies[person] {
+					t.Errorf("manifest entry of %s = %+v, want removed in reply %s", person, entry, replies[person])
+				}
+			}
+			// Alice's section never changes, whoever moves around her
+			if entry := updated["Alice Smith"]; entry.TS != "10" || entry.Hash != previous["Alice Smith"].Hash {
+				t.Errorf("Alice's manifest entry = %+v, want her unchanged section in reply 10", entry)
+			}
+		})
+	}
+}
+
+func TestUpdateDailyReportThreadRemovedPersonReturns(t *testing.T) {
+	calls := updateSlackStub(t, 0)
+	thread := &reportThread{botToken: "xoxb-test", channel: "C1", threadTS: "1.0"}
+	alice := updateTestGroup("Alice Smith", "MTV-1")
+	bob := updateTestGroup("Bob Jones", "MTV-2")
+	people := postedManifest(thread, []PersonStatusGroup{alice, bob}, map[string]string{"Alice Smith": "10", "Bob Jones": "10"})
+
+	runs := []struct {
+		groups    []PersonStatusGroup
+		wantCalls int
+	}{
+		{groups: []PersonStatusGroup{alice}, wantCalls: 1},      // Bob is struck through
+		{groups: []PersonStatusGroup{alice}, wantCalls: 1},      // and not struck again
+		{groups: []PersonStatusGroup{alice, bob}, wantCalls: 2}, // until he comes back in place
+	}
+	for i, run := range runs {
+		var err error
+		people, err = updateDailyReportThread(thread, "https://jira.example.com", run.groups, modeQA, people, nil)
+		if err != nil {
+			t.Fatalf("run %d: %v", i+1, err)
+		}
+		if len(*calls) != run.wantCalls {
+			t.Fatalf("after run %d: %d calls, want %d", i+1, len(*calls), run.wantCalls)
+		}
+	}
+
+	last := (*calls)[len(*calls)-1]
+	if last.Method != "chat.update" || last.TS != "10" || !strings.Contains(last.Text, "MTV-2") {
+		t.Errorf("last call = %+v, want Bob's section back in reply 10", last)
+	}
+	if entry := people["Bob Jones"]; entry.Removed || entry.TS != "10" {
+		t.Errorf("Bob's manifest entry = %+v, want a current section in reply 10", entry)
+	}
+}
+
+func TestUpdateDailyReportThreadSplitReplies(t *testing.T) {
+	thread := &reportThread{botToken: "xoxb-test", channel: "C1", threadTS: "1.0"}
+	alice := updateTestGroup("Alice Smith", "MTV-1")
+	previous := postedManifest(thread, []PersonStatusGroup{alice}, map[string]string{"Alice Smith": "10"})
+	entry := previous["Alice Smith"]
+	entry.Parts = []string{"11"}
+	previous["Alice Smith"] = entry
+
+	t.Run("changed reply is updated across its parts", func(t *testing.T) {
+		calls := updateSlackStub(t, 0)
+		groups := []PersonStatusGroup{updateTestGroup("Alice Smith", "MTV-1", "MTV-2")}
+		updated, err := updateDailyReportThread(thread, "https://jira.example.com", groups, modeQA, previous, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, call := range *calls {
+			got = append(got, call.Method+" "+call.TS)
+		}
+		if want := "chat.update 10, chat.update 11"; strings.Join(got, ", ") != want {
+			t.Errorf("calls = %v, want %s", got, want)
+		}
+		if parts := updated["Alice Smith"].Parts; fmt.Sprint(parts) != "[11]" {
+			t.Errorf("parts = %v, want [11]", parts)
+		}
+	})
+
+	t.Run("split changes reply records every part", func(t *testing.T) {
+		updateSlackStub(t, 3)
+		groups := []PersonStatusGroup{alice, updateTestGroup("Bob Jones", "MTV-2", "MTV-3")}
+		updated, err := updateDailyReportThread(thread, "https://jira.example.com", groups, modeQA, previous, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bob := updated["Bob Jones"]
+		if bob.TS == "" || len(bob.Parts) == 0 {
+			t.Errorf("Bob's manifest entry = %+v, want the ts of every part", bob)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+// Request IDs
+//
+// In server mode every slash command gets a short random ID. It is carried in the
+// request's context, prefixed to its log lines (so the output of concurrent
+// commands can be told apart) and appended to error messages as "ref: ab12cd",
+// so users can quote it when reporting a problem.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+)
+
+// requestIDKey is the context key of the request ID.
+type requestIDKey struct{}
+
+// newRequestID returns a short random request ID such as "ab12cd".
+func newRequestID() string {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+// withRequestID returns a copy of ctx carrying the request ID.
+func withRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// requestIDFrom returns the request ID of ctx, or "" if it has none.
+func requestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// requestIDPrefix returns the log line prefix for the request ID of ctx.
+func requestIDPrefix(ctx context.Context) string {
+	if id := requestIDFrom(ctx); id != "" {
+		return "[" + id + "] "
+	}
+	return ""
+}
+
+// sendRequestError sends an error response that references the request ID of ctx.
+func sendRequestError(ctx context.Context, responseURL, errorMsg string) {
+	if id := requestIDFrom(ctx); id != "" {
+		errorMsg = fmt.Sprintf("%s\n\n_ref: %s_", errorMsg, id)
+	}
+	sendErrorResponse(responseURL, errorMsg)
+}
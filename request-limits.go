@@ -0,0 +1,96 @@
+// Request limits
+//
+// Slack payloads are small form posts (JSON for the Events API), so anything large
+// or of another content type is rejected before it reaches signature verification
+// or ParseForm. The
+// server enforces read and idle timeouts so slow clients can't hold connections
+// open indefinitely. There is no server-wide write timeout, because /api/report
+// fetches from JIRA inside the handler and may take longer; the Slack routes get
+// their own write deadline instead.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// maxSlackBodyBytes is the largest request body accepted from Slack (payloads are a few KB).
+const maxSlackBodyBytes = 64 << 10
+
+// Server timeouts (variables so tests can shorten them)
+var (
+	serverReadTimeout = 10 * time.Second
+	serverIdleTimeout = 60 * time.Second
+	// slackWriteTimeout bounds the response time of the Slack routes only
+	slackWriteTimeout = 10 * time.Second
+)
+
+// newHTTPServer creates the server with read and idle timeouts. Write deadlines
+// are set per route (see limitRequest).
+func newHTTPServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: serverReadTimeout,
+		ReadTimeout:       serverReadTimeout,
+		IdleTimeout:       serverIdleTimeout,
+	}
+}
+
+// limitFormRequest wraps a handler so it only runs for form-encoded requests with
+// a body of at most maxSlackBodyBytes. The body is read up front and restored for
+// the wrapped handler.
+func limitFormRequest(next http.HandlerFunc) http.HandlerFunc {
+	return limitRequest("application/x-www-form-urlencoded", next)
+}
+
+// limitJSONRequest is limitFormRequest for JSON requests.
+func limitJSONRequest(next http.HandlerFunc) http.HandlerFunc {
+	return limitRequest("application/json", next)
+}
+
+// limitRequest wraps a handler so it only runs for POST requests of the given
+// media type with a body of at most maxSlackBodyBytes, and must answer within
+// slackWriteTimeout.
+func limitRequest(allowedType string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Test recorders don't support deadlines
+		err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(slackWriteTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			logf("⚠️  Failed to set the write deadline for %s: %v\n", r.URL.Path, err)
+		}
+
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil || mediaType != allowedType {
+			logf("⚠️  Rejected request to %s with content type %q\n", r.URL.Path, r.Header.Get("Content-Type"))
+			http.Error(w, "Unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackBodyBytes))
+		r.Body.Close()
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				logf("⚠️  Rejected request to %s: body larger than %d bytes\n", r.URL.Path, maxSlackBodyBytes)
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			logf("⚠️  Failed to read request to %s: %v\n", r.URL.Path, err)
+			http.Error(w, "Failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next(w, r)
+	}
+}
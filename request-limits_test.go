@@ -0,0 +1,146 @@
+package main
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLimitRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		wantStatus  int
+	}{
+		{name: "form post", method: "POST", contentType: "application/x-www-form-urlencoded", body: "text=hi", wantStatus: 200},
+		{name: "form post with charset", method: "POST", contentType: "application/x-www-form-urlencoded; charset=utf-8", body: "text=hi", wantStatus: 200},
+		{name: "body at the limit", method: "POST", contentType: "application/x-www-form-urlencoded", body: strings.Repeat("a", maxSlackBodyBytes), wantStatus: 200},
+		{name: "oversized body", method: "POST", contentType: "application/x-www-form-urlencoded", body: strings.Repeat("a", maxSlackBodyBytes+1), wantStatus: 413},
+		{name: "JSON to a form route", method: "POST", contentType: "application/json", body: "{}", wantStatus: 415},
+		{name: "multipart", method: "POST", contentType: "multipart/form-data; boundary=x", body: "--x--", wantStatus: 415},
+		{name: "no content type", method: "POST", body: "text=hi", wantStatus: 415},
+		{name: "GET", method: "GET", wantStatus: 405},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := limitFormRequest(func(w http.ResponseWriter, r *http.Request) {
+				data, _ := io.ReadAll(r.Body)
+				got = string(data)
+			})
+
+			req := httptest.NewRequest(tt.method, "/slack/issues", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			handler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == 200 && got != tt.body {
+				t.Errorf("handler saw a %d byte body, want %d bytes", len(got), len(tt.body))
+			}
+		})
+	}
+}
+
+// serveTestServer runs newHTTPServer with handler on a loopback listener and
+// returns its address.
+func serveTestServer(t *testing.T, handler http.Handler) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := newHTTPServer(listener.Addr().String(), handler)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().String()
+}
+
+// shortenServerTimeouts sets the server timeouts for the test.
+func shortenServerTimeouts(t *testing.T, read, slackWrite time.Duration) {
+	t.Helper()
+	savedRead, savedWrite := serverReadTimeout, slackWriteTimeout
+	serverReadTimeout, slackWriteTimeout = read, slackWrite
+	t.Cleanup(func() { serverReadTimeout, slackWriteTimeout = savedRead, savedWrite })
+}
+
+func TestServerDropsSlowClients(t *testing.T) {
+	tests := []struct {
+		name    string
+		partial string
+	}{
+		{name: "headers never finish", partial: "POST /slack/issues HTTP/1.1\r\nHost: test\r\nContent-Type: application/x-www-form-urlencoded\r\n"},
+		{name: "body never finishes", partial: "POST /slack/issues HTTP/1.1\r\nHost: test\r\nContent-Type: application/x-www-form-urlencoded\r\nContent-Length: 100\r\n\r\ntext=hi"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shortenServerTimeouts(t, 200*time.Millisecond, time.Second)
+			mux := http.NewServeMux()
+			mux.HandleFunc("/slack/issues", limitFormRequest(func(w http.ResponseWriter, r *http.Request) {}))
+			addr := serveTestServer(t, mux)
+
+			conn, err := net.Dial("tcp", addr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+			if _, err := conn.Write([]byte(tt.partial)); err != nil {
+				t.Fatal(err)
+			}
+
+			// The server gives up after its read timeout; the test gives it much longer
+			conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			start := time.Now()
+			response, err := io.ReadAll(conn)
+			if err != nil {
+				t.Fatalf("connection still open after %v: %v", time.Since(start), err)
+			}
+			if strings.HasPrefix(string(response), "HTTP/1.1 200") {
+				t.Errorf("partial request was answered with %q", response)
+			}
+		})
+	}
+}
+
+func TestWriteDeadlineOnlyOnSlackRoutes(t *testing.T) {
+	shortenServerTimeouts(t, time.Second, 100*time.Millisecond)
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte("done"))
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slack/issues", limitFormRequest(slow))
+	mux.HandleFunc("/api/report", slow)
+	addr := serveTestServer(t, mux)
+
+	resp, err := http.Get("http://" + addr + "/api/report")
+	if err != nil {
+		t.Fatalf("slow /api/report was cut off: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != 200 || string(body) != "done" {
+		t.Errorf("/api/report = %d %q, want 200 done", resp.StatusCode, body)
+	}
+
+	resp, err = http.Post("http://"+addr+"/slack/issues", "application/x-www-form-urlencoded", strings.NewReader("text=hi"))
+	if err == nil {
+		body, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err == nil && string(body) == "done" {
+			t.Error("slow Slack handler answered after its write deadline")
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Issue resolution
+//
+// Closed issues look alike without their resolution (Done, Won't Do, Duplicate),
+// so issues in a closed status show it next to the status, e.g.
+// "*Resolution:* Won't Do". Issues without a resolution show nothing.
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// closedStatuses are the statuses whose issues show their resolution.
+var closedStatuses = []string{"Closed", "Done", "Resolved"}
+
+// issueResolution returns the resolution name of an issue, or "" if it has none.
+func issueResolution(issue JiraIssue) string {
+	if issue.Fields.Resolution == nil {
+		return ""
+	}
+	return issue.Fields.Resolution.Name
+}
+
+// issueResolved returns when the issue was resolved, or zero if it is unresolved
+// or the resolutiondate field wasn't fetched.
+func issueResolved(issue JiraIssue) time.Time {
+	resolved, err := time.Parse(jiraTimeLayout, issue.Fields.ResolutionDate)
+	if err != nil {
+		return time.Time{}
+	}
+	return resolved
+}
+
+// resolutionSuffix renders the resolution part of an issue line, or "" unless the
+// issue is closed with a resolution.
+func resolutionSuffix(issue IssueItem) string {
+	if issue.Resolution == "" {
+		return ""
+	}
+	for _, status := range closedStatuses {
+		if strings.EqualFold(issue.Status, status) {
+			return fmt.Sprintf("  |  *Resolution:* %s", escapeSlackText(issue.Resolution))
+		}
+	}
+	return ""
+}
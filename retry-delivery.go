@@ -0,0 +1,244 @@
+// Retrying failed deliveries
+//
+// When a report is only partly delivered (a channel or some replies failed), the
+// run writes failed-delivery.json to STATE_DIR listing, per channel, the thread
+// that was started and the people whose sections weren't posted, along with the
+// grouped issues. Running with -retry-from=<file> posts only those sections: into
+// the existing thread if one was started, otherwise under a new header. The saved
+// issues are reused while they are younger than RETRY_MAX_AGE minutes (default
+// 60); older ones are refetched with the saved query.
+//
+// Sections that still fail are written back to the file; once everything is
+// delivered the file is removed.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// failedDeliveryFile is the name of the failure manifest inside STATE_DIR
+const failedDeliveryFile = "failed-delivery.json"
+
+// failedDelivery lists what a run couldn't post and the data to post it from.
+type failedDelivery struct {
+	Mode      reportMode          `json:"mode"`
+	FetchedAt time.Time           `json:"fetched_at"`
+	JQL       string              `json:"jql"`
+	Date      string              `json:"date"` // Date shown in headers of threads started by a retry
+	Channels  []failedChannel     `json:"channels"`
+	Groups    []PersonStatusGroup `json:"groups"`
+}
+
+// failedChannel is one channel's undelivered share of the report. A missing
+// ThreadTS means the thread itself wasn't started. No people means only the
+// closing replies (overflow and footer) are missing.
+type failedChannel struct {
+	Channel  string   `json:"channel"`
+	ThreadTS string   `json:"thread_ts,omitempty"`
+	People   []string `json:"people,omitempty"`
+}
+
+// retryMaxAge returns how old saved issues may be before a retry refetches them.
+func retryMaxAge() time.Duration {
+	return time.Duration(envInt("RETRY_MAX_AGE", 60)) * time.Minute
+}
+
+// unpostedPeople returns the people of groups shown in full whose sections aren't in posted.
+func unpostedPeople(groups []PersonStatusGroup, posted map[string]manifestPerson) []string {
+	shown, _ := splitPersonOverflow(groups, maxPeople(), overflowByName())
+	var people []string
+	for _, group := range shown {
+		if _, ok := posted[group.Person]; !ok {
+			people = append(people, group.Person)
+		}
+	}
+	return people
+}
+
+// failedThread describes what is missing from a thread after sending failed.
+func failedThread(thread *reportThread, groups []PersonStatusGroup, posted map[string]manifestPerson) failedChannel {
+	return failedChannel{
+		Channel:  thread.channel,
+		ThreadTS: thread.threadTS,
+		People:   unpostedPeople(groups, posted),
+	}
+}
+
+// saveFailedDelivery writes the failure manifest and tells how to retry it.
+// Historical and dry runs aren't retried this way, so nothing is written for them.
+func saveFailedDelivery(mode reportMode, jql, date string, channels []failedChannel, groups []PersonStatusGroup) {
+	if !asOfTime.IsZero() || dryRun || len(channels) == 0 {
+		return
+	}
+
+	failed := failedDelivery{
+		Mode:      mode,
+		FetchedAt: time.Now().UTC(),
+		JQL:       jql,
+		Date:      date,
+		Channels:  channels,
+		Groups:    groups,
+	}
+	if err := writeStateFile(failedDeliveryFile, failed); err != nil {
+		logf("⚠️  Failed to write failure manifest: %v\n", err)
+		return
+	}
+	logf("🔄 Retry the undelivered parts with -retry-from=%s\n", filepath.Join(stateDir(), failedDeliveryFile))
+}
+
+// loadFailedDelivery reads a failure manifest.
+func loadFailedDelivery(path string) (failedDelivery, error) {
+	var failed failedDelivery
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return failed, fmt.Errorf("failed to read failure manifest: %w", err)
+	}
+	if err := json.Unmarshal(data, &failed); err != nil {
+		return failed, fmt.Errorf("failed to parse failure manifest: %w", err)
+	}
+	return failed, nil
+}
+
+// runRetry posts the sections listed in the failure manifest at path.
+func runRetry(path string) {
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
+	if jiraURL == "" || jiraToken == "" || slackBotToken == "" {
+		logln("❌ Missing required credentials")
+		logln("Please set environment variables: JIRA_URL, JIRA_TOKEN, SLACK_BOT_TOKEN")
+		os.Exit(1)
+	}
+
+	failed, err := loadFailedDelivery(path)
+	if err != nil {
+		logf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	logf("🔄 Retrying %d channel(s) of the %s report from %s\n", len(failed.Channels), failed.Mode, path)
+
+	groups := failed.Groups
+	if age := time.Since(failed.FetchedAt); age > retryMaxAge() {
+		logf("   Saved issues are %s old, refetching...\n", age.Round(time.Minute))
+		grouper := newPersonGrouper(failed.Mode)
+		_, err := streamJiraIssues(jiraURL, jiraToken, failed.JQL, reportFetchOptions(failed.Mode), func(page JiraSearchResponse) error {
+			grouper.add(page)
+			return nil
+		})
+		if err != nil {
+			logf("❌ Failed to fetch JIRA issues: %v\n", err)
+			os.Exit(1)
+		}
+		groups = grouper.groups()
+		if envBool("SHOW_EPIC", false) {
+			resolveEpicSummaries(jiraURL, jiraToken, groups)
+		}
+	}
+	byPerson := make(map[string]PersonStatusGroup, len(groups))
+	for _, group := range groups {
+		byPerson[group.Person] = group
+	}
+
+	stats := newRunStats()
+	posted := make(map[string]manifestPerson)
+	var remaining []failedChannel
+	var lastThread *reportThread
+	for _, channel := range failed.Channels {
+		var channelGroups []PersonStatusGroup
+		for _, person := range channel.People {
+			if group, ok := byPerson[person]; ok {
+				channelGroups = append(channelGroups, group)
+			} else {
+				logf("   ⏭️  %s no longer has issues in the report\n", person)
+			}
+		}
+
+		thread := newReportThread(slackBotToken, channel.Channel, stats)
+		if channel.ThreadTS != "" {
+			logf("   %s: continuing thread %s with %d people\n", channel.Channel, channel.ThreadTS, len(channelGroups))
+			thread.threadTS = channel.ThreadTS
+		} else {
+			logf("   %s: starting a new thread with %d people\n", channel.Channel, len(channelGroups))
+			if err := thread.start(buildHeaderBlocks(failed.Mode, failed.Date, "", channelGroups, envRender)); err != nil {
+				logf("   ❌ %s failed: %v\n", channel.Channel, err)
+				remaining = append(remaining, channel)
+				continue
+			}
+		}
+
+		channelPosted, err := sendDailyReportThreaded(thread, jiraURL, failed.JQL, channelGroups, failed.Mode, stats, envRender)
+		for person, entry := range channelPosted {
+			posted[person] = entry
+		}
+		if err != nil {
+			logf("   ❌ %s failed: %v\n", channel.Channel, err)
+			remaining = append(remaining, failedThread(thread, channelGroups, channelPosted))
+			continue
+		}
+		lastThread = thread
+		logf("   ✅ %s delivered\n", channel.Channel)
+	}
+
+	if len(posted) > 0 {
+		if err := mergeReportManifest(failed.Mode, lastThread, posted); err != nil {
+			logf("⚠️  Failed to update report manifest: %v\n", err)
+		}
+	}
+
+	if len(remaining) > 0 {
+		failed.Channels = remaining
+		if err := writeFailedDeliveryFile(path, failed); err != nil {
+			logf("⚠️  %v\n", err)
+		}
+		logf("❌ %d channel(s) still incomplete, retry again with -retry-from=%s\n", len(remaining), path)
+		os.Exit(1)
+	}
+
+	if err := os.Remove(path); err != nil {
+		logf("⚠️  Failed to remove %s: %v\n", path, err)
+	}
+	if err := recordSuccessfulRun(failed.Mode, failed.FetchedAt, countGroupIssues(groups)); err != nil {
+		logf("⚠️  Failed to record successful run: %v\n", err)
+	}
+	logln("\n✅ Delivered everything the failed run missed")
+}
+
+// writeFailedDeliveryFile rewrites a failure manifest at an arbitrary path.
+func writeFailedDeliveryFile(path string, failed failedDelivery) error {
+	data, err := json.MarshalIndent(failed, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal failure manifest: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write failure manifest: %w", err)
+	}
+	return nil
+}
+
+// mergeReportManifest adds the retried sections to the mode's manifest, starting
+// one from thread if the failed run didn't leave any.
+func mergeReportManifest(mode reportMode, thread *reportThread, posted map[string]manifestPerson) error {
+	manifests, err := loadReportManifests()
+	if err != nil {
+		return err
+	}
+	manifest, ok := manifests[string(mode)]
+	if !ok {
+		if thread == nil {
+			return nil
+		}
+		manifest = reportManifest{Mode: mode, Channel: thread.channel, ThreadTS: thread.threadTS}
+	}
+	if manifest.People == nil {
+		manifest.People = make(map[string]manifestPerson)
+	}
+	for person, entry := range posted {
+		manifest.People[person] = entry
+	}
+	manifest.PostedAt = time.Now().UTC()
+	return saveReportManifest(manifest)
+}
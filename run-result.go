@@ -0,0 +1,181 @@
+// Machine-readable run results
+//
+// Wrapper scripts shouldn't have to parse the emoji log to decide whether to
+// alert. With -json-output the report run writes exactly one JSON document to
+// stdout when it ends, however it ends, and everything logged goes to stderr:
+//
+//	{"success":false,"outcome":"degraded","exit_code":2,"mode":"qa",
+//	 "counts":{"fetched":120,"issues":98,"filtered":22,"people":14},
+//	 "thread":{"channel":"C0123","thread_ts":"1714…","permalink":"https://…"},
+//	 "timing":{...},"people":[{"person":"Jane Doe","issues":7,"status":"sent",...}]}
+//
+// outcome is one of ok, skipped, degraded, partial, person_not_found and failed;
+// exit_code is the process's exit code. timing is the runStats breakdown. Each
+// person shown in the report gets a status of sent, overflow (summarized in the
+// overflow reply) or failed. Fields are only ever added, never renamed or removed.
+//
+// -json-output is only supported for the Slack report, without -dry-run (whose
+// messages own stdout).
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+)
+
+// jsonOutput is the -json-output flag.
+var jsonOutput bool
+
+// activeRunResult collects the results of a -json-output run; nil otherwise.
+var activeRunResult *runResult
+
+// runResultOutput is where the result document is written: the real stdout.
+var runResultOutput io.Writer = os.Stdout
+
+// errUsage stands in for invalid command-line flags, which are explained in the log.
+var errUsage = errors.New("invalid command-line flags")
+
+// runResult is the -json-output document.
+type runResult struct {
+	Success  bool           `json:"success"`
+	Outcome  string         `json:"outcome"`
+	ExitCode int            `json:"exit_code"`
+	Error    string         `json:"error,omitempty"`
+	Skipped  string         `json:"skipped,omitempty"` // Why no report was due
+	Mode     reportMode     `json:"mode,omitempty"`
+	Counts   runCounts      `json:"counts"`
+	Thread   *runThread     `json:"thread,omitempty"`
+	Timing   *runStats      `json:"timing,omitempty"`
+	People   []personResult `json:"people"`
+}
+
+// runCounts are the issue and person counts of the run.
+type runCounts struct {
+	Fetched  int `json:"fetched"`
+	Issues   int `json:"issues"`   // Issues in the report, after filtering
+	Filtered int `json:"filtered"` // Issues the filters removed
+	People   int `json:"people"`
+}
+
+// runThread is the report's thread; with TEAM_CHANNEL_MAP, the default channel's.
+type runThread struct {
+	Channel   string `json:"channel"`
+	ThreadTS  string `json:"thread_ts"`
+	Permalink string `json:"permalink,omitempty"`
+}
+
+// personResult is how one person's section was delivered.
+type personResult struct {
+	Person  string `json:"person"`
+	Issues  int    `json:"issues"`
+	Status  string `json:"status"` // sent, overflow or failed
+	Channel string `json:"channel,omitempty"`
+	TS      string `json:"ts,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// startRunResult moves logging to stderr and starts collecting the run's results.
+func startRunResult() {
+	runResultOutput = os.Stdout
+	os.Stdout = os.Stderr
+	activeRunResult = &runResult{People: []personResult{}}
+}
+
+// recordCounts notes the fetched, kept and filtered issue counts. Safe to call on a nil receiver.
+func (r *runResult) recordCounts(metrics filterMetrics, groups []PersonStatusGroup) {
+	if r == nil {
+		return
+	}
+	r.Counts = runCounts{
+		Fetched:  metrics.Fetched,
+		Issues:   countGroupIssues(groups),
+		Filtered: metrics.Excluded,
+		People:   len(groups),
+	}
+}
+
+// recordThread notes the report's thread. Safe to call on a nil receiver.
+func (r *runResult) recordThread(channel, threadTS string) {
+	if r == nil || threadTS == "" {
+		return
+	}
+	r.Thread = &runThread{Channel: channel, ThreadTS: threadTS}
+}
+
+// recordDelivery notes the delivery of each person of groups: sent if their
+// section is in posted, otherwise overflow or, after err, failed. Safe to call
+// on a nil receiver.
+func (r *runResult) recordDelivery(groups []PersonStatusGroup, posted map[string]manifestPerson, err error) {
+	if r == nil {
+		return
+	}
+	shown, overflow := splitPersonOverflow(groups, maxPeople(), overflowByName())
+	for _, group := range shown {
+		result := personResult{Person: group.Person, Issues: group.TotalIssues, Status: "failed"}
+		if entry, ok := posted[group.Person]; ok {
+			result.Status, result.Channel, result.TS = "sent", entry.Channel, entry.TS
+		} else if err != nil {
+			result.Error = redactSecrets(err.Error())
+		}
+		r.People = append(r.People, result)
+	}
+	for _, group := range overflow {
+		result := personResult{Person: group.Person, Issues: group.TotalIssues, Status: "overflow"}
+		if err != nil {
+			result.Status, result.Error = "failed", redactSecrets(err.Error())
+		}
+		r.People = append(r.People, result)
+	}
+}
+
+// emit classifies the run by its exit code and error and writes the document.
+// Safe to call on a nil receiver.
+func (r *runResult) emit(code int, err error) {
+	if r == nil {
+		return
+	}
+	r.ExitCode = code
+	r.Success = code == 0
+	switch {
+	case code == 0 && r.Skipped != "":
+		r.Outcome = "skipped"
+	case code == 0:
+		r.Outcome = "ok"
+	case errors.Is(err, errDegradedDelivery):
+		r.Outcome = "degraded"
+	case errors.Is(err, errPartialDelivery):
+		r.Outcome = "partial"
+	case code == exitPersonNotFound:
+		r.Outcome = "person_not_found"
+	default:
+		r.Outcome = "failed"
+	}
+	if err != nil {
+		r.Error = redactSecrets(err.Error())
+	}
+	if r.Timing != nil && r.Timing.TotalMS == 0 {
+		r.Timing.finish()
+	}
+	if r.Thread != nil {
+		if permalink, err := slackPermalink(os.Getenv("SLACK_BOT_TOKEN"), r.Thread.Channel, r.Thread.ThreadTS); err == nil {
+			r.Thread.Permalink = permalink
+		} else {
+			logf("⚠️  Failed to get the thread permalink: %v\n", err)
+		}
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		logf("⚠️  Failed to encode the run result: %v\n", err)
+		return
+	}
+	runResultOutput.Write(append(data, '\n'))
+}
+
+// exitRun writes the -json-output document, if any, and exits with code.
+func exitRun(code int, err error) {
+	activeRunResult.emit(code, err)
+	os.Exit(code)
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureOutput points os.Stdout and os.Stderr at pipes while run runs and
+// returns what was written to each. The run result state is reset afterwards.
+func captureOutput(t *testing.T, run func()) (stdout, stderr string) {
+	t.Helper()
+	outR, outW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	errR, errW, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedStdout, savedStderr, savedOutput := os.Stdout, os.Stderr, runResultOutput
+	os.Stdout, os.Stderr = outW, errW
+	defer func() {
+		os.Stdout, os.Stderr, runResultOutput = savedStdout, savedStderr, savedOutput
+		activeRunResult = nil
+	}()
+
+	// Read concurrently so a full pipe can't block run
+	outC, errC := make(chan string), make(chan string)
+	go func() { data, _ := io.ReadAll(outR); outC <- string(data) }()
+	go func() { data, _ := io.ReadAll(errR); errC <- string(data) }()
+
+	run()
+	outW.Close()
+	errW.Close()
+	return <-outC, <-errC
+}
+
+func TestRunResultWritesOneDocument(t *testing.T) {
+	stdout, stderr := captureOutput(t, func() {
+		startRunResult()
+		logf("📤 Sending report to Slack...\n")
+		logln("   ✓ Thread created")
+		activeRunResult.Mode = modeQA
+		activeRunResult.recordCounts(filterMetrics{Fetched: 5, Excluded: 2}, []PersonStatusGroup{{Person: "Ann", TotalIssues: 3}})
+		activeRunResult.emit(2, fmt.Errorf("failed to send: %w", errDegradedDelivery))
+	})
+
+	if !strings.Contains(stderr, "Sending report to Slack") || !strings.Contains(stderr, "Thread created") {
+		t.Errorf("log didn't go to stderr: %q", stderr)
+	}
+	if strings.Count(stdout, "\n") != 1 || !strings.HasSuffix(stdout, "\n") {
+		t.Fatalf("stdout isn't a single line: %q", stdout)
+	}
+
+	dec := json.NewDecoder(strings.NewReader(stdout))
+	var result map[string]interface{}
+	if err := dec.Decode(&result); err != nil {
+		t.Fatalf("stdout isn't JSON: %v\n%s", err, stdout)
+	}
+	if dec.More() {
+		t.Errorf("stdout has more than one document: %q", stdout)
+	}
+	for field, want := range map[string]interface{}{"success": false, "outcome": "degraded", "exit_code": 2.0, "mode": "qa"} {
+		if result[field] != want {
+			t.Errorf("%s = %v, want %v", field, result[field], want)
+		}
+	}
+	if counts, _ := result["counts"].(map[string]interface{}); counts["fetched"] != 5.0 || counts["filtered"] != 2.0 || counts["people"] != 1.0 {
+		t.Errorf("counts = %v", result["counts"])
+	}
+	if people, ok := result["people"].([]interface{}); !ok || len(people) != 0 {
+		t.Errorf("people = %v, want an empty list", result["people"])
+	}
+}
+
+func TestRunResultOutcome(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    int
+		err     error
+		skipped string
+		want    string
+	}{
+		{name: "ok", code: 0, want: "ok"},
+		{name: "skipped", code: 0, skipped: "weekend", want: "skipped"},
+		{name: "degraded", code: 2, err: errDegradedDelivery, want: "degraded"},
+		{name: "partial", code: 2, err: fmt.Errorf("x: %w", errPartialDelivery), want: "partial"},
+		{name: "person not found", code: exitPersonNotFound, err: fmt.Errorf("no match"), want: "person_not_found"},
+		{name: "failed", code: 1, err: fmt.Errorf("boom"), want: "failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &runResult{Skipped: tt.skipped, People: []personResult{}}
+			var out strings.Builder
+			saved := runResultOutput
+			runResultOutput = &out
+			defer func() { runResultOutput = saved }()
+
+			result.emit(tt.code, tt.err)
+			if result.Outcome != tt.want {
+				t.Errorf("outcome = %q, want %q", result.Outcome, tt.want)
+			}
+			if strings.Count(out.String(), "\n") != 1 {
+				t.Errorf("wrote %q, want one line", out.String())
+			}
+		})
+	}
+}
+
+func TestRunResultNilIsSilent(t *testing.T) {
+	stdout, _ := captureOutput(t, func() {
+		var result *runResult
+		result.recordThread("C1", "1.0")
+		result.emit(1, fmt.Errorf("boom"))
+	})
+	if stdout != "" {
+		t.Errorf("a run without -json-output wrote %q", stdout)
+	}
+}
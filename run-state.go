@@ -0,0 +1,206 @@
+// Run state
+//
+// The report keeps a small JSON state file in STATE_DIR (default: the working
+// directory) recording when each report mode last posted successfully. Queries
+// that look for changes "since the last report" use it instead of a fixed -24h,
+// so a drifting cron or a skipped day doesn't silently miss items.
+//
+// JIRA_JQL may contain the ${LAST_RUN} placeholder, e.g.
+//
+//	project = MTV AND status CHANGED AFTER "${LAST_RUN}"
+//
+// which is replaced by the start of the window, formatted in the JIRA server's timezone.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// runStateFile is the name of the state file inside STATE_DIR
+	runStateFile = "run-state.json"
+
+	// defaultRunWindow is used when there is no recorded successful run yet
+	defaultRunWindow = 24 * time.Hour
+
+	// maxRunWindow caps how far back a window reaches after a long outage
+	maxRunWindow = 7 * 24 * time.Hour
+
+	// jqlTimeLayout is the date format accepted in JQL date comparisons
+	jqlTimeLayout = "2006-01-02 15:04"
+
+	// lastRunPlaceholder is substituted in JIRA_JQL with the window start
+	lastRunPlaceholder = "${LAST_RUN}"
+)
+
+// runState is persisted between runs.
+type runState struct {
+	LastSuccess map[string]time.Time `json:"last_success"`         // Keyed by report mode
+	LastTotal   map[string]int       `json:"last_total,omitempty"` // Issues in the last posted report, by mode
+}
+
+// stateDir returns the directory holding persistent state files.
+func stateDir() string {
+	if dir := os.Getenv("STATE_DIR"); dir != "" {
+		return dir
+	}
+	return "."
+}
+
+// loadRunState reads the state file. A missing file yields an empty state.
+func loadRunState() (runState, error) {
+	state := runState{LastSuccess: make(map[string]time.Time)}
+
+	data, err := os.ReadFile(filepath.Join(stateDir(), runStateFile))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("failed to read run state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse run state: %w", err)
+	}
+	if state.LastSuccess == nil {
+		state.LastSuccess = make(map[string]time.Time)
+	}
+	return state, nil
+}
+
+// saveRunState writes the state file atomically.
+func saveRunState(state runState) error {
+	return writeStateFile(runStateFile, state)
+}
+
+// writeStateFile writes v as JSON to the named file in STATE_DIR atomically.
+func writeStateFile(name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	path := filepath.Join(stateDir(), name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+	// A temp file of its own, so processes sharing STATE_DIR can't mix their writes
+	tmp, err := os.CreateTemp(filepath.Dir(path), name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// recordSuccessfulRun stores the time and issue total of a successful post for the mode.
+// Called only after the report was delivered, so missed days are covered next time.
+func recordSuccessfulRun(mode reportMode, at time.Time, total int) error {
+	state, err := loadRunState()
+	if err != nil {
+		return err
+	}
+	state.LastSuccess[string(mode)] = at
+	if state.LastTotal == nil {
+		state.LastTotal = make(map[string]int)
+	}
+	state.LastTotal[string(mode)] = total
+	return saveRunState(state)
+}
+
+// runWindowStart returns the start of the "since last report" window for the mode:
+// the last successful run, or 24h ago if none, but never more than 7 days back.
+func runWindowStart(mode reportMode, now time.Time) time.Time {
+	start := now.Add(-defaultRunWindow)
+
+	state, err := loadRunState()
+	if err != nil {
+		logf("⚠️  %v, using a %s window\n", err, defaultRunWindow)
+	} else if last, ok := state.LastSuccess[string(mode)]; ok && last.Before(now) {
+		start = last
+	}
+
+	if earliest := now.Add(-maxRunWindow); start.Before(earliest) {
+		start = earliest
+	}
+	return start
+}
+
+// fetchJiraServerLocation returns the JIRA server's timezone, derived from the
+// UTC offset of serverTime in /rest/api/2/serverInfo.
+func fetchJiraServerLocation(jiraURL, jiraToken string) (*time.Location, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/rest/api/2/serverInfo", jiraURL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	setJiraAuth(req, jiraToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("JIRA API returned %d: %s", resp.StatusCode, redactSecrets(string(body)))
+	}
+
+	var info struct {
+		ServerTime string `json:"serverTime"`
+	}
+	if err := json.Unmarshal(body, &info); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	serverTime, err := time.Parse(jiraTimeLayout, info.ServerTime)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected serverTime %q: %w", info.ServerTime, err)
+	}
+	_, offset := serverTime.Zone()
+	return time.FixedZone("JIRA", offset), nil
+}
+
+// formatJQLTime formats t for a JQL date comparison in the given location.
+func formatJQLTime(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(jqlTimeLayout)
+}
+
+// expandLastRunPlaceholder substitutes ${LAST_RUN} in jql with the start of the
+// mode's window. Queries without the placeholder are returned unchanged and
+// don't cost a serverInfo request.
+func expandLastRunPlaceholder(jql string, mode reportMode, jiraURL, jiraToken string) string {
+	if !strings.Contains(jql, lastRunPlaceholder) {
+		return jql
+	}
+
+	loc, err := fetchJiraServerLocation(jiraURL, jiraToken)
+	if err != nil {
+		logf("⚠️  Failed to get JIRA server timezone, using UTC: %v\n", err)
+		loc = time.UTC
+	}
+
+	since := formatJQLTime(runWindowStart(mode, time.Now()), loc)
+	logf("   Window starts at %s (JIRA server time)\n", since)
+	return strings.ReplaceAll(jql, lastRunPlaceholder, since)
+}
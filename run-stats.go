@@ -0,0 +1,68 @@
+// Run timing
+//
+// runStats records where the time of a daily report run goes: the JIRA fetch,
+// grouping, each Slack post and the delays between posts. It is printed at the
+// end of the run with -verbose and carries JSON tags so it can be exported.
+package main
+
+import "time"
+
+// runStats is the timing breakdown of a single daily report run.
+// All durations are serialized in milliseconds.
+type runStats struct {
+	Started        time.Time `json:"started"`
+	JiraFetchMS    int64     `json:"jira_fetch_ms"`
+	JiraPages      int       `json:"jira_pages"`
+	GroupingMS     int64     `json:"grouping_ms"`
+	SlackLatencyMS []int64   `json:"slack_latency_ms"` // One entry per posted message, in order
+	SleepMS        int64     `json:"sleep_ms"`
+	TotalMS        int64     `json:"total_ms"`
+}
+
+// newRunStats starts timing a run.
+func newRunStats() *runStats {
+	return &runStats{Started: time.Now()}
+}
+
+// recordSlack records the latency of one Slack post. Safe to call on a nil receiver.
+func (s *runStats) recordSlack(d time.Duration) {
+	if s == nil {
+		return
+	}
+	s.SlackLatencyMS = append(s.SlackLatencyMS, d.Milliseconds())
+}
+
+// sleep pauses for d and accounts it as sleep time. Safe to call on a nil receiver.
+func (s *runStats) sleep(d time.Duration) {
+	time.Sleep(d)
+	if s != nil {
+		s.SleepMS += d.Milliseconds()
+	}
+}
+
+// finish stamps the total wall clock time of the run.
+func (s *runStats) finish() {
+	s.TotalMS = time.Since(s.Started).Milliseconds()
+}
+
+// print writes the timing breakdown to stdout.
+func (s *runStats) print() {
+	var slackTotal, slackMax int64
+	for _, ms := range s.SlackLatencyMS {
+		slackTotal += ms
+		if ms > slackMax {
+			slackMax = ms
+		}
+	}
+	slackAvg := int64(0)
+	if len(s.SlackLatencyMS) > 0 {
+		slackAvg = slackTotal / int64(len(s.SlackLatencyMS))
+	}
+
+	logln("\n⏱️  Run timing:")
+	logf("   JIRA fetch:  %dms (%d page(s))\n", s.JiraFetchMS, s.JiraPages)
+	logf("   Grouping:    %dms\n", s.GroupingMS)
+	logf("   Slack posts: %d message(s), %dms total (avg %dms, max %dms)\n", len(s.SlackLatencyMS), slackTotal, slackAvg, slackMax)
+	logf("   Sleeps:      %dms\n", s.SleepMS)
+	logf("   Total:       %dms\n", s.TotalMS)
+}
@@ -0,0 +1,48 @@
+// Posting days
+//
+// With WEEKDAYS_ONLY=true the daily report doesn't post on Saturdays and Sundays,
+// and SKIP_DATES (comma-separated YYYY-MM-DD, e.g. holidays) skips specific days,
+// so a plain daily cron entry is enough. Days are evaluated in REPORT_TIMEZONE
+// (an IANA name like "Asia/Jerusalem"), defaulting to the machine's local time.
+package main
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// reportLocation returns the timezone the report's posting days are evaluated in.
+func reportLocation() *time.Location {
+	name := strings.TrimSpace(os.Getenv("REPORT_TIMEZONE"))
+	if name == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		logf("⚠️  Invalid REPORT_TIMEZONE %q, using local time: %v\n", name, err)
+		return time.Local
+	}
+	return loc
+}
+
+// skipReason returns why no report should be posted at now, or "" to post.
+func skipReason(now time.Time) string {
+	local := now.In(reportLocation())
+
+	if envBool("WEEKDAYS_ONLY", false) && (local.Weekday() == time.Saturday || local.Weekday() == time.Sunday) {
+		return local.Weekday().String() + " (WEEKDAYS_ONLY)"
+	}
+
+	today := local.Format("2006-01-02")
+	for _, date := range envList("SKIP_DATES") {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			logf("⚠️  Ignoring invalid SKIP_DATES entry %q (expected YYYY-MM-DD)\n", date)
+			continue
+		}
+		if date == today {
+			return today + " (SKIP_DATES)"
+		}
+	}
+	return ""
+}
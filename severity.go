@@ -0,0 +1,149 @@
+// Bug severity
+//
+// Bugs carry a Severity custom field (JIRA_FIELD_SEVERITY, defaulting to Red Hat
+// JIRA's field) that is shown next to the status of Bug-type issues. With
+// SORT_BY_SEVERITY=true, bugs are moved to the top of each status group ordered
+// by SEVERITY_ORDER (most severe first).
+//
+// During release crunch, -min-severity=High hides bugs ranked below High in
+// SEVERITY_ORDER from the report. Bugs without a (known) severity stay, and the
+// thread's footer says how many bugs were hidden.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultSeverityField is the Severity custom field in Red Hat JIRA.
+const defaultSeverityField = "customfield_12316142"
+
+// defaultSeverityOrder is Red Hat JIRA's severity scheme, most severe first.
+var defaultSeverityOrder = []string{"Urgent", "Critical", "High", "Important", "Medium", "Moderate", "Low", "Informational"}
+
+// severityField returns the configured Severity field ID.
+func severityField() string {
+	if field := os.Getenv("JIRA_FIELD_SEVERITY"); field != "" {
+		return field
+	}
+	return defaultSeverityField
+}
+
+// issueSeverity returns the severity of a Bug, or "" for other issue types and unset values.
+// The field may be a plain string or a select-list object with a "value" key.
+func issueSeverity(issue JiraIssue) string {
+	if !strings.EqualFold(issue.Fields.IssueType.Name, "Bug") {
+		return ""
+	}
+	return issue.customFieldString(severityField())
+}
+
+// severitySuffix renders the severity part of an issue line, or "" if the issue has none.
+func severitySuffix(issue IssueItem) string {
+	if issue.Severity == "" {
+		return ""
+	}
+	return fmt.Sprintf("  |  *Severity:* %s", escapeSlackText(issue.Severity))
+}
+
+// severityRanks maps lowercased severity names to their rank in SEVERITY_ORDER (0 is most severe).
+func severityRanks() map[string]int {
+	order := envList("SEVERITY_ORDER")
+	if len(order) == 0 {
+		order = defaultSeverityOrder
+	}
+
+	ranks := make(map[string]int, len(order))
+	for i, name := range order {
+		ranks[strings.ToLower(name)] = i
+	}
+	return ranks
+}
+
+// severityFilter hides bugs ranked below a minimum severity.
+type severityFilter struct {
+	min     string
+	minRank int
+	ranks   map[string]int
+}
+
+// activeSeverityFilter is set at startup from -min-severity and applied when grouping issues.
+var activeSeverityFilter severityFilter
+
+// hiddenBugs is how many bugs the running report hid with -min-severity, noted in its footer.
+var hiddenBugs int
+
+// loadSeverityFilter validates the -min-severity value against SEVERITY_ORDER.
+// An empty value disables the filter.
+func loadSeverityFilter(minSeverity string) (severityFilter, error) {
+	minSeverity = strings.TrimSpace(minSeverity)
+	if minSeverity == "" {
+		return severityFilter{}, nil
+	}
+
+	ranks := severityRanks()
+	rank, ok := ranks[strings.ToLower(minSeverity)]
+	if !ok {
+		order := envList("SEVERITY_ORDER")
+		if len(order) == 0 {
+			order = defaultSeverityOrder
+		}
+		return severityFilter{}, fmt.Errorf("-min-severity %q is not in the severity order (%s)", minSeverity, strings.Join(order, ", "))
+	}
+	return severityFilter{min: minSeverity, minRank: rank, ranks: ranks}, nil
+}
+
+// hides reports whether the issue is a bug ranked below the minimum severity.
+func (f severityFilter) hides(issue JiraIssue) bool {
+	if f.ranks == nil {
+		return false
+	}
+	rank, ok := f.ranks[strings.ToLower(issueSeverity(issue))]
+	return ok && rank > f.minRank
+}
+
+// reason returns the exclusionReason rule of hidden bugs.
+func (f severityFilter) reason() string {
+	return "severity below " + f.min
+}
+
+// buildHiddenBugsBlocks creates the footer note counting bugs hidden by
+// -min-severity, or nil if none were.
+func buildHiddenBugsBlocks(hidden int) []map[string]interface{} {
+	if hidden == 0 {
+		return nil
+	}
+	noun := "bugs"
+	if hidden == 1 {
+		noun = "bug"
+	}
+
+	return []map[string]interface{}{
+		{
+			"type": "context",
+			"elements": []map[string]string{
+				{
+					"type": "mrkdwn",
+					"text": fmt.Sprintf("🙈 %d %s below *%s* severity hidden from this report", hidden, noun, escapeSlackMrkdwn(activeSeverityFilter.min)),
+				},
+			},
+		},
+	}
+}
+
+// sortBySeverity moves issues with a known severity to the front, most severe first.
+// Issues without a (known) severity keep their relative order after them.
+func sortBySeverity(issues []IssueItem, ranks map[string]int) {
+	rank := func(issue IssueItem) int {
+		if r, ok := ranks[strings.ToLower(issue.Severity)]; ok {
+			return r
+		}
+		return len(ranks)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return rank(issues[i]) < rank(issues[j])
+	})
+}
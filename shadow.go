@@ -0,0 +1,87 @@
+// Shadow report
+//
+// Before a formatting change reaches the team channel, both versions can run side
+// by side. With SHADOW_CHANNEL set, each newly posted report is posted a second
+// time to that channel, from the same JIRA fetch, with the settings of
+// SHADOW_FLAGS applied on top of the environment. SHADOW_FLAGS is a JSON object of
+// rendering setting to value:
+//
+//	SHADOW_FLAGS='{"INDENT_STYLE": "quote", "NO_EMOJI": "true"}'
+//
+// Only the rendering settings (INDENT_STYLE, NO_EMOJI, PERSON_LINK, ISSUE_TEMPLATE
+// and COMPACT_CHANNEL_POST, see render-options.go) can be set, and others fail at
+// startup; the query, filters and grouping are shared with the primary report.
+// The settings are passed to the renderer rather than set in the environment, so
+// the shadow copy is safe to render next to -server's handlers. The shadow copy is a single thread even with TEAM_CHANNEL_MAP, never
+// moves to SLACK_FALLBACK_CHANNEL and doesn't touch the run state or manifests.
+// Its failures are logged and never change the run's exit code. Updates (-update,
+// UPDATE_IN_PLACE) and single-person runs have no shadow copy.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// activeShadowRender holds the SHADOW_FLAGS render options, loaded at startup.
+var activeShadowRender renderOptions
+
+// shadowChannel returns SHADOW_CHANNEL, or "" when shadow reports are off.
+func shadowChannel() string {
+	return strings.TrimSpace(os.Getenv("SHADOW_CHANNEL"))
+}
+
+// loadShadowFlags parses SHADOW_FLAGS into render options, parsing an
+// ISSUE_TEMPLATE override. Settings that aren't rendering settings are rejected,
+// since they would have no effect. An unset SHADOW_FLAGS renders like the
+// primary report.
+func loadShadowFlags() (renderOptions, error) {
+	value := strings.TrimSpace(os.Getenv("SHADOW_FLAGS"))
+	if value == "" {
+		return renderOptions{}, nil
+	}
+
+	var flags map[string]string
+	if err := json.Unmarshal([]byte(value), &flags); err != nil {
+		return renderOptions{}, fmt.Errorf("SHADOW_FLAGS is not a JSON object of setting to value: %w", err)
+	}
+	render := renderOptions{settings: flags}
+	for name, value := range flags {
+		if !renderSettings[name] {
+			return renderOptions{}, fmt.Errorf("SHADOW_FLAGS: %s isn't a rendering setting (INDENT_STYLE, NO_EMOJI, PERSON_LINK, ISSUE_TEMPLATE or COMPACT_CHANNEL_POST)", name)
+		}
+		if name == "ISSUE_TEMPLATE" {
+			tmpl, err := parseIssueTemplate(value)
+			if err != nil {
+				return renderOptions{}, fmt.Errorf("SHADOW_FLAGS: %w", err)
+			}
+			render.template = tmpl
+		}
+	}
+	return render, nil
+}
+
+// sendShadowReport posts the report again to SHADOW_CHANNEL with SHADOW_FLAGS applied.
+// The count trend is passed in, as the primary post has already recorded today's
+// total. Failures are only logged.
+func sendShadowReport(mode reportMode, jiraURL, jql, slackBotToken, date, trend string, personGroups []PersonStatusGroup) {
+	channel := shadowChannel()
+	render := activeShadowRender
+
+	logf("👥 Sending shadow report to %s at %s...\n", channel, time.Now().Format("15:04:05"))
+	stats := newRunStats()
+	// No fallback channel: the shadow copy never moves
+	thread := &reportThread{botToken: slackBotToken, channel: channel, stats: stats, render: render}
+	if err := thread.start(buildHeaderBlocks(mode, date, trend, personGroups, render)); err != nil {
+		logf("⚠️  Shadow report not sent: %v\n", err)
+		return
+	}
+	if _, err := sendDailyReportThreaded(thread, jiraURL, jql, personGroups, mode, stats, render); err != nil {
+		logf("⚠️  Shadow report incomplete: %v\n", err)
+		return
+	}
+	logf("✅ Sent shadow report to %s\n", channel)
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoadShadowFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   string
+		wantErr string
+	}{
+		{name: "unset", flags: ""},
+		{name: "rendering settings", flags: `{"INDENT_STYLE": "quote", "NO_EMOJI": "true", "ISSUE_TEMPLATE": "{{.Key}}"}`},
+		{name: "not a rendering setting", flags: `{"JIRA_JQL": "project = X"}`, wantErr: "isn't a rendering setting"},
+		{name: "invalid template", flags: `{"ISSUE_TEMPLATE": "{{.Nope}}"}`, wantErr: "can't be rendered"},
+		{name: "not an object", flags: `["INDENT_STYLE"]`, wantErr: "not a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SHADOW_FLAGS", tt.flags)
+			_, err := loadShadowFlags()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("loadShadowFlags: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("loadShadowFlags error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShadowRenderLeavesEnvironment(t *testing.T) {
+	t.Setenv("SHADOW_FLAGS", `{"INDENT_STYLE": "quote", "PERSON_LINK": "none", "ISSUE_TEMPLATE": "{{.Indent}}shadow {{.Key}}"}`)
+	t.Setenv("INDENT_STYLE", "bullets")
+	render, err := loadShadowFlags()
+	if err != nil {
+		t.Fatalf("loadShadowFlags: %v", err)
+	}
+	primaryTemplate := activeIssueTemplate
+
+	group := PersonStatusGroup{
+		Person:       "Jane Doe",
+		StatusGroups: map[string][]IssueItem{"POST": {{Key: "MTV-1", Summary: "Fix it", Status: "POST"}}},
+		TotalIssues:  1,
+	}
+	thread := &reportThread{channel: "C1", threadTS: "1.0", render: render}
+	shadow := blocksText(buildPersonBlocks(thread, "https://jira.example.com", group, modeQA, 0, render))
+	primary := blocksText(buildPersonBlocks(thread, "https://jira.example.com", group, modeQA, 0, envRender))
+
+	if !strings.Contains(shadow, "> shadow MTV-1") {
+		t.Errorf("shadow section doesn't use its settings:\n%s", shadow)
+	}
+	if strings.Contains(shadow, "jira.example.com/issues") {
+		t.Errorf("shadow section links the person despite PERSON_LINK=none:\n%s", shadow)
+	}
+	if strings.Contains(primary, "shadow") || !strings.Contains(primary, "https://jira.example.com/issues") {
+		t.Errorf("primary section picked up the shadow settings:\n%s", primary)
+	}
+	if got := os.Getenv("INDENT_STYLE"); got != "bullets" {
+		t.Errorf("INDENT_STYLE = %q after rendering, want bullets", got)
+	}
+	if activeIssueTemplate != primaryTemplate {
+		t.Error("rendering the shadow copy replaced activeIssueTemplate")
+	}
+}
+
+// blocksText joins the mrkdwn text of section blocks.
+func blocksText(blocks []map[string]interface{}) string {
+	var texts []string
+	for _, block := range blocks {
+		if text, ok := block["text"].(map[string]string); ok {
+			texts = append(texts, text["text"])
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,132 @@
+// Fallback channel
+//
+// When the report channel is archived, renamed or the bot was removed from it,
+// posting fails with is_archived, channel_not_found or not_in_channel. With
+// SLACK_FALLBACK_CHANNEL set (e.g. an ops channel) the report then continues
+// there, starting with a header explaining what happened, instead of aborting.
+// The run still ends with errDegradedDelivery so the exit code signals it.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// SlackAPIError is an error reported by the Slack Web API ("ok": false).
+type SlackAPIError struct {
+	Code string
+}
+
+func (e *SlackAPIError) Error() string {
+	return "Slack API error: " + e.Code
+}
+
+// channelUnavailableErrors are the Slack errors meaning the channel can't receive the report.
+var channelUnavailableErrors = map[string]bool{
+	"is_archived":       true,
+	"channel_not_found": true,
+	"not_in_channel":    true,
+}
+
+// errDegradedDelivery reports that the report was (partly) posted to the fallback channel.
+var errDegradedDelivery = errors.New("report channel unavailable, delivered to SLACK_FALLBACK_CHANNEL")
+
+// reportThread posts the report thread, moving it to the fallback channel if the
+// report channel becomes unavailable.
+type reportThread struct {
+	botToken string
+	channel  string
+	threadTS string
+	header   []map[string]interface{} // Repeated in the fallback channel
+	stats    *runStats
+	render   renderOptions // Applied when sending (NO_EMOJI)
+	fallback string        // SLACK_FALLBACK_CHANNEL, or "" for none
+	degraded bool
+}
+
+// newReportThread creates a thread poster for the channel; start must be called first.
+func newReportThread(botToken, channel string, stats *runStats) *reportThread {
+	return &reportThread{botToken: botToken, channel: channel, stats: stats, fallback: os.Getenv("SLACK_FALLBACK_CHANNEL")}
+}
+
+// start posts the header message that starts the thread.
+func (t *reportThread) start(header []map[string]interface{}) error {
+	t.header = header
+	parts, err := t.send("", header)
+	if err != nil {
+		// The fallback message already carries the header
+		return t.fallBack(err)
+	}
+	t.threadTS = parts[0]
+	return nil
+}
+
+// reply posts a message in the thread and returns the ts of each message it was
+// posted as: one, or more if it had to be split. After a fallback the message is
+// in t.channel, no longer the original channel.
+func (t *reportThread) reply(blocks []map[string]interface{}) ([]string, error) {
+	parts, err := t.send(t.threadTS, blocks)
+	if err == nil {
+		return parts, nil
+	}
+	if fallbackErr := t.fallBack(err); fallbackErr != nil {
+		return nil, fallbackErr
+	}
+	return t.send(t.threadTS, blocks)
+}
+
+// send posts one message and records its latency.
+func (t *reportThread) send(threadTS string, blocks []map[string]interface{}) ([]string, error) {
+	if dryRun {
+		ts, err := printDryRunMessage(t.channel, threadTS, blocks)
+		if err != nil {
+			return nil, err
+		}
+		return []string{ts}, nil
+	}
+	start := time.Now()
+	parts, err := sendMessageParts(t.botToken, t.channel, threadTS, blocks, t.render)
+	t.stats.recordSlack(time.Since(start))
+	return parts, err
+}
+
+// fallBack moves the thread to the fallback channel if cause means the report
+// channel is unavailable. Returns cause if there's no (further) fallback, or the
+// error of posting the explanatory header there.
+func (t *reportThread) fallBack(cause error) error {
+	var apiErr *SlackAPIError
+	if !errors.As(cause, &apiErr) || !channelUnavailableErrors[apiErr.Code] {
+		return cause
+	}
+
+	fallback := t.fallback
+	if fallback == "" || t.degraded || fallback == t.channel {
+		return cause
+	}
+
+	logf("   ⚠️  Channel %s unavailable (%s), continuing in fallback channel %s\n", t.channel, apiErr.Code, fallback)
+	notice := "⚠️ *The daily report couldn't be posted to <#" + t.channel + ">* (`" + apiErr.Code + "`), so it continues here. Please check the channel configuration."
+	if t.threadTS != "" {
+		notice += " Earlier parts were posted in the original thread."
+	}
+
+	original := t.channel
+	t.channel = fallback
+	t.threadTS = ""
+	blocks := []map[string]interface{}{
+		{
+			"type": "section",
+			"text": map[string]string{"type": "mrkdwn", "text": notice},
+		},
+	}
+	parts, err := t.send("", append(blocks, t.header...))
+	if err != nil {
+		return fmt.Errorf("failed to post to fallback channel after %s failed (%v): %w", original, cause, err)
+	}
+
+	t.threadTS = parts[0]
+	t.degraded = true
+	return nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// channelPost is a chat.postMessage seen by unavailableChannelStub.
+type channelPost struct {
+	Channel  string
+	ThreadTS string
+}
+
+// unavailableChannelStub points the Slack API at a server that rejects posts to
+// the channel with code once failAfter posts went through, and accepts all others.
+func unavailableChannelStub(t *testing.T, channel, code string, failAfter int) *[]channelPost {
+	t.Helper()
+	var posts []channelPost
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Channel  string `json:"channel"`
+			ThreadTS string `json:"thread_ts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("decoding payload: %v", err)
+		}
+		posts = append(posts, channelPost{Channel: payload.Channel, ThreadTS: payload.ThreadTS})
+		if payload.Channel == channel && len(posts) > failAfter {
+			json.NewEncoder(w).Encode(SlackMessageResponse{Error: code})
+			return
+		}
+		json.NewEncoder(w).Encode(SlackMessageResponse{OK: true, TS: fmt.Sprint(len(posts))})
+	}))
+	t.Cleanup(server.Close)
+
+	previous := slackAPIBase
+	slackAPIBase = server.URL
+	t.Cleanup(func() { slackAPIBase = previous })
+	return &posts
+}
+
+func TestReportThreadFallsBack(t *testing.T) {
+	t.Setenv("SLACK_FALLBACK_CHANNEL", "C-ops")
+	header := []map[string]interface{}{sectionBlock("header")}
+
+	for _, code := range []string{"is_archived", "channel_not_found", "not_in_channel"} {
+		t.Run(code, func(t *testing.T) {
+			posts := unavailableChannelStub(t, "C-report", code, 0)
+			thread := newReportThread("xoxb-test", "C-report", nil)
+			if err := thread.start(header); err != nil {
+				t.Fatalf("start: %v", err)
+			}
+			if _, err := thread.reply([]map[string]interface{}{sectionBlock("reply")}); err != nil {
+				t.Fatalf("reply: %v", err)
+			}
+
+			// The header fails, the notice starts a thread in the fallback channel, the reply follows it
+			want := "[{C-report } {C-ops } {C-ops 2}]"
+			if got := fmt.Sprint(*posts); got != want {
+				t.Errorf("posts = %s, want %s", got, want)
+			}
+			if !thread.degraded || thread.channel != "C-ops" {
+				t.Errorf("thread in %s, degraded %v; want C-ops, degraded", thread.channel, thread.degraded)
+			}
+		})
+	}
+}
+
+func TestReportThreadFallsBackMidThread(t *testing.T) {
+	t.Setenv("SLACK_FALLBACK_CHANNEL", "C-ops")
+	posts := unavailableChannelStub(t, "C-report", "is_archived", 1)
+	thread := newReportThread("xoxb-test", "C-report", nil)
+	if err := thread.start([]map[string]interface{}{sectionBlock("header")}); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	parts, err := thread.reply([]map[string]interface{}{sectionBlock("reply")})
+	if err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+
+	// The failed reply is posted again under the fallback channel's notice
+	want := "[{C-report } {C-report 1} {C-ops } {C-ops 3}]"
+	if got := fmt.Sprint(*posts); got != want {
+		t.Errorf("posts = %s, want %s", got, want)
+	}
+	if parts[0] != "4" || !thread.degraded {
+		t.Errorf("reply ts %v, degraded %v; want 4 in the degraded thread", parts, thread.degraded)
+	}
+}
+
+func TestReportThreadDoesNotFallBack(t *testing.T) {
+	tests := []struct {
+		name     string
+		fallback string
+		code     string
+	}{
+		{name: "other Slack error", fallback: "C-ops", code: "ratelimited"},
+		{name: "no fallback channel", fallback: "", code: "is_archived"},
+		{name: "fallback is the report channel", fallback: "C-report", code: "is_archived"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SLACK_FALLBACK_CHANNEL", tt.fallback)
+			posts := unavailableChannelStub(t, "C-report", tt.code, 0)
+			thread := newReportThread("xoxb-test", "C-report", nil)
+
+			err := thread.start([]map[string]interface{}{sectionBlock("header")})
+			if !isSlackError(err, tt.code) {
+				t.Errorf("err = %v, want %s", err, tt.code)
+			}
+			if len(*posts) != 1 || thread.degraded {
+				t.Errorf("posts = %v, degraded %v; want one failed post", *posts, thread.degraded)
+			}
+		})
+	}
+}
+
+func TestReportExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "delivered", err: nil, want: 0},
+		{name: "fallback channel", err: fmt.Errorf("team report: %w", errDegradedDelivery), want: 2},
+		{name: "some team channels", err: errPartialDelivery, want: 2},
+		{name: "unknown person", err: &personNotFoundError{name: "nobody"}, want: exitPersonNotFound},
+		{name: "failed", err: errors.New("failed to send initial message"), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reportExitCode(tt.err); got != tt.want {
+				t.Errorf("reportExitCode = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Slack Interactivity
+//
+// Handles button clicks on messages sent by the slash command server. Slack posts
+// these to /slack/interactions as a form with a JSON "payload" field.
+//
+// Supported actions:
+//
+//	share_to_channel - Re-runs the query behind an ephemeral /issues result and posts
+//	                   it publicly as a thread in the channel the command came from
+//	remind_me        - Schedules a DM with a person's section of the daily report
+//	                   (see reminders.go)
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// shareActionID identifies the "Share to channel" button on ephemeral results
+const shareActionID = "share_to_channel"
+
+// slackActionValueLimit is the maximum length Slack accepts for a button value
+const slackActionValueLimit = 2000
+
+// SlackInteractionPayload represents the parts of a block_actions payload we use
+type SlackInteractionPayload struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		TS     string                   `json:"ts"`
+		Blocks []map[string]interface{} `json:"blocks"`
+	} `json:"message"`
+	ResponseURL string `json:"response_url"`
+	Actions     []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// shareAction is the state encoded into the "Share to channel" button so the
+// click can reproduce the exact query without the original request
+type shareAction struct {
+	Username     string `json:"u"`
+	IncludeAll   bool   `json:"a,omitempty"`
+	StatusFilter string `json:"s,omitempty"`
+	SprintOnly   bool   `json:"p,omitempty"`
+	Sort         string `json:"o,omitempty"`
+}
+
+// encodeActionValue serializes action state into an opaque button value.
+func encodeActionValue(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal action value: %w", err)
+	}
+
+	value := base64.RawURLEncoding.EncodeToString(data)
+	if len(value) > slackActionValueLimit {
+		return "", fmt.Errorf("action value is %d chars, Slack allows %d", len(value), slackActionValueLimit)
+	}
+	return value, nil
+}
+
+// decodeActionValue parses a button value produced by encodeActionValue into v.
+func decodeActionValue(value string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return fmt.Errorf("failed to decode action value: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to unmarshal action value: %w", err)
+	}
+	return nil
+}
+
+// buildShareButtonBlock creates the actions block with the "Share to channel" button.
+// Returns nil if the state can't be encoded, in which case the button is omitted.
+func buildShareButtonBlock(username string, includeAll, sprintOnly bool, statusFilter, sortKey string) map[string]interface{} {
+	value, err := encodeActionValue(shareAction{
+		Username:     username,
+		IncludeAll:   includeAll,
+		StatusFilter: statusFilter,
+		SprintOnly:   sprintOnly,
+		Sort:         sortKey,
+	})
+	if err != nil {
+		logf("   ⚠️  Omitting share button: %v\n", err)
+		return nil
+	}
+
+	return map[string]interface{}{
+		"type": "actions",
+		"elements": []map[string]interface{}{
+			{
+				"type":      "button",
+				"action_id": shareActionID,
+				"text": map[string]string{
+					"type": "plain_text",
+					"text": "📣 Share to channel",
+				},
+				"value": value,
+			},
+		},
+	}
+}
+
+// handleInteraction processes button clicks from Slack messages
+func handleInteraction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logf("⚠️  Failed to parse form on %s: %v\n", r.URL.Path, err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+
+	var payload SlackInteractionPayload
+	if err := json.Unmarshal([]byte(r.FormValue("payload")), &payload); err != nil {
+		http.Error(w, "Invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	// Acknowledge immediately (required within 3 seconds), work happens asynchronously
+	w.WriteHeader(http.StatusOK)
+
+	if payload.Type != "block_actions" {
+		return
+	}
+
+	for _, action := range payload.Actions {
+		switch action.ActionID {
+		case shareActionID:
+			logf("📨 Share requested by @%s in %s\n", payload.User.Username, payload.Channel.ID)
+			go processShareAction(payload, action.Value)
+		case remindActionID:
+			logf("📨 Reminder requested by @%s\n", payload.User.Username)
+			go processRemindAction(payload, action.Value)
+		default:
+			logf("⚠️  Ignoring unknown action %q\n", action.ActionID)
+		}
+	}
+}
+
+// processShareAction refetches the issues behind an ephemeral result and posts them
+// publicly as a thread in the originating channel, then replaces the ephemeral message
+// with a confirmation.
+func processShareAction(payload SlackInteractionPayload, value string) {
+	var share shareAction
+	if err := decodeActionValue(value, &share); err != nil {
+		logf("   ❌ %v\n", err)
+		sendErrorResponse(payload.ResponseURL, "Couldn't read the shared query. Please run the command again.")
+		return
+	}
+
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
+	if jiraURL == "" || jiraToken == "" || slackBotToken == "" {
+		sendErrorResponse(payload.ResponseURL, "Configuration error: JIRA_URL, JIRA_TOKEN or SLACK_BOT_TOKEN not set")
+		return
+	}
+
+	userIssues, _, err := fetchUserIssues(context.Background(), jiraURL, jiraToken, share.Username, share.IncludeAll, share.StatusFilter, share.SprintOnly)
+	if err != nil {
+		logf("   ❌ JIRA fetch error: %v\n", err)
+		sendErrorResponse(payload.ResponseURL, fmt.Sprintf("Failed to fetch JIRA issues: %v", err))
+		return
+	}
+	if len(userIssues) == 0 {
+		sendErrorResponse(payload.ResponseURL, fmt.Sprintf("No issues found for: *%s* anymore, nothing to share.", escapeSlackMrkdwn(share.Username)))
+		return
+	}
+
+	sortUserIssues(userIssues, share.Sort)
+	statusGroups := groupIssuesByStatus(userIssues)
+	err = sendThreadedResponse(slackBotToken, payload.Channel.ID, jiraURL, share.Username, statusGroups, share.IncludeAll, share.StatusFilter, share.SprintOnly)
+	if err != nil {
+		logf("   ❌ Failed to share: %v\n", err)
+		sendErrorResponse(payload.ResponseURL, fmt.Sprintf("Failed to share to this channel: %v\n\nMake sure the bot is a member of the channel.", err))
+		return
+	}
+
+	err = sendSlackResponse(payload.ResponseURL, SlackSlashResponse{
+		ReplaceOriginal: true,
+		Text:            fmt.Sprintf("✅ Shared %d issue(s) for *%s* to this channel", len(userIssues), escapeSlackMrkdwn(share.Username)),
+	})
+	if err != nil {
+		logf("   ❌ ERROR replacing ephemeral message: %v\n", err)
+		return
+	}
+
+	logf("✅ Shared %d issues for %s to %s\n", len(userIssues), share.Username, payload.Channel.ID)
+}
@@ -0,0 +1,270 @@
+// Slack user lookup by email
+//
+// SLACK_USER_IDS maps people to Slack users by hand. With
+// SLACK_LOOKUP_BY_EMAIL=true, people without an entry are also found by the email
+// address on their JIRA user (users.lookupByEmail, which needs the
+// users:read.email scope). On a 30-person report that is a burst of calls Slack
+// may throttle, so the lookups:
+//   - start while the JIRA fetch is still running, as each page's people arrive,
+//     and run one at a time, SLACK_LOOKUP_SPACING_MS (default 700) apart
+//   - wait out a 429 for its Retry-After and try again, up to 3 times
+//   - are cached across runs in slack-users.json in STATE_DIR, keyed by email,
+//     for a week (people not found in Slack too)
+//
+// JIRA Cloud hides email addresses unless the token may see them; people without
+// one simply get no Slack user.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// slackUsersFile caches users.lookupByEmail results inside STATE_DIR
+	slackUsersFile = "slack-users.json"
+
+	// slackUserCacheTTL is how long a cached lookup is trusted
+	slackUserCacheTTL = 7 * 24 * time.Hour
+
+	// defaultSlackLookupSpacing separates consecutive lookups unless SLACK_LOOKUP_SPACING_MS is set
+	defaultSlackLookupSpacing = 700 * time.Millisecond
+
+	// slackLookupAttempts is how often a throttled lookup is tried
+	slackLookupAttempts = 3
+)
+
+// activeSlackUsers resolves people to Slack users during a report run; nil when
+// SLACK_LOOKUP_BY_EMAIL is off.
+var activeSlackUsers *slackUserDirectory
+
+// slackUserCacheEntry is one cached lookup. An empty UserID means no Slack user has the email.
+type slackUserCacheEntry struct {
+	UserID     string    `json:"user_id,omitempty"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+// slackUserDirectory looks up people's Slack users by email in the background.
+type slackUserDirectory struct {
+	botToken string
+	spacing  time.Duration
+	queue    chan string
+
+	mu      sync.Mutex
+	emails  map[string]string              // Email, by lowercased person
+	cache   map[string]slackUserCacheEntry // By lowercased email
+	pending map[string]chan struct{}       // Closed once the email's lookup is done
+	dirty   bool
+}
+
+// slackLookupByEmailEnabled reports whether SLACK_LOOKUP_BY_EMAIL is on.
+func slackLookupByEmailEnabled() bool {
+	return envBool("SLACK_LOOKUP_BY_EMAIL", false)
+}
+
+// newSlackUserDirectory loads the lookup cache and starts the lookup worker.
+// A cache that can't be read is logged and starts empty.
+func newSlackUserDirectory(botToken string) *slackUserDirectory {
+	d := &slackUserDirectory{
+		botToken: botToken,
+		spacing:  time.Duration(envInt("SLACK_LOOKUP_SPACING_MS", int(defaultSlackLookupSpacing/time.Millisecond))) * time.Millisecond,
+		queue:    make(chan string, 256),
+		emails:   make(map[string]string),
+		cache:    make(map[string]slackUserCacheEntry),
+		pending:  make(map[string]chan struct{}),
+	}
+
+	data, err := os.ReadFile(filepath.Join(stateDir(), slackUsersFile))
+	if err == nil {
+		err = json.Unmarshal(data, &d.cache)
+	}
+	if err != nil && !os.IsNotExist(err) {
+		logf("⚠️  Failed to read %s, looking up Slack users again: %v\n", slackUsersFile, err)
+		d.cache = make(map[string]slackUserCacheEntry)
+	}
+
+	go d.work()
+	return d
+}
+
+// prefetch records the person's email and queues its lookup unless it is cached
+// or already queued. Safe to call on a nil receiver.
+func (d *slackUserDirectory) prefetch(person, email string) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if d == nil || email == "" {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.emails[strings.ToLower(person)] = email
+	if _, queued := d.pending[email]; queued {
+		return
+	}
+	if entry, ok := d.cache[email]; ok && time.Since(entry.ResolvedAt) < slackUserCacheTTL {
+		return
+	}
+	done := make(chan struct{})
+	d.pending[email] = done
+	select {
+	case d.queue <- email:
+	default:
+		// A full queue only delays the lookup until the person is rendered
+		delete(d.pending, email)
+		close(done)
+	}
+}
+
+// idFor returns the person's Slack user ID, waiting for a queued lookup to finish
+// and looking the email up now if it wasn't queued. Returns "" when the person has
+// no known email or no Slack user. Safe to call on a nil receiver.
+func (d *slackUserDirectory) idFor(person string) string {
+	if d == nil {
+		return ""
+	}
+
+	d.mu.Lock()
+	email := d.emails[strings.ToLower(person)]
+	done := d.pending[email]
+	d.mu.Unlock()
+	if email == "" {
+		return ""
+	}
+	if done != nil {
+		<-done
+	}
+
+	d.mu.Lock()
+	entry, ok := d.cache[email]
+	d.mu.Unlock()
+	if !ok || time.Since(entry.ResolvedAt) >= slackUserCacheTTL {
+		d.resolve(email)
+		d.mu.Lock()
+		entry = d.cache[email]
+		d.mu.Unlock()
+	}
+	return entry.UserID
+}
+
+// work resolves queued emails one at a time, saving the cache whenever the queue runs dry.
+func (d *slackUserDirectory) work() {
+	for email := range d.queue {
+		d.resolve(email)
+
+		d.mu.Lock()
+		if done, ok := d.pending[email]; ok {
+			delete(d.pending, email)
+			close(done)
+		}
+		d.mu.Unlock()
+
+		if len(d.queue) == 0 {
+			d.save()
+		}
+		time.Sleep(d.spacing)
+	}
+}
+
+// resolve looks an email up in Slack and caches the result. Failures other than
+// "not found" aren't cached, so the next run tries again.
+func (d *slackUserDirectory) resolve(email string) {
+	userID, err := lookupSlackUserByEmail(d.botToken, email)
+	if err != nil {
+		logf("   ⚠️  Slack lookup for %s failed: %v\n", redactEmail(email), err)
+		return
+	}
+
+	d.mu.Lock()
+	d.cache[email] = slackUserCacheEntry{UserID: userID, ResolvedAt: time.Now().UTC()}
+	d.dirty = true
+	d.mu.Unlock()
+}
+
+// save writes the cache to STATE_DIR if it changed, logging failures. Safe to
+// call on a nil receiver.
+func (d *slackUserDirectory) save() {
+	if d == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.dirty {
+		return
+	}
+	if err := writeStateFile(slackUsersFile, d.cache); err != nil {
+		logf("⚠️  Failed to save the Slack user cache: %v\n", err)
+		return
+	}
+	d.dirty = false
+}
+
+// lookupSlackUserByEmail calls users.lookupByEmail, retrying after a 429 for as
+// long as Retry-After says. Returns "" without error when no user has the email.
+func lookupSlackUserByEmail(botToken, email string) (string, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest("GET", slackAPIBase+"/users.lookupByEmail?email="+url.QueryEscape(email), nil)
+		if err != nil {
+			return "", fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+botToken)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", fmt.Errorf("failed to call Slack API: %w", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read response: %w", err)
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests {
+			if attempt >= slackLookupAttempts {
+				return "", fmt.Errorf("still rate limited after %d attempts", attempt)
+			}
+			wait, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+			if err != nil || wait < 1 {
+				wait = 1
+			}
+			logf("   ⏳ Slack rate limited users.lookupByEmail, retrying in %ds\n", wait)
+			time.Sleep(time.Duration(wait) * time.Second)
+			continue
+		}
+
+		var result struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+			User  struct {
+				ID string `json:"id"`
+			} `json:"user"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return "", fmt.Errorf("failed to parse response: %w", err)
+		}
+		switch {
+		case result.OK:
+			return result.User.ID, nil
+		case result.Error == "users_not_found":
+			return "", nil
+		}
+		return "", &SlackAPIError{Code: result.Error}
+	}
+}
+
+// redactEmail shortens an email for logs ("jdoe@example.com" → "j…@example.com").
+func redactEmail(email string) string {
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return "…"
+	}
+	return local[:1] + "…@" + domain
+}
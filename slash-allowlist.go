@@ -0,0 +1,68 @@
+// Slash command channel allowlist
+//
+// Ephemeral results are private, but running /issues in a public channel still
+// shows anyone there who works on what. SLASH_ALLOWED_CHANNELS (comma-separated
+// channel IDs) restricts the slash commands to those channels; commands from
+// other channels get a polite refusal naming the allowed ones, and the attempt is
+// logged. DMs are allowed unless SLASH_ALLOW_DMS=false. Empty means unrestricted.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// isDirectMessage reports whether cmd was run in a direct message.
+func isDirectMessage(cmd SlackSlashCommand) bool {
+	return cmd.ChannelName == "directmessage" || strings.HasPrefix(cmd.ChannelID, "D")
+}
+
+// slashChannelAllowed reports whether slash commands may be used where cmd was run.
+func slashChannelAllowed(cmd SlackSlashCommand) bool {
+	allowed := envList("SLASH_ALLOWED_CHANNELS")
+	if len(allowed) == 0 {
+		return true
+	}
+	if isDirectMessage(cmd) {
+		return envBool("SLASH_ALLOW_DMS", true)
+	}
+	for _, channel := range allowed {
+		if channel == cmd.ChannelID {
+			return true
+		}
+	}
+	return false
+}
+
+// slashChannelRefusal explains where the command can be used instead.
+func slashChannelRefusal(cmd SlackSlashCommand) string {
+	var places []string
+	for _, channel := range envList("SLASH_ALLOWED_CHANNELS") {
+		places = append(places, fmt.Sprintf("<#%s>", channel))
+	}
+	if envBool("SLASH_ALLOW_DMS", true) {
+		places = append(places, "a direct message with me")
+	}
+	return fmt.Sprintf("🔒 Sorry, `%s` isn't available here. Please use it in %s.", cmd.Command, strings.Join(places, ", "))
+}
+
+// refuseSlashChannel answers a command from a channel outside the allowlist and
+// logs the attempt. Returns false when the command may proceed.
+func refuseSlashChannel(ctx context.Context, w http.ResponseWriter, cmd SlackSlashCommand) bool {
+	if slashChannelAllowed(cmd) {
+		return false
+	}
+	ctxLogf(ctx, "🚫 Rejected %s from @%s (%s) in %s (%s): channel not in SLASH_ALLOWED_CHANNELS\n",
+		cmd.Command, cmd.UserName, cmd.UserID, cmd.ChannelID, cmd.ChannelName)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(SlackSlashResponse{
+		ResponseType: "ephemeral",
+		Text:         plainText(slashChannelRefusal(cmd)),
+	})
+	return true
+}
@@ -0,0 +1,41 @@
+// Public slash command responses
+//
+// By default /issues answers privately via the command's response_url. With
+// SLASH_PUBLIC_RESPONSES=true the results are instead posted as a thread in the
+// channel the command was invoked in (cmd.ChannelID), so the same server works in
+// any channel without a fixed SLACK_CHANNEL. Commands run from a DM are answered
+// in the user's DM with the bot, opened via conversations.open, since the bot
+// can't post into other people's conversations.
+package main
+
+import "fmt"
+
+// SlackConversationOpenResponse represents the response from Slack's conversations.open API
+type SlackConversationOpenResponse struct {
+	OK      bool `json:"ok"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Error string `json:"error,omitempty"`
+}
+
+// slashResponseChannel returns the channel public results for cmd are posted to.
+func slashResponseChannel(botToken string, cmd SlackSlashCommand) (string, error) {
+	if isDirectMessage(cmd) {
+		return openDirectMessage(botToken, cmd.UserID)
+	}
+	return cmd.ChannelID, nil
+}
+
+// openDirectMessage opens (or reuses) the DM between the bot and a user and returns its channel ID.
+func openDirectMessage(botToken, userID string) (string, error) {
+	var result SlackConversationOpenResponse
+	if err := callSlackAPI(botToken, "conversations.open", map[string]string{"users": userID}, &result); err != nil {
+		return "", err
+	}
+	if !result.OK {
+		return "", fmt.Errorf("Slack API error: %s", result.Error)
+	}
+
+	return result.Channel.ID, nil
+}
@@ -0,0 +1,83 @@
+// Ephemeral slash results via chat.postEphemeral
+//
+// A command's response_url accepts only five messages, which long paginated
+// results and follow-ups run into. Results for commands run in a channel are
+// therefore posted with chat.postEphemeral, which has no such limit. When the bot
+// can't post there (user_not_in_channel, not_in_channel, channel_not_found, e.g.
+// the bot isn't a member of the channel) and for DMs, response_url is used as before.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ephemeralUnavailableErrors are the Slack errors meaning chat.postEphemeral can't
+// reach the user in the channel, so response_url has to be used instead.
+var ephemeralUnavailableErrors = map[string]bool{
+	"user_not_in_channel": true,
+	"not_in_channel":      true,
+	"channel_not_found":   true,
+}
+
+// postEphemeral shows blocks to a single user in a channel. Slack errors are
+// returned as *SlackAPIError.
+func postEphemeral(botToken, channel, user, text string, blocks []map[string]interface{}) error {
+	plainBlockText(blocks, envRender)
+	text = plainText(text)
+	if err := validateBlocks(blocks); err != nil {
+		return fmt.Errorf("invalid message: %w", err)
+	}
+	payload := map[string]interface{}{
+		"channel": channel,
+		"user":    user,
+		"text":    text,
+		"blocks":  blocks,
+	}
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := callSlackAPI(botToken, "chat.postEphemeral", payload, &result); err != nil {
+		return err
+	}
+	if !result.OK {
+		return &SlackAPIError{Code: result.Error}
+	}
+	return nil
+}
+
+// isEphemeralUnavailable reports whether err means the user can't be reached in the channel.
+func isEphemeralUnavailable(err error) bool {
+	var apiErr *SlackAPIError
+	return errors.As(err, &apiErr) && ephemeralUnavailableErrors[apiErr.Code]
+}
+
+// sendEphemeralPages sends the result pages privately to the user who ran cmd,
+// through chat.postEphemeral where possible and the response_url otherwise.
+func sendEphemeralPages(ctx context.Context, botToken string, cmd SlackSlashCommand, text string, pages [][]map[string]interface{}) error {
+	useResponseURL := isDirectMessage(cmd) || cmd.ChannelID == ""
+	for i, blocks := range pages {
+		if !useResponseURL {
+			err := postEphemeral(botToken, cmd.ChannelID, cmd.UserID, text, blocks)
+			if err == nil {
+				continue
+			}
+			if !isEphemeralUnavailable(err) {
+				return fmt.Errorf("page %d/%d: %w", i+1, len(pages), err)
+			}
+			ctxLogf(ctx, "   ⚠️  Can't post ephemerally in %s (%v), using response_url\n", cmd.ChannelID, err)
+			useResponseURL = true
+		}
+
+		err := sendSlackResponse(cmd.ResponseURL, SlackSlashResponse{
+			ResponseType: "ephemeral",
+			Blocks:       blocks,
+		})
+		if err != nil {
+			return fmt.Errorf("page %d/%d: %w", i+1, len(pages), err)
+		}
+	}
+	return nil
+}
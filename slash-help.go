@@ -0,0 +1,54 @@
+// Slash command help
+//
+// /issues help (or --help) answers privately with the supported flags and a few
+// examples, without querying JIRA. The status filters are listed from
+// slashStatusFlags and the sort keys from slashSortKeys, so the help stays in
+// step with what the command accepts.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// isHelpRequest reports whether the command text asks for help.
+func isHelpRequest(text string) bool {
+	switch strings.ToLower(strings.TrimSpace(text)) {
+	case "help", "--help", "-h":
+		return true
+	}
+	return false
+}
+
+// slashHelpText renders the /issues help message.
+func slashHelpText() string {
+	var statusFlags []string
+	for flag := range slashStatusFlags {
+		statusFlags = append(statusFlags, "`"+flag+"`")
+	}
+	sort.Strings(statusFlags)
+
+	lines := []string{
+		"*📖 /issues — show someone's JIRA issues*",
+		"",
+		"*Usage:* `/issues [name] [flags]` — without a name, your own issues (from your Slack profile)",
+		"",
+		"*Flags:*",
+		"• `--all` — include closed issues (this year's, counted per year for earlier ones)",
+		"• `--all-years` — like `--all`, listing closed issues of every year",
+		"• " + strings.Join(statusFlags, ", ") + " — only issues in that status (one at a time)",
+		"• `--sprint` — only issues in the current sprint",
+		fmt.Sprintf("• `--sort=%s` — order issues within each status", strings.Join(slashSortKeys, "|")),
+		fmt.Sprintf("• `--limit=N` — at most N issues per status (%d-%d)", minSlashLimit, maxSlashLimit),
+		"• `--share` — post the results as a thread in this channel instead of privately",
+		"• `help` — this message",
+		"",
+		"*Examples:*",
+		"• `/issues` — your open issues",
+		"• `/issues John Doe --on-qa` — John Doe's ON_QA issues",
+		"• `/issues --all --sort=updated` — all your issues, most recently updated first",
+		"• `/issues --in-progress --limit=5` — up to 5 of your In Progress issues",
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,58 @@
+// Slash command issue limit
+//
+// /issues --limit=N shows at most N issues per status, with an "…and N more"
+// line for the rest, for a quick glance at a long queue. The summary counts
+// still cover every issue. Values outside 1-50 are clamped with a note.
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Bounds of the --limit value.
+const (
+	minSlashLimit = 1
+	maxSlashLimit = 50
+)
+
+// limitFlagPattern matches the --limit flag and its value.
+var limitFlagPattern = regexp.MustCompile(`--limit(?:=(\S*))?`)
+
+// parseLimitFlag extracts --limit from the command text. Returns the limit (0 if
+// the flag is absent), a note if the value was clamped, the text without the flag,
+// and an error for values that aren't numbers.
+func parseLimitFlag(text string) (int, string, string, error) {
+	match := limitFlagPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0, "", text, nil
+	}
+	text = strings.Replace(text, match[0], "", 1)
+
+	limit, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, "", text, fmt.Errorf("Invalid limit %q. Use e.g. `--limit=5`", match[1])
+	}
+
+	clamped := limit
+	if clamped < minSlashLimit {
+		clamped = minSlashLimit
+	} else if clamped > maxSlashLimit {
+		clamped = maxSlashLimit
+	}
+	if clamped != limit {
+		return clamped, fmt.Sprintf("`--limit` must be between %d and %d, showing up to %d per status.", minSlashLimit, maxSlashLimit, clamped), text, nil
+	}
+	return limit, "", text, nil
+}
+
+// limitedIssues returns the first limit issues and how many were left out.
+// A limit of 0 keeps all issues.
+func limitedIssues(issues []IssueItem, limit int) ([]IssueItem, int) {
+	if limit <= 0 || len(issues) <= limit {
+		return issues, 0
+	}
+	return issues[:limit], len(issues) - limit
+}
@@ -15,23 +15,38 @@
 //	/issues --verified          - Shows only Verified status issues
 //	/issues John Doe --modified - Shows John Doe's Modified issues
 //	/issues --all John Doe      - Order doesn't matter
+//	/issues --sprint            - Limits the results to the active sprint (see sprint.go)
+//	/issues --sort=updated      - Orders each status by updated, key or priority (see slash-sort.go)
 //
-// Results are shown as ephemeral (private) messages organized by status.
+// Results are shown as ephemeral (private) messages organized by status, or posted
+// to the invoking channel with SLASH_PUBLIC_RESPONSES=true (see slash-channel.go).
 //
-// The server fetches fresh JIRA data for each request.
+// The server fetches fresh JIRA data for each request. When your name can't be
+// read from Slack, /issues searches for your Slack username instead and says so.
 package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 )
 
+const (
+	// defaultSlackUserInfoRetries is how often a failed users.info call is retried
+	// unless SLACK_USER_INFO_RETRIES is set
+	defaultSlackUserInfoRetries = 2
+
+	// slackUserInfoBackoff is the wait before retrying users.info without a Retry-After
+	slackUserInfoBackoff = 500 * time.Millisecond
+)
+
 // SlackSlashCommand represents the payload Slack sends to slash command endpoints
 type SlackSlashCommand struct {
 	Token       string `json:"token"`
@@ -48,9 +63,10 @@ type SlackSlashCommand struct {
 
 // SlackSlashResponse represents the response sent back to Slack
 type SlackSlashResponse struct {
-	ResponseType string                   `json:"response_type"` // "ephemeral" or "in_channel"
-	Text         string                   `json:"text,omitempty"`
-	Blocks       []map[string]interface{} `json:"blocks,omitempty"`
+	ResponseType    string                   `json:"response_type,omitempty"` // "ephemeral" or "in_channel"
+	Text            string                   `json:"text,omitempty"`
+	Blocks          []map[string]interface{} `json:"blocks,omitempty"`
+	ReplaceOriginal bool                     `json:"replace_original,omitempty"` // Replace the message an interaction came from
 }
 
 // SlackUserInfoResponse represents the response from Slack's users.info API
@@ -60,6 +76,8 @@ type SlackUserInfoResponse struct {
 		ID       string `json:"id"`
 		Name     string `json:"name"`
 		RealName string `json:"real_name"`
+		TZ       string `json:"tz"`
+		TZOffset int    `json:"tz_offset"` // Seconds east of UTC
 		Profile  struct {
 			DisplayName string `json:"display_name"`
 			RealName    string `json:"real_name"`
@@ -78,19 +96,32 @@ func startSlashCommandServer() {
 
 	slackSigningSecret := os.Getenv("SLACK_SIGNING_SECRET")
 	if slackSigningSecret == "" {
-		fmt.Println("⚠️  Warning: SLACK_SIGNING_SECRET not set. Request verification disabled.")
-		fmt.Println("   For production, set this to verify requests are from Slack.")
+		logln("⚠️  Warning: SLACK_SIGNING_SECRET not set. Request verification disabled.")
+		logln("   For production, set this to verify requests are from Slack.")
 	}
 
-	http.HandleFunc("/slack/issues", handleMyIssuesCommand)
+	http.HandleFunc("/slack/issues", limitFormRequest(verifySlackRequest(slackSigningSecret, handleMyIssuesCommand)))
+	http.HandleFunc("/slack/team-issues", limitFormRequest(verifySlackRequest(slackSigningSecret, handleTeamIssuesCommand)))
+	http.HandleFunc("/slack/interactions", limitFormRequest(verifySlackRequest(slackSigningSecret, handleInteraction)))
+	http.HandleFunc("/slack/events", limitJSONRequest(verifySlackRequest(slackSigningSecret, handleSlackEvents)))
+	http.HandleFunc("/report/run", requireTriggerAuth(os.Getenv("REPORT_TRIGGER_TOKEN"), slackSigningSecret, handleReportRun))
+	http.HandleFunc("/api/report", requireTriggerAuth(os.Getenv("REPORT_API_TOKEN"), "", handleAPIReport))
+	http.HandleFunc("/api/report/manifest", requireTriggerAuth(os.Getenv("REPORT_API_TOKEN"), "", handleReportManifest))
 	http.HandleFunc("/health", handleHealthCheck)
 
-	fmt.Printf("🚀 Slash command server starting on port %s...\n", port)
-	fmt.Printf("📍 Endpoint: http://localhost:%s/slack/issues\n", port)
-	fmt.Println("✅ Ready to receive Slack commands!")
-
-	if err := http.ListenAndServe(":"+port, nil); err != nil {
-		fmt.Printf("❌ Server error: %v\n", err)
+	logf("🚀 Slash command server starting on port %s...\n", port)
+	logf("📍 Endpoint: http://localhost:%s/slack/issues\n", port)
+	logf("📍 Team issues: http://localhost:%s/slack/team-issues\n", port)
+	logf("📍 Interactions: http://localhost:%s/slack/interactions\n", port)
+	logf("📍 Events: http://localhost:%s/slack/events\n", port)
+	logf("📍 Report trigger: http://localhost:%s/report/run\n", port)
+	logf("📍 Report API: http://localhost:%s/api/report\n", port)
+	logf("📍 Report manifest: http://localhost:%s/api/report/manifest\n", port)
+	logln("✅ Ready to receive Slack commands!")
+
+	server := newHTTPServer(":"+port, http.DefaultServeMux)
+	if err := server.ListenAndServe(); err != nil {
+		logf("❌ Server error: %v\n", err)
 		os.Exit(1)
 	}
 }
@@ -110,11 +141,34 @@ func handleMyIssuesCommand(w http.ResponseWriter, r *http.Request) {
 
 	// Parse the form data from Slack
 	if err := r.ParseForm(); err != nil {
+		logf("⚠️  Failed to parse form on %s: %v\n", r.URL.Path, err)
 		http.Error(w, "Failed to parse form", http.StatusBadRequest)
 		return
 	}
 
-	cmd := SlackSlashCommand{
+	cmd := slashCommandFromForm(r)
+
+	ctx := withRequestID(context.Background(), newRequestID())
+	ctxLogf(ctx, "📨 Received command from @%s: %s %s\n", cmd.UserName, cmd.Command, cmd.Text)
+	if refuseSlashChannel(ctx, w, cmd) {
+		return
+	}
+
+	// Send immediate acknowledgment to Slack (required within 3 seconds)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(SlackSlashResponse{
+		ResponseType: "ephemeral",
+		Text:         "🔍 Fetching your JIRA issues...",
+	})
+
+	// Process the request asynchronously
+	go processSlashCommand(ctx, cmd)
+}
+
+// slashCommandFromForm reads a slash command from the parsed form of its request.
+func slashCommandFromForm(r *http.Request) SlackSlashCommand {
+	return SlackSlashCommand{
 		Token:       r.FormValue("token"),
 		TeamID:      r.FormValue("team_id"),
 		TeamDomain:  r.FormValue("team_domain"),
@@ -126,80 +180,111 @@ func handleMyIssuesCommand(w http.ResponseWriter, r *http.Request) {
 		Text:        r.FormValue("text"),
 		ResponseURL: r.FormValue("response_url"),
 	}
+}
 
-	fmt.Printf("📨 Received command from @%s: %s %s\n", cmd.UserName, cmd.Command, cmd.Text)
-
-	// Send immediate acknowledgment to Slack (required within 3 seconds)
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(SlackSlashResponse{
-		ResponseType: "ephemeral",
-		Text:         "🔍 Fetching your JIRA issues...",
-	})
+// slashStatusFlags maps the status filter flags of /issues to JIRA statuses.
+// Note: Status names must match JIRA's exact status values (case-sensitive!)
+var slashStatusFlags = map[string]string{
+	"--modified":        "MODIFIED",        // Uppercase in JIRA
+	"--closed":          "Closed",          // Title case
+	"--new":             "New",             // Title case
+	"--open":            "Open",            // Title case
+	"--in-progress":     "In Progress",     // Title case with space
+	"--on-qa":           "ON_QA",           // Uppercase
+	"--post":            "POST",            // Uppercase
+	"--verified":        "Verified",        // Title case
+	"--done":            "Done",            // Title case
+	"--archived":        "Archived",        // Title case
+	"--assigned":        "ASSIGNED",        // Uppercase
+	"--release-pending": "Release Pending", // Title case with space
+}
 
-	// Process the request asynchronously
-	go processSlashCommand(cmd)
+// takeSlashFlag reports whether flag is one of the words of the command text and
+// returns the text without it. Only whole words count, so a name that happens to
+// contain a flag (e.g. "jean--allard") is left intact.
+func takeSlashFlag(text, flag string) (bool, string) {
+	found := false
+	var words []string
+	for _, word := range strings.Fields(text) {
+		if word == flag {
+			found = true
+			continue
+		}
+		words = append(words, word)
+	}
+	return found, strings.Join(words, " ")
 }
 
-// processSlashCommand fetches JIRA data and sends the filtered response
-func processSlashCommand(cmd SlackSlashCommand) {
+// processSlashCommand fetches JIRA data and sends the filtered response.
+// Log lines and error responses carry the request ID of ctx.
+func processSlashCommand(ctx context.Context, cmd SlackSlashCommand) {
 	jiraURL := os.Getenv("JIRA_URL")
 	jiraToken := os.Getenv("JIRA_TOKEN")
 	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
 
 	if jiraURL == "" || jiraToken == "" {
-		sendErrorResponse(cmd.ResponseURL, "Configuration error: JIRA_URL or JIRA_TOKEN not set")
+		sendRequestError(ctx, cmd.ResponseURL, "Configuration error: JIRA_URL or JIRA_TOKEN not set")
 		return
 	}
 
 	if slackBotToken == "" {
-		sendErrorResponse(cmd.ResponseURL, "Configuration error: SLACK_BOT_TOKEN not set")
+		sendRequestError(ctx, cmd.ResponseURL, "Configuration error: SLACK_BOT_TOKEN not set")
 		return
 	}
 
 	// Parse the command text for flags and username
 	text := strings.TrimSpace(cmd.Text)
-	includeAll := strings.Contains(text, "--all")
+	if isHelpRequest(text) {
+		if err := sendSlackResponse(cmd.ResponseURL, SlackSlashResponse{ResponseType: "ephemeral", Text: slashHelpText()}); err != nil {
+			ctxLogf(ctx, "   ❌ ERROR sending help: %v\n", err)
+		}
+		return
+	}
+	sortKey, text, err := parseSortFlag(text)
+	if err != nil {
+		sendRequestError(ctx, cmd.ResponseURL, err.Error())
+		return
+	}
+	limit, limitNote, text, err := parseLimitFlag(text)
+	if err != nil {
+		sendRequestError(ctx, cmd.ResponseURL, err.Error())
+		return
+	}
+	allYears, text := parseAllYearsFlag(text)
+	sprintOnly, text := parseSprintFlag(text)
+	share, text := takeSlashFlag(text, "--share")
+	includeAll, text := takeSlashFlag(text, "--all")
 
 	// Check for status-specific flags
-	// Note: Status names must match JIRA's exact status values (case-sensitive!)
 	statusFilter := ""
-	statusFlags := map[string]string{
-		"--modified":        "MODIFIED",        // Uppercase in JIRA
-		"--closed":          "Closed",          // Title case
-		"--new":             "New",             // Title case
-		"--open":            "Open",            // Title case
-		"--in-progress":     "In Progress",     // Title case with space
-		"--on-qa":           "ON_QA",           // Uppercase
-		"--post":            "POST",            // Uppercase
-		"--verified":        "Verified",        // Title case
-		"--done":            "Done",            // Title case
-		"--archived":        "Archived",        // Title case
-		"--assigned":        "ASSIGNED",        // Uppercase
-		"--release-pending": "Release Pending", // Title case with space
-	}
-
-	for flag, status := range statusFlags {
-		if strings.Contains(text, flag) {
-			statusFilter = status
-			text = strings.ReplaceAll(text, flag, "")
+	for flag, status := range slashStatusFlags {
+		if found, rest := takeSlashFlag(text, flag); found {
+			statusFilter = canonicalStatus(status)
+			text = rest
 			break // Only one status filter at a time
 		}
 	}
 
-	// Remove --all from text to get username
-	username := strings.TrimSpace(strings.ReplaceAll(text, "--all", ""))
+	// What's left is the username
+	username := text
 
 	// If no username provided, fetch the user's real name from Slack
+	degradedNote := ""
 	if username == "" {
 		realName, err := getSlackUserRealName(slackBotToken, cmd.UserID)
-		if err != nil {
-			sendErrorResponse(cmd.ResponseURL, "Failed to auto-detect your name.\n\nPlease specify a name: `/issues John Doe`")
+		switch {
+		case err == nil:
+			username = realName
+			ctxLogf(ctx, "   Auto-detected user: %s (Slack: @%s, ID: %s)\n", username, cmd.UserName, cmd.UserID)
+		case cmd.UserName != "":
+			// Searching by the Slack username beats failing the command outright
+			username = cmd.UserName
+			ctxLogf(ctx, "   ⚠️  Auto-detection failed (%v), searching for Slack username @%s\n", err, username)
+			degradedNote = fmt.Sprintf("⚠️ Couldn't read your Slack profile, so your Slack username %s was searched instead. If that's wrong, specify a name: `/issues John Doe`", username)
+		default:
+			sendRequestError(ctx, cmd.ResponseURL, "Failed to auto-detect your name.\n\nPlease specify a name: `/issues John Doe`")
 			return
 		}
-
-		username = realName
-		fmt.Printf("   Auto-detected user: %s (Slack: @%s, ID: %s)\n", username, cmd.UserName, cmd.UserID)
 	}
 
 	if statusFilter != "" {
@@ -207,60 +292,124 @@ func processSlashCommand(cmd SlackSlashCommand) {
 		if statusFilter == "MODIFIED" {
 			displayStatus = "Modified"
 		}
-		fmt.Printf("   Fetching %s issues for %s...\n", displayStatus, username)
+		ctxLogf(ctx, "   Fetching %s issues for %s...\n", displayStatus, username)
 	} else if includeAll {
-		fmt.Printf("   Fetching ALL issues (including closed) for %s...\n", username)
+		ctxLogf(ctx, "   Fetching ALL issues (including closed) for %s...\n", username)
 	} else {
-		fmt.Printf("   Fetching open issues for %s...\n", username)
+		ctxLogf(ctx, "   Fetching open issues for %s...\n", username)
+	}
+	if sprintOnly {
+		ctxLogf(ctx, "   Limited to the current sprint\n")
 	}
 
-	// Build JQL based on flags
-	jql := buildJQLQueryWithStatus(username, includeAll, statusFilter)
-	fmt.Printf("   JQL: %s\n", jql)
-	issues, err := fetchJiraIssues(jiraURL, jiraToken, jql)
+	userIssues, user, err := fetchUserIssues(ctx, jiraURL, jiraToken, username, includeAll, statusFilter, sprintOnly)
 	if err != nil {
-		fmt.Printf("   ❌ JIRA fetch error: %v\n", err)
-		sendErrorResponse(cmd.ResponseURL, fmt.Sprintf("Failed to fetch JIRA issues: %v", err))
+		ctxLogf(ctx, "   ❌ JIRA fetch error: %v\n", err)
+		sendRequestError(ctx, cmd.ResponseURL, fmt.Sprintf("Failed to fetch JIRA issues: %v", err))
 		return
 	}
-	fmt.Printf("   ✓ Fetched JIRA responses\n")
-
-	// Filter issues for the specified user
-	// For slash commands, show ALL user issues (skipFilters=true)
-	userIssues := filterIssuesByUser(issues, username, true)
-	fmt.Printf("   ✓ Found %d issues for %s\n", len(userIssues), username)
 
+	// Status filter is already applied in JQL; tell an unknown name from an empty queue
 	if len(userIssues) == 0 {
-		sendErrorResponse(cmd.ResponseURL, fmt.Sprintf("No issues found for: *%s*\n\nMake sure the name matches exactly as it appears in JIRA.", username))
+		message, exists := noIssuesMessage(ctx, jiraURL, jiraToken, username, includeAll, statusFilter)
+		if degradedNote != "" {
+			message += "\n\n" + degradedNote
+		}
+		if !exists {
+			sendRequestError(ctx, cmd.ResponseURL, message)
+			return
+		}
+		if sprintOnly {
+			message += "\n_Only the current sprint was searched. Drop `--sprint` to see the rest._"
+		}
+		if err := sendSlackResponse(cmd.ResponseURL, SlackSlashResponse{ResponseType: "ephemeral", Text: message}); err != nil {
+			ctxLogf(ctx, "   ❌ ERROR sending response: %v\n", err)
+		}
 		return
 	}
 
-	// Check if we have results (status filter already applied in JQL)
-	if statusFilter != "" && len(userIssues) == 0 {
-		displayStatus := statusFilter
-		if statusFilter == "MODIFIED" {
-			displayStatus = "Modified"
+	// Group issues by status, in the requested order within each status
+	sortUserIssues(userIssues, sortKey)
+	statusGroups := groupIssuesByStatus(userIssues)
+
+	// Post a thread in the channel for --share, or for every command with SLASH_PUBLIC_RESPONSES
+	if share || envBool("SLASH_PUBLIC_RESPONSES", false) {
+		channel, err := slashResponseChannel(slackBotToken, cmd)
+		if err == nil {
+			err = sendThreadedResponse(slackBotToken, channel, jiraURL, username, statusGroups, includeAll, statusFilter, sprintOnly)
 		}
-		sendErrorResponse(cmd.ResponseURL, fmt.Sprintf("No *%s* issues found for: *%s*", displayStatus, username))
-		return
+		if err == nil {
+			ctxLogf(ctx, "✅ Posted %d issues for %s to %s\n", len(userIssues), username, channel)
+			return
+		}
+		// Fall back to the private response, e.g. when the bot isn't a member of the channel
+		ctxLogf(ctx, "   ⚠️  Failed to post to channel, answering privately: %v\n", err)
 	}
 
-	// Group issues by status
-	statusGroups := groupIssuesByStatus(userIssues)
+	// Build ephemeral response (private, only visible to user), one message per page
+	if degradedNote != "" {
+		if limitNote != "" {
+			limitNote += " "
+		}
+		limitNote += degradedNote
+	}
+	pages := buildEphemeralStatusPages(jiraURL, username, user, statusGroups, includeAll, allYears, sprintOnly, statusFilter, sortKey, limit, limitNote)
 
-	// Build ephemeral response (private, only visible to user)
-	blocks := buildEphemeralStatusBlocks(jiraURL, username, statusGroups, includeAll, statusFilter)
+	if err := sendEphemeralPages(ctx, slackBotToken, cmd, fmt.Sprintf("Issues for %s", shortDisplayName(username)), pages); err != nil {
+		ctxLogf(ctx, "   ❌ ERROR sending ephemeral response: %v\n", err)
+		return
+	}
 
-	err = sendSlackResponse(cmd.ResponseURL, SlackSlashResponse{
-		ResponseType: "ephemeral",
-		Blocks:       blocks,
+	ctxLogf(ctx, "✅ Sent %d issues for %s to @%s (ephemeral)\n", len(userIssues), username, cmd.UserName)
+}
+
+// fetchUserIssues runs the slash command query and returns the issues belonging to username
+// Also returns the JIRA user the issues matched, for linking back to JIRA.
+func fetchUserIssues(ctx context.Context, jiraURL, jiraToken, username string, includeAll bool, statusFilter string, sprintOnly bool) ([]IssueItem, jiraUser, error) {
+	// Build JQL based on flags
+	jql := withSprintClause(buildJQLQueryWithStatus(username, includeAll, statusFilter), sprintOnly)
+	ctxLogf(ctx, "   JQL: %s\n", jql)
+	// Filter issues for the specified user page by page, keeping only the matches
+	// of every name variant so a fallback variant doesn't need another fetch
+	// For slash commands, show ALL user issues (skipFilters=true)
+	variants := userNameVariants(username)
+	matches := make([][]IssueItem, len(variants))
+	accounts := make([]map[string]bool, len(variants))
+	for i := range accounts {
+		accounts[i] = make(map[string]bool)
+	}
+	opts := fetchOptions{ExtraFields: []string{"updated", "resolutiondate"}}
+	if sprintOnly {
+		opts.ExtraFields = append(opts.ExtraFields, sprintField())
+	}
+	if issueLinksNeeded() {
+		opts.ExtraFields = append(opts.ExtraFields, "issuelinks")
+	}
+	_, err := streamJiraIssuesContext(ctx, jiraURL, jiraToken, jql, opts, func(page JiraSearchResponse) error {
+		for i, variant := range variants {
+			matches[i] = append(matches[i], filterIssuesByUser([]JiraSearchResponse{page}, variant, true)...)
+			collectUserAccounts(page, variant, accounts[i])
+		}
+		return nil
 	})
 	if err != nil {
-		fmt.Printf("   ❌ ERROR sending ephemeral response: %v\n", err)
-		return
+		return nil, jiraUser{}, err
+	}
+	ctxLogf(ctx, "   ✓ Fetched JIRA responses\n")
+
+	for i, variant := range variants {
+		if len(matches[i]) == 0 {
+			continue
+		}
+		if variant != username {
+			ctxLogf(ctx, "   ✓ Matched %q as %q (variant %d of %d)\n", username, variant, i+1, len(variants))
+		}
+		ctxLogf(ctx, "   ✓ Found %d issues for %s\n", len(matches[i]), variant)
+		return matches[i], jiraUser{Name: variant, Accounts: sortedAccounts(accounts[i])}, nil
 	}
 
-	fmt.Printf("✅ Sent %d issues for %s to @%s (ephemeral)\n", len(userIssues), username, cmd.UserName)
+	ctxLogf(ctx, "   ✓ Found 0 issues for %s (tried %q)\n", username, variants)
+	return nil, jiraUser{Name: username}, nil
 }
 
 // buildJQLQueryWithStatus constructs the JQL query based on flags
@@ -269,7 +418,7 @@ func buildJQLQueryWithStatus(username string, includeAll bool, statusFilter stri
 	jql := "project = MTV"
 
 	if statusFilter != "" {
-		jql += fmt.Sprintf(" AND status = \"%s\"", statusFilter)
+		jql += " AND " + statusJQLClause(statusFilter)
 		jql += " AND updated >= -365d ORDER BY updated DESC"
 	} else if includeAll {
 		jql += " AND updated >= -365d ORDER BY status ASC, updated DESC"
@@ -295,11 +444,16 @@ func groupIssuesByStatus(issues []IssueItem) map[string][]IssueItem {
 	return groups
 }
 
-// buildEphemeralStatusBlocks creates a flat ephemeral message organized by status
-// Respects Slack's 50 block limit by truncating if needed
-func buildEphemeralStatusBlocks(jiraURL, username string, statusGroups map[string][]IssueItem, includeAll bool, statusFilter string) []map[string]interface{} {
-	// Status order
-	statusOrder := []string{"Open", "In Progress", "Modified", "Closed", "Archived", "POST", "ON_QA", "MODIFIED", "Verified", "Done"}
+// slashStatusOrder is the order statuses appear in within slash command results,
+// private or shared. Statuses not listed follow alphabetically.
+var slashStatusOrder = []string{"Open", "In Progress", "Closed", "Archived", "POST", "ON_QA", "MODIFIED", "Verified", "Done"}
+
+// buildEphemeralStatusPages creates the ephemeral messages for a result, organized by status.
+// Slack allows 50 blocks per message, so long results are split into pages (sent as
+// separate ephemeral messages) with "Part N/M" footers, up to EPHEMERAL_MAX_PAGES
+// (default and maximum 5, the number of messages a response_url accepts). The last page ends with a "Share to channel" button.
+func buildEphemeralStatusPages(jiraURL, username string, user jiraUser, statusGroups map[string][]IssueItem, includeAll, allYears, sprintOnly bool, statusFilter, sortKey string, limit int, limitNote string) [][]map[string]interface{} {
+	statusOrder := slashStatusOrder
 
 	// Calculate total issues
 	totalIssues := 0
@@ -307,42 +461,53 @@ func buildEphemeralStatusBlocks(jiraURL, username string, statusGroups map[strin
 		totalIssues += len(issues)
 	}
 
-	// Build summary lines
-	summaryLines := []string{}
-	for _, status := range statusOrder {
-		if issues, exists := statusGroups[status]; exists {
-			summaryLines = append(summaryLines, fmt.Sprintf("• *%s:* %d", status, len(issues)))
-		}
+	// Closed issues of --all results are grouped by resolution year (see slash-years.go)
+	var hiddenYears map[string][]IssueItem
+	if includeAll && statusFilter == "" {
+		var yearOrder map[string][]string
+		statusGroups, yearOrder, hiddenYears = splitByResolutionYear(statusGroups, allYears, reportNow())
+		statusOrder = expandStatusOrder(statusOrder, yearOrder)
 	}
 
-	// Add any statuses not in predefined order
-	for status, issues := range statusGroups {
-		found := false
-		for _, s := range statusOrder {
-			if s == status {
-				found = true
-				break
-			}
-		}
-		if !found {
-			summaryLines = append(summaryLines, fmt.Sprintf("• *%s:* %d", status, len(issues)))
+	// Build summary lines, each linking to the status' issues in JIRA
+	summaryLines := []string{}
+	for _, status := range statusesInOrder(presentStatuses(statusGroups, hiddenYears), statusOrder) {
+		if issues, exists := statusGroups[status]; exists {
+			summaryLines = append(summaryLines, fmt.Sprintf("• <%s|*%s:*> %d", jiraSearchURL(jiraURL, withSprintClause(statusGroupJQL(user, status), sprintOnly)), escapeSlackMrkdwn(status), len(issues)))
+		} else {
+			summaryLines = append(summaryLines, fmt.Sprintf("• <%s|*%s:*> %d _(not listed)_", jiraSearchURL(jiraURL, withSprintClause(statusGroupJQL(user, status), sprintOnly)), escapeSlackMrkdwn(status), len(hiddenYears[status])))
 		}
 	}
 
 	// Build title based on filters
-	title := fmt.Sprintf("🔍 Issues for %s", username)
+	name := shortDisplayName(username)
+	title := fmt.Sprintf("🔍 Issues for %s", name)
 	if statusFilter != "" {
 		// Display friendly status name (title case instead of UPPERCASE)
 		displayStatus := statusFilter
 		if statusFilter == "MODIFIED" {
 			displayStatus = "Modified"
 		}
-		title = fmt.Sprintf("🔍 %s Issues for %s", displayStatus, username)
+		title = fmt.Sprintf("🔍 %s Issues for %s", displayStatus, name)
 	} else if includeAll {
-		title = fmt.Sprintf("🔍 All Issues for %s", username)
+		title = fmt.Sprintf("🔍 All Issues for %s", name)
+	}
+
+	overview := fmt.Sprintf("Found *%d* issue(s) across *%d* status(es)", totalIssues, len(statusGroups))
+	if sprint := sprintOverviewLine(statusGroups, sprintOnly); sprint != "" {
+		overview += "\n" + sprint
+	}
+	if limit > 0 {
+		overview += fmt.Sprintf(", showing up to %d per status", limit)
+	}
+	if limitNote != "" {
+		overview += "\n_" + limitNote + "_"
+	}
+	if len(hiddenYears) > 0 {
+		overview += "\n_Closed issues of earlier years are counted but not listed. Add `--all-years` to list them._"
 	}
 
-	blocks := []map[string]interface{}{
+	intro := []map[string]interface{}{
 		{
 			"type": "header",
 			"text": map[string]string{
@@ -354,198 +519,151 @@ func buildEphemeralStatusBlocks(jiraURL, username string, statusGroups map[strin
 			"type": "section",
 			"text": map[string]string{
 				"type": "mrkdwn",
-				"text": fmt.Sprintf("Found *%d* issue(s) across *%d* status(es)\n\n📊 *Summary:*\n%s",
-					totalIssues, len(statusGroups), strings.Join(summaryLines, "\n")),
+				"text": fmt.Sprintf("%s\n\n📊 *Summary:*\n%s", overview, strings.Join(summaryLines, "\n")),
 			},
 		},
 		{"type": "divider"},
 	}
 
-	const maxBlocks = 48 // Leave room for header/summary/dividers
-	currentBlocks := 3   // Header + summary + divider
-	issuesShown := 0     // Track how many issues displayed
-	truncated := false   // Track if we've added truncation message
-
-	// Add issues by status
-	for _, status := range statusOrder {
-		issues, exists := statusGroups[status]
-		if !exists {
-			continue
+	// Lay out every status header and issue in display order
+	var entries []ephemeralEntry
+	for _, status := range statusesInOrder(presentStatuses(statusGroups), statusOrder) {
+		issues := statusGroups[status]
+		shownIssues, hidden := limitedIssues(issues, limit)
+		for _, issue := range shownIssues {
+			entries = append(entries, ephemeralEntry{
+				status:      status,
+				statusCount: len(issues),
+				text:        slashIssueText(jiraURL, issue),
+			})
 		}
-
-		// Check if we have room for at least the status header + 1 issue
-		if currentBlocks+2 > maxBlocks {
-			if !truncated {
-				remainingIssues := totalIssues - issuesShown
-				blocks = append(blocks, map[string]interface{}{
-					"type": "section",
-					"text": map[string]string{
-						"type": "mrkdwn",
-						"text": fmt.Sprintf("\n_...and %d more issue(s) not shown_", remainingIssues),
-					},
-				})
-				truncated = true
-			}
-			break
+		if hidden > 0 {
+			entries = append(entries, ephemeralEntry{
+				status:      status,
+				statusCount: len(issues),
+				text:        fmt.Sprintf("_…and %d more_", hidden),
+			})
 		}
+	}
 
-		// Add status header
-		blocks = append(blocks, map[string]interface{}{
-			"type": "section",
-			"text": map[string]string{
-				"type": "mrkdwn",
-				"text": fmt.Sprintf("\n📂 *%s* (%d)", status, len(issues)),
-			},
-		})
-		currentBlocks++
-
-		// Add issues for this status
-		for i, issue := range issues {
-			if currentBlocks >= maxBlocks {
-				if !truncated {
-					remainingInStatus := len(issues) - i
-					remainingTotal := totalIssues - issuesShown
-					blocks = append(blocks, map[string]interface{}{
-						"type": "section",
-						"text": map[string]string{
-							"type": "mrkdwn",
-							"text": fmt.Sprintf("_...and %d more in this status (%d total remaining)_",
-								remainingInStatus, remainingTotal),
-						},
-					})
-					truncated = true
-				}
-				break
-			}
+	pages := paginateEphemeralEntries(intro, entries, envInt("EPHEMERAL_MAX_PAGES", 5))
 
-			// Format PR links
-			pr := "–"
-			if len(issue.GitPullRequest) > 0 {
-				var prLinks []string
-				for j, prURL := range issue.GitPullRequest {
-					prLinks = append(prLinks, fmt.Sprintf("<%s|PR%d>", prURL, j+1))
-				}
-				pr = strings.Join(prLinks, " ")
-			}
+	// Offer to post the same results publicly in the channel
+	if shareBlock := buildShareButtonBlock(username, includeAll, sprintOnly, statusFilter, sortKey); shareBlock != nil {
+		pages[len(pages)-1] = append(pages[len(pages)-1], shareBlock)
+	}
 
-			// Escape and truncate summary
-			summary := escapeSlackText(issue.Summary)
-			if len(summary) > 100 {
-				summary = summary[:100] + "..."
-			}
+	return pages
+}
 
-			text := fmt.Sprintf("• <%s/browse/%s|*%s*> — %s\n   *Status:* %s  |  *PR:* %s",
-				jiraURL, issue.Key, issue.Key, summary, issue.Status, pr)
+// slashIssueText formats an issue line of a slash command result, private or shared.
+func slashIssueText(jiraURL string, issue IssueItem) string {
+	return renderIssueLine(newIssueLineData(jiraURL, issue, 100, envRender), envRender)
+}
 
-			blocks = append(blocks, map[string]interface{}{
-				"type": "section",
-				"text": map[string]string{
-					"type": "mrkdwn",
-					"text": text,
-				},
-			})
-			currentBlocks++
-			issuesShown++
+// ephemeralEntry is one issue line of an ephemeral result.
+type ephemeralEntry struct {
+	status      string
+	statusCount int
+	text        string
+}
+
+// ephemeralPageBlocks is the number of blocks per page, leaving room for the
+// page footer and the share button within Slack's 50 block limit.
+const ephemeralPageBlocks = 47
+
+// paginateEphemeralEntries splits the issue lines into pages of at most
+// ephemeralPageBlocks blocks, starting with the intro blocks. Every page starts a
+// status with its header (marked "cont." when continued from the previous page).
+// A status that fits on a page of its own isn't split: it starts the next page
+// instead, as long as pages remain.
+// Issues beyond maxPages are summarized on the last page.
+func paginateEphemeralEntries(intro []map[string]interface{}, entries []ephemeralEntry, maxPages int) [][]map[string]interface{} {
+	// A response_url accepts at most 5 messages
+	if maxPages < 1 {
+		maxPages = 1
+	} else if maxPages > 5 {
+		maxPages = 5
+	}
+
+	section := func(text string) map[string]interface{} {
+		return map[string]interface{}{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": text,
+			},
 		}
 	}
 
-	// Add any statuses not in predefined order
-	for status, issues := range statusGroups {
-		// Skip if already processed
-		found := false
-		for _, s := range statusOrder {
-			if s == status {
-				found = true
-				break
-			}
-		}
-		if found {
-			continue
+	// Length of the run of entries each status starts, to keep statuses on one page
+	runLength := make([]int, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		runLength[i] = 1
+		if i+1 < len(entries) && entries[i+1].status == entries[i].status {
+			runLength[i] += runLength[i+1]
 		}
+	}
 
-		// Check if we have room for at least the status header + 1 issue
-		if currentBlocks+2 > maxBlocks {
-			if !truncated {
-				remainingIssues := totalIssues - issuesShown
-				blocks = append(blocks, map[string]interface{}{
-					"type": "section",
-					"text": map[string]string{
-						"type": "mrkdwn",
-						"text": fmt.Sprintf("\n_...and %d more issue(s) not shown_", remainingIssues),
-					},
-				})
-				truncated = true
-			}
-			break
+	pages := [][]map[string]interface{}{intro}
+	page := intro
+	currentStatus := ""
+	shown := 0
+	for i, entry := range entries {
+		newStatus := entry.status != currentStatus
+		needed := 1
+		if newStatus {
+			needed = 2 // Keep a status header together with its first issue
 		}
 
-		// Add status header
-		blocks = append(blocks, map[string]interface{}{
-			"type": "section",
-			"text": map[string]string{
-				"type": "mrkdwn",
-				"text": fmt.Sprintf("\n📂 *%s* (%d)", status, len(issues)),
-			},
-		})
-		currentBlocks++
-
-		// Add issues for this status
-		for i, issue := range issues {
-			if currentBlocks >= maxBlocks {
-				if !truncated {
-					remainingInStatus := len(issues) - i
-					remainingTotal := totalIssues - issuesShown
-					blocks = append(blocks, map[string]interface{}{
-						"type": "section",
-						"text": map[string]string{
-							"type": "mrkdwn",
-							"text": fmt.Sprintf("_...and %d more in this status (%d total remaining)_",
-								remainingInStatus, remainingTotal),
-						},
-					})
-					truncated = true
-				}
+		// A status that would be split but fits on a page of its own starts the next page
+		breakAtStatus := newStatus && len(page) > len(intro) && len(pages) < maxPages &&
+			len(page)+1+runLength[i] > ephemeralPageBlocks && 1+runLength[i] <= ephemeralPageBlocks
+		if breakAtStatus || len(page)+needed > ephemeralPageBlocks {
+			if len(pages) == maxPages {
 				break
 			}
-
-			// Format PR links
-			pr := "–"
-			if len(issue.GitPullRequest) > 0 {
-				var prLinks []string
-				for j, prURL := range issue.GitPullRequest {
-					prLinks = append(prLinks, fmt.Sprintf("<%s|PR%d>", prURL, j+1))
-				}
-				pr = strings.Join(prLinks, " ")
+			page = []map[string]interface{}{}
+			pages = append(pages, page)
+			if !newStatus {
+				page = append(page, section(fmt.Sprintf("\n📂 *%s* (%d, cont.)", escapeSlackMrkdwn(entry.status), entry.statusCount)))
 			}
+		}
 
-			// Escape and truncate summary
-			summary := escapeSlackText(issue.Summary)
-			if len(summary) > 100 {
-				summary = summary[:100] + "..."
-			}
+		if newStatus {
+			page = append(page, section(fmt.Sprintf("\n📂 *%s* (%d)", escapeSlackMrkdwn(entry.status), entry.statusCount)))
+			currentStatus = entry.status
+		}
+		page = append(page, section(entry.text))
+		pages[len(pages)-1] = page
+		shown++
+	}
 
-			text := fmt.Sprintf("• <%s/browse/%s|*%s*> — %s\n   *Status:* %s  |  *PR:* %s",
-				jiraURL, issue.Key, issue.Key, summary, issue.Status, pr)
+	if remaining := len(entries) - shown; remaining > 0 {
+		last := len(pages) - 1
+		pages[last] = append(pages[last], section(fmt.Sprintf("\n_...and %d more issue(s) not shown. Use a status filter such as `--on-qa` to narrow the results._", remaining)))
+	}
 
-			blocks = append(blocks, map[string]interface{}{
-				"type": "section",
-				"text": map[string]string{
-					"type": "mrkdwn",
-					"text": text,
+	if len(pages) > 1 {
+		for i := range pages {
+			pages[i] = append(pages[i], map[string]interface{}{
+				"type": "context",
+				"elements": []map[string]string{
+					{"type": "mrkdwn", "text": fmt.Sprintf("Part %d/%d", i+1, len(pages))},
 				},
 			})
-			currentBlocks++
-			issuesShown++
 		}
 	}
 
-	return blocks
+	for _, page := range pages {
+		limitBlockText(page)
+	}
+	return pages
 }
 
 // sendThreadedResponse sends the main summary message and status group replies
-func sendThreadedResponse(botToken, channel, jiraURL, username string, statusGroups map[string][]IssueItem, includeAll bool) error {
-	// Define status order
-	statusOrder := []string{"Open", "In Progress", "Modified", "Closed", "Archived", "POST", "ON_QA", "MODIFIED", "Verified", "Done"}
+func sendThreadedResponse(botToken, channel, jiraURL, username string, statusGroups map[string][]IssueItem, includeAll bool, statusFilter string, sprintOnly bool) error {
+	statusOrder := slashStatusOrder
 
 	// Calculate total issues
 	totalIssues := 0
@@ -553,31 +671,28 @@ func sendThreadedResponse(botToken, channel, jiraURL, username string, statusGro
 		totalIssues += len(issues)
 	}
 
-	// Build summary for main message
+	// Build summary for main message, unlisted statuses last
+	statuses := statusesInOrder(presentStatuses(statusGroups), statusOrder)
 	summaryLines := []string{}
-	for _, status := range statusOrder {
-		if issues, exists := statusGroups[status]; exists {
-			summaryLines = append(summaryLines, fmt.Sprintf("• *%s:* %d issue(s)", status, len(issues)))
-		}
+	for _, status := range statuses {
+		summaryLines = append(summaryLines, fmt.Sprintf("• *%s:* %d issue(s)", escapeSlackMrkdwn(status), len(statusGroups[status])))
 	}
 
-	// Add any statuses not in the predefined order
-	for status, issues := range statusGroups {
-		found := false
-		for _, s := range statusOrder {
-			if s == status {
-				found = true
-				break
-			}
-		}
-		if !found {
-			summaryLines = append(summaryLines, fmt.Sprintf("• *%s:* %d issue(s)", status, len(issues)))
+	name := shortDisplayName(username)
+	title := fmt.Sprintf("🔍 Issues for %s", name)
+	if statusFilter != "" {
+		displayStatus := statusFilter
+		if statusFilter == "MODIFIED" {
+			displayStatus = "Modified"
 		}
+		title = fmt.Sprintf("🔍 %s Issues for %s", displayStatus, name)
+	} else if includeAll {
+		title = fmt.Sprintf("🔍 All Issues for %s", name)
 	}
 
-	title := fmt.Sprintf("🔍 Issues for %s", username)
-	if includeAll {
-		title = fmt.Sprintf("🔍 All Issues for %s", username)
+	overview := fmt.Sprintf("Found *%d* issue(s) across *%d* status(es)", totalIssues, len(statusGroups))
+	if sprint := sprintOverviewLine(statusGroups, sprintOnly); sprint != "" {
+		overview += "\n" + sprint
 	}
 
 	// Build main summary message blocks
@@ -593,31 +708,28 @@ func sendThreadedResponse(botToken, channel, jiraURL, username string, statusGro
 			"type": "section",
 			"text": map[string]string{
 				"type": "mrkdwn",
-				"text": fmt.Sprintf("Found *%d* issue(s) across *%d* status(es)\n\n📊 *Summary:*\n%s\n\n👇 _See details in thread below_",
-					totalIssues, len(statusGroups), strings.Join(summaryLines, "\n")),
+				"text": fmt.Sprintf("%s\n\n📊 *Summary:*\n%s\n\n👇 _See details in thread below_",
+					overview, strings.Join(summaryLines, "\n")),
 			},
 		},
 	}
+	limitBlockText(summaryBlocks)
 
 	// Send main message to create thread
-	fmt.Printf("   Creating thread with summary...\n")
-	threadTS, err := sendToSlackAPI(botToken, channel, "", summaryBlocks)
+	logf("   Creating thread with summary...\n")
+	threadTS, err := sendToSlackAPI(botToken, channel, "", summaryBlocks, envRender)
 	if err != nil {
 		return fmt.Errorf("failed to send summary message: %w", err)
 	}
-	fmt.Printf("   ✓ Thread created\n")
+	logf("   ✓ Thread created\n")
 
 	// Send each status group as a thread reply (in order)
 	// Split large groups to avoid Slack's 50 block limit
 	const maxIssuesPerMessage = 15
 	sentCount := 0
 
-	for _, status := range statusOrder {
-		issues, exists := statusGroups[status]
-		if !exists {
-			continue
-		}
-
+	for _, status := range statuses {
+		issues := statusGroups[status]
 		sentCount++
 
 		// Split into chunks if too many issues
@@ -629,66 +741,25 @@ func sendThreadedResponse(botToken, channel, jiraURL, username string, statusGro
 			chunk := issues[i:end]
 
 			if len(issues) <= maxIssuesPerMessage {
-				fmt.Printf("   Sending status group %d/%d: %s (%d issues)...\n", sentCount, len(statusGroups), status, len(issues))
+				logf("   Sending status group %d/%d: %s (%d issues)...\n", sentCount, len(statusGroups), status, len(issues))
 			} else {
-				fmt.Printf("   Sending status group %d/%d: %s (%d-%d of %d issues)...\n",
+				logf("   Sending status group %d/%d: %s (%d-%d of %d issues)...\n",
 					sentCount, len(statusGroups), status, i+1, end, len(issues))
 			}
 
 			blocks := buildStatusGroupBlocks(jiraURL, status, chunk, i == 0)
-			_, err = sendToSlackAPI(botToken, channel, threadTS, blocks)
+			_, err = sendToSlackAPI(botToken, channel, threadTS, blocks, envRender)
 			if err != nil {
 				return fmt.Errorf("failed to send status group %s: %w", status, err)
 			}
 
-			fmt.Printf("   ✓ Status group %s sent\n", status)
+			logf("   ✓ Status group %s sent\n", status)
 
 			// Small delay between messages to ensure proper ordering
 			time.Sleep(500 * time.Millisecond)
 		}
 	}
 
-	// Send any remaining statuses not in predefined order
-	for status, issues := range statusGroups {
-		found := false
-		for _, s := range statusOrder {
-			if s == status {
-				found = true
-				break
-			}
-		}
-		if found {
-			continue
-		}
-
-		sentCount++
-
-		// Split into chunks if too many issues
-		for i := 0; i < len(issues); i += maxIssuesPerMessage {
-			end := i + maxIssuesPerMessage
-			if end > len(issues) {
-				end = len(issues)
-			}
-			chunk := issues[i:end]
-
-			if len(issues) <= maxIssuesPerMessage {
-				fmt.Printf("   Sending status group %d/%d: %s (%d issues)...\n", sentCount, len(statusGroups), status, len(issues))
-			} else {
-				fmt.Printf("   Sending status group %d/%d: %s (%d-%d of %d issues)...\n",
-					sentCount, len(statusGroups), status, i+1, end, len(issues))
-			}
-
-			blocks := buildStatusGroupBlocks(jiraURL, status, chunk, i == 0)
-			_, err = sendToSlackAPI(botToken, channel, threadTS, blocks)
-			if err != nil {
-				return fmt.Errorf("failed to send status group %s: %w", status, err)
-			}
-
-			fmt.Printf("   ✓ Status group %s sent\n", status)
-			time.Sleep(500 * time.Millisecond)
-		}
-	}
-
 	return nil
 }
 
@@ -703,41 +774,23 @@ func buildStatusGroupBlocks(jiraURL, status string, issues []IssueItem, isFirstC
 			"type": "section",
 			"text": map[string]string{
 				"type": "mrkdwn",
-				"text": fmt.Sprintf("📂 *%s*\n", status),
+				"text": fmt.Sprintf("📂 *%s*\n", escapeSlackMrkdwn(status)),
 			},
 		})
 		blocks = append(blocks, map[string]interface{}{"type": "divider"})
 	}
 
 	for _, issue := range issues {
-		// Format PR links
-		pr := "–"
-		if len(issue.GitPullRequest) > 0 {
-			var prLinks []string
-			for i, prURL := range issue.GitPullRequest {
-				prLinks = append(prLinks, fmt.Sprintf("<%s|PR%d>", prURL, i+1))
-			}
-			pr = strings.Join(prLinks, " ")
-		}
-
-		// Escape and truncate summary
-		summary := escapeSlackText(issue.Summary)
-		if len(summary) > 150 {
-			summary = summary[:150] + "..."
-		}
-
-		text := fmt.Sprintf("• <%s/browse/%s|*%s*> — %s\n   *Status:* %s  |  *PR:* %s",
-			jiraURL, issue.Key, issue.Key, summary, issue.Status, pr)
-
 		blocks = append(blocks, map[string]interface{}{
 			"type": "section",
 			"text": map[string]string{
 				"type": "mrkdwn",
-				"text": text,
+				"text": slashIssueText(jiraURL, issue),
 			},
 		})
 	}
 
+	limitBlockText(blocks)
 	return blocks
 }
 
@@ -762,6 +815,11 @@ func filterIssuesByUser(responses []JiraSearchResponse, username string, skipFil
 					continue
 				}
 
+				// Skip issues below the configured minimum priority
+				if activePriorityFilter.drops(issuePriority(issue)) {
+					continue
+				}
+
 				// Skip Epics without PRs
 				if issue.Fields.IssueType.Name == "Epic" && len(prs) == 0 {
 					continue
@@ -783,12 +841,7 @@ func filterIssuesByUser(responses []JiraSearchResponse, username string, skipFil
 			if strings.Contains(strings.ToLower(assigneeName), usernameLower) ||
 				strings.Contains(strings.ToLower(qaContactName), usernameLower) {
 
-				filtered = append(filtered, IssueItem{
-					Key:            issue.Key,
-					Summary:        issue.Fields.Summary,
-					Status:         issue.Fields.Status.Name,
-					GitPullRequest: prs,
-				})
+				filtered = append(filtered, newIssueItem(issue))
 			}
 		}
 	}
@@ -798,6 +851,11 @@ func filterIssuesByUser(responses []JiraSearchResponse, username string, skipFil
 
 // sendSlackResponse sends a response to Slack's response_url
 func sendSlackResponse(responseURL string, response SlackSlashResponse) error {
+	plainBlockText(response.Blocks, envRender)
+	response.Text = plainText(response.Text)
+	if err := validateBlocks(response.Blocks); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
 	data, err := json.Marshal(response)
 	if err != nil {
 		return fmt.Errorf("failed to marshal response: %w", err)
@@ -811,7 +869,7 @@ func sendSlackResponse(responseURL string, response SlackSlashResponse) error {
 
 	if resp.StatusCode >= 300 {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("Slack returned status %d: %s", resp.StatusCode, string(bodyBytes))
+		return fmt.Errorf("Slack returned status %d: %s", resp.StatusCode, redactSecrets(string(bodyBytes)))
 	}
 
 	return nil
@@ -821,55 +879,92 @@ func sendSlackResponse(responseURL string, response SlackSlashResponse) error {
 func sendErrorResponse(responseURL, errorMsg string) {
 	response := SlackSlashResponse{
 		ResponseType: "ephemeral",
-		Text:         "❌ " + errorMsg,
+		Text:         "❌ " + redactSecrets(errorMsg),
 	}
 
 	if err := sendSlackResponse(responseURL, response); err != nil {
-		fmt.Printf("❌ Failed to send error response: %v\n", err)
+		logf("❌ Failed to send error response: %v\n", err)
 	}
 }
 
 // getSlackUserRealName fetches a user's real name from Slack using their user ID
 func getSlackUserRealName(botToken, userID string) (string, error) {
-	url := fmt.Sprintf("https://slack.com/api/users.info?user=%s", userID)
+	userInfo, err := fetchSlackUserInfo(botToken, userID)
+	if err != nil {
+		return "", err
+	}
+
+	// Try display name first, then real name, then fall back to username
+	if userInfo.User.Profile.DisplayName != "" {
+		return userInfo.User.Profile.DisplayName, nil
+	}
+	if userInfo.User.RealName != "" {
+		return userInfo.User.RealName, nil
+	}
+	if userInfo.User.Profile.RealName != "" {
+		return userInfo.User.Profile.RealName, nil
+	}
+
+	return userInfo.User.Name, nil
+}
+
+// fetchSlackUserInfo calls Slack's users.info API for a user. A 429, a 5xx or a
+// failed connection is retried SLACK_USER_INFO_RETRIES times (default 2), after
+// Retry-After or a short backoff.
+func fetchSlackUserInfo(botToken, userID string) (SlackUserInfoResponse, error) {
+	retries := envInt("SLACK_USER_INFO_RETRIES", defaultSlackUserInfoRetries)
+	for attempt := 0; ; attempt++ {
+		userInfo, wait, err := fetchSlackUserInfoOnce(botToken, userID)
+		if err == nil || wait == 0 || attempt >= retries {
+			return userInfo, err
+		}
+		logf("   ⏳ users.info failed (%v), retrying in %s\n", err, wait)
+		time.Sleep(wait)
+	}
+}
+
+// fetchSlackUserInfoOnce makes a single users.info call. On a transient failure
+// it also returns how long to wait before trying again; 0 means don't retry.
+func fetchSlackUserInfoOnce(botToken, userID string) (SlackUserInfoResponse, time.Duration, error) {
+	var userInfo SlackUserInfoResponse
+	url := fmt.Sprintf("%s/users.info?user=%s", slackAPIBase, userID)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return userInfo, 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", botToken))
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to call Slack API: %w", err)
+		return userInfo, slackUserInfoBackoff, fmt.Errorf("failed to call Slack API: %w", err)
 	}
 	defer resp.Body.Close()
 
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		wait := slackUserInfoBackoff
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			wait = time.Duration(seconds) * time.Second
+		}
+		return userInfo, wait, fmt.Errorf("Slack rate limited users.info")
+	case resp.StatusCode >= 500:
+		return userInfo, slackUserInfoBackoff, fmt.Errorf("Slack returned status %d", resp.StatusCode)
+	}
+
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
+		return userInfo, 0, fmt.Errorf("failed to read response: %w", err)
 	}
 
-	var userInfo SlackUserInfoResponse
 	if err := json.Unmarshal(bodyBytes, &userInfo); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
+		return userInfo, 0, fmt.Errorf("failed to parse response: %w", err)
 	}
 
 	if !userInfo.OK {
-		return "", fmt.Errorf("Slack API error: %s", userInfo.Error)
-	}
-
-	// Try display name first, then real name, then fall back to username
-	if userInfo.User.Profile.DisplayName != "" {
-		return userInfo.User.Profile.DisplayName, nil
-	}
-	if userInfo.User.RealName != "" {
-		return userInfo.User.RealName, nil
-	}
-	if userInfo.User.Profile.RealName != "" {
-		return userInfo.User.Profile.RealName, nil
+		return userInfo, 0, fmt.Errorf("Slack API error: %s", userInfo.Error)
 	}
 
-	return userInfo.User.Name, nil
+	return userInfo, 0, nil
 }
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTakeSlashFlag(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		flag      string
+		wantFound bool
+		wantText  string
+	}{
+		{name: "flag after the name", text: "Jane Doe --share", flag: "--share", wantFound: true, wantText: "Jane Doe"},
+		{name: "flag before the name", text: "--all Jane Doe", flag: "--all", wantFound: true, wantText: "Jane Doe"},
+		{name: "flag alone", text: "--share", flag: "--share", wantFound: true, wantText: ""},
+		{name: "no flag", text: "Jane Doe", flag: "--share", wantText: "Jane Doe"},
+		{name: "name containing the flag", text: "jean--allard", flag: "--all", wantText: "jean--allard"},
+		{name: "longer flag isn't the flag", text: "Jane --all-years", flag: "--all", wantText: "Jane --all-years"},
+		{name: "flag given twice", text: "--share Jane --share", flag: "--share", wantFound: true, wantText: "Jane"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, text := takeSlashFlag(tt.text, tt.flag)
+			if found != tt.wantFound || text != tt.wantText {
+				t.Errorf("takeSlashFlag(%q, %q) = %v, %q; want %v, %q", tt.text, tt.flag, found, text, tt.wantFound, tt.wantText)
+			}
+		})
+	}
+}
+
+// slashJiraStub serves a search that returns issues and records the JQL of each request.
+func slashJiraStub(t *testing.T, issues ...string) (*httptest.Server, *[]string) {
+	t.Helper()
+	var page JiraSearchResponse
+	for _, issue := range issues {
+		page.Issues = append(page.Issues, parseTestIssue(t, issue))
+	}
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			JQL string `json:"jql"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request: %v", err)
+		}
+		queries = append(queries, body.JQL)
+		json.NewEncoder(w).Encode(page)
+	}))
+	t.Cleanup(server.Close)
+	return server, &queries
+}
+
+// slashSlackStub points the Slack API at a server that records each call as
+// "method channel" ("response" for the response_url) and answers chat.postMessage
+// with postError, if set. Returns the response_url to use.
+func slashSlackStub(t *testing.T, postError string) (string, *[]string) {
+	t.Helper()
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method := strings.TrimPrefix(r.URL.Path, "/")
+		if method == "response" {
+			calls = append(calls, method)
+			return
+		}
+		var payload struct {
+			Channel string `json:"channel"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		calls = append(calls, method+" "+payload.Channel)
+		if method == "chat.postMessage" && postError != "" {
+			json.NewEncoder(w).Encode(SlackMessageResponse{Error: postError})
+			return
+		}
+		json.NewEncoder(w).Encode(SlackMessageResponse{OK: true, TS: fmt.Sprint(len(calls))})
+	}))
+	t.Cleanup(server.Close)
+
+	previous := slackAPIBase
+	slackAPIBase = server.URL
+	t.Cleanup(func() { slackAPIBase = previous })
+	return server.URL + "/response", &calls
+}
+
+func TestProcessSlashCommandShare(t *testing.T) {
+	issue := `{"key": "MTV-1", "fields": {"summary": "Fix it", "status": {"name": "POST"}, "issuetype": {"name": "Bug"}, "assignee": {"displayName": "jean--allard"}}}`
+
+	tests := []struct {
+		name      string
+		text      string
+		postError string
+		wantCalls string // Slack calls, in order
+		wantJQL   string // Part of the JQL
+	}{
+		{
+			name:      "share posts to the channel",
+			text:      "jean--allard --share",
+			wantCalls: "chat.postMessage C1, chat.postMessage C1",
+			wantJQL:   "status IN (POST, ON_QA, MODIFIED)",
+		},
+		{
+			name:      "share flag first",
+			text:      "--share jean--allard",
+			wantCalls: "chat.postMessage C1, chat.postMessage C1",
+			wantJQL:   "status IN (POST, ON_QA, MODIFIED)",
+		},
+		{
+			name:      "share with --all",
+			text:      "jean--allard --all --share",
+			wantCalls: "chat.postMessage C1, chat.postMessage C1",
+			wantJQL:   "ORDER BY status ASC, updated DESC",
+		},
+		{
+			name:      "falls back to a private response when the channel rejects the post",
+			text:      "jean--allard --share",
+			postError: "not_in_channel",
+			wantCalls: "chat.postMessage C1, chat.postEphemeral C1",
+			wantJQL:   "status IN (POST, ON_QA, MODIFIED)",
+		},
+		{
+			name:      "without --share the answer is private",
+			text:      "jean--allard",
+			wantCalls: "chat.postEphemeral C1",
+			wantJQL:   "status IN (POST, ON_QA, MODIFIED)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jira, queries := slashJiraStub(t, issue)
+			responseURL, calls := slashSlackStub(t, tt.postError)
+			t.Setenv("JIRA_URL", jira.URL)
+			t.Setenv("JIRA_TOKEN", "jira-token")
+			t.Setenv("SLACK_BOT_TOKEN", "xoxb-test")
+			t.Setenv("SLASH_PUBLIC_RESPONSES", "")
+
+			processSlashCommand(context.Background(), SlackSlashCommand{
+				ChannelID:   "C1",
+				ChannelName: "mtv-team",
+				UserID:      "U1",
+				UserName:    "jane",
+				Text:        tt.text,
+				ResponseURL: responseURL,
+			})
+
+			if got := strings.Join(*calls, ", "); got != tt.wantCalls {
+				t.Errorf("Slack calls = %s, want %s", got, tt.wantCalls)
+			}
+			if len(*queries) != 1 || !strings.Contains((*queries)[0], tt.wantJQL) {
+				t.Errorf("JQL = %q, want one query with %q", *queries, tt.wantJQL)
+			}
+		})
+	}
+}
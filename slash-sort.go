@@ -0,0 +1,81 @@
+// Slash command sorting
+//
+// /issues --sort=<key> orders the issues within each status:
+//
+//	updated  - most recently updated first
+//	key      - by issue key (MTV-9 before MTV-10)
+//	priority - most important first, ranked by PRIORITY_ORDER
+//
+// Without --sort issues keep JIRA's order.
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// slashSortKeys are the accepted --sort values.
+var slashSortKeys = []string{"updated", "key", "priority"}
+
+// sortFlagPattern matches the --sort flag and its value.
+var sortFlagPattern = regexp.MustCompile(`--sort(?:=(\S*))?`)
+
+// parseSortFlag extracts --sort from the command text. Returns the sort key ("" if
+// the flag is absent), the text without the flag, and an error for unknown keys.
+func parseSortFlag(text string) (string, string, error) {
+	match := sortFlagPattern.FindStringSubmatch(text)
+	if match == nil {
+		return "", text, nil
+	}
+	text = strings.Replace(text, match[0], "", 1)
+
+	key := strings.ToLower(match[1])
+	for _, valid := range slashSortKeys {
+		if key == valid {
+			return key, text, nil
+		}
+	}
+	return "", text, fmt.Errorf("Unknown sort %q. Valid options: %s", match[1], "`--sort="+strings.Join(slashSortKeys, "`, `--sort=")+"`")
+}
+
+// sortUserIssues orders issues by the sort key. Grouping by status keeps this order.
+func sortUserIssues(issues []IssueItem, key string) {
+	switch key {
+	case "updated":
+		sort.SliceStable(issues, func(i, j int) bool {
+			return issues[i].Updated.After(issues[j].Updated)
+		})
+	case "key":
+		sort.SliceStable(issues, func(i, j int) bool {
+			return issueKeyLess(issues[i].Key, issues[j].Key)
+		})
+	case "priority":
+		rank := func(issue IssueItem) int {
+			if r, ok := activePriorityFilter.ranks[strings.ToLower(issue.Priority)]; ok {
+				return r
+			}
+			return len(activePriorityFilter.ranks)
+		}
+		sort.SliceStable(issues, func(i, j int) bool {
+			return rank(issues[i]) < rank(issues[j])
+		})
+	}
+}
+
+// issueKeyLess compares issue keys by project, then numerically by issue number.
+func issueKeyLess(a, b string) bool {
+	projectA, numberA, _ := strings.Cut(a, "-")
+	projectB, numberB, _ := strings.Cut(b, "-")
+	if projectA != projectB {
+		return projectA < projectB
+	}
+	na, errA := strconv.Atoi(numberA)
+	nb, errB := strconv.Atoi(numberB)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return na < nb
+}
@@ -0,0 +1,198 @@
+// Team slash command
+//
+// /team-issues answers privately with the open issues of the caller's whole team,
+// grouped by person. The caller is resolved from their Slack profile like
+// /issues does, their team looked up in TEAM_MAP (see team-grouping.go), and an
+// issue belongs to a teammate when they are its assignee or QA contact; an issue
+// shared by two teammates is listed under both. Callers TEAM_MAP doesn't mention
+// get a message explaining how to add them.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// handleTeamIssuesCommand processes the /team-issues slash command
+func handleTeamIssuesCommand(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		logf("⚠️  Failed to parse form on %s: %v\n", r.URL.Path, err)
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+	cmd := slashCommandFromForm(r)
+
+	ctx := withRequestID(context.Background(), newRequestID())
+	ctxLogf(ctx, "📨 Received command from @%s: %s %s\n", cmd.UserName, cmd.Command, cmd.Text)
+	if refuseSlashChannel(ctx, w, cmd) {
+		return
+	}
+
+	// Acknowledge within Slack's 3 seconds, the fetch takes longer
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(SlackSlashResponse{
+		ResponseType: "ephemeral",
+		Text:         "🔍 Fetching your team's JIRA issues...",
+	})
+
+	go processTeamCommand(ctx, cmd)
+}
+
+// callerTeam returns the TEAM_MAP team of name, trying its name variants in order.
+func callerTeam(name string) (string, bool) {
+	for _, variant := range userNameVariants(name) {
+		if team, ok := activeTeamMap[strings.ToLower(variant)]; ok {
+			return team, true
+		}
+	}
+	return "", false
+}
+
+// teamMembers returns the lowercased names TEAM_MAP assigns to team.
+func teamMembers(team string) map[string]bool {
+	members := make(map[string]bool)
+	for name, memberTeam := range activeTeamMap {
+		if memberTeam == team {
+			members[name] = true
+		}
+	}
+	return members
+}
+
+// groupTeamIssues files each issue under the teammates among its assignee and QA contact.
+func groupTeamIssues(page JiraSearchResponse, members map[string]bool, groups map[string][]IssueItem) {
+	for _, issue := range page.Issues {
+		var people []string
+		if issue.Fields.Assignee != nil {
+			people = append(people, issue.Fields.Assignee.DisplayName)
+		}
+		if issue.Fields.QAContact != nil && (issue.Fields.Assignee == nil || issue.Fields.QAContact.DisplayName != issue.Fields.Assignee.DisplayName) {
+			people = append(people, issue.Fields.QAContact.DisplayName)
+		}
+		for _, person := range people {
+			if members[strings.ToLower(person)] {
+				groups[person] = append(groups[person], newIssueItem(issue))
+			}
+		}
+	}
+}
+
+// processTeamCommand fetches the open issues of the caller's team and sends them
+// privately, grouped by person.
+func processTeamCommand(ctx context.Context, cmd SlackSlashCommand) {
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	slackBotToken := os.Getenv("SLACK_BOT_TOKEN")
+
+	if jiraURL == "" || jiraToken == "" {
+		sendRequestError(ctx, cmd.ResponseURL, "Configuration error: JIRA_URL or JIRA_TOKEN not set")
+		return
+	}
+	if slackBotToken == "" {
+		sendRequestError(ctx, cmd.ResponseURL, "Configuration error: SLACK_BOT_TOKEN not set")
+		return
+	}
+
+	caller, err := getSlackUserRealName(slackBotToken, cmd.UserID)
+	if err != nil {
+		sendRequestError(ctx, cmd.ResponseURL, "Failed to auto-detect your name from your Slack profile.")
+		return
+	}
+	team, ok := callerTeam(caller)
+	if !ok {
+		message := fmt.Sprintf("You (%s) aren't in any team. Ask an admin to add your JIRA display name to `TEAM_MAP`, e.g. `{\"%s\": \"Storage\"}`.\n\nUse `/issues` to see your own issues meanwhile.", caller, caller)
+		if err := sendSlackResponse(cmd.ResponseURL, SlackSlashResponse{ResponseType: "ephemeral", Text: message}); err != nil {
+			ctxLogf(ctx, "   ❌ ERROR sending response: %v\n", err)
+		}
+		return
+	}
+	members := teamMembers(team)
+	ctxLogf(ctx, "   Fetching open issues of team %s (%d people) for %s...\n", team, len(members), caller)
+
+	jql := buildJQLQueryWithStatus("", false, "")
+	ctxLogf(ctx, "   JQL: %s\n", jql)
+	groups := make(map[string][]IssueItem)
+	_, err = streamJiraIssuesContext(ctx, jiraURL, jiraToken, jql, fetchOptions{ExtraFields: []string{"updated"}}, func(page JiraSearchResponse) error {
+		groupTeamIssues(page, members, groups)
+		return nil
+	})
+	if err != nil {
+		ctxLogf(ctx, "   ❌ JIRA fetch error: %v\n", err)
+		sendRequestError(ctx, cmd.ResponseURL, fmt.Sprintf("Failed to fetch JIRA issues: %v", err))
+		return
+	}
+
+	if len(groups) == 0 {
+		message := fmt.Sprintf("✅ Team %s has no open issues.", team)
+		if err := sendSlackResponse(cmd.ResponseURL, SlackSlashResponse{ResponseType: "ephemeral", Text: message}); err != nil {
+			ctxLogf(ctx, "   ❌ ERROR sending response: %v\n", err)
+		}
+		return
+	}
+
+	pages := buildTeamIssuePages(jiraURL, team, groups)
+	if err := sendEphemeralPages(ctx, slackBotToken, cmd, fmt.Sprintf("Issues of team %s", team), pages); err != nil {
+		ctxLogf(ctx, "   ❌ ERROR sending ephemeral response: %v\n", err)
+		return
+	}
+	ctxLogf(ctx, "✅ Sent the issues of %d people of team %s to @%s (ephemeral)\n", len(groups), team, cmd.UserName)
+}
+
+// buildTeamIssuePages creates the ephemeral messages for a team, one group per
+// person in alphabetical order.
+func buildTeamIssuePages(jiraURL, team string, groups map[string][]IssueItem) [][]map[string]interface{} {
+	people := make([]string, 0, len(groups))
+	total := 0
+	for person, issues := range groups {
+		people = append(people, person)
+		total += len(issues)
+	}
+	sort.Slice(people, func(i, j int) bool {
+		return strings.ToLower(people[i]) < strings.ToLower(people[j])
+	})
+
+	var summaryLines []string
+	var entries []ephemeralEntry
+	for _, person := range people {
+		issues := groups[person]
+		summaryLines = append(summaryLines, fmt.Sprintf("• *%s:* %d", escapeSlackMrkdwn(shortDisplayName(person)), len(issues)))
+		for _, issue := range issues {
+			entries = append(entries, ephemeralEntry{
+				status:      person,
+				statusCount: len(issues),
+				text:        slashIssueText(jiraURL, issue),
+			})
+		}
+	}
+
+	intro := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]string{
+				"type": "plain_text",
+				"text": fmt.Sprintf("👥 Open Issues of Team %s", team),
+			},
+		},
+		{
+			"type": "section",
+			"text": map[string]string{
+				"type": "mrkdwn",
+				"text": fmt.Sprintf("Found *%d* issue(s) across *%d* people\n\n📊 *Summary:*\n%s", total, len(people), strings.Join(summaryLines, "\n")),
+			},
+		},
+		{"type": "divider"},
+	}
+
+	return paginateEphemeralEntries(intro, entries, envInt("EPHEMERAL_MAX_PAGES", 5))
+}
@@ -0,0 +1,117 @@
+// Slash command resolution years
+//
+// /issues --all returns a year of closed issues, which in one "Closed" group is
+// more noise than information. With --all, the Closed, Verified and Done groups
+// are split by resolution year ("Closed (2024)", newest first) and only the
+// current year is listed; the summary still counts every year, linking to each in
+// JIRA. --all-years (which implies --all) lists every year.
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// yearSplitStatuses are the statuses --all results split by resolution year.
+var yearSplitStatuses = []string{"Closed", "Verified", "Done"}
+
+// yearStatusPattern matches a status group name produced by splitByResolutionYear.
+var yearStatusPattern = regexp.MustCompile(`^(.+) \((\d{4})\)$`)
+
+// parseAllYearsFlag extracts --all-years from the command text. Returns whether it
+// was given and the text without it.
+func parseAllYearsFlag(text string) (bool, string) {
+	allYears, text := takeSlashFlag(text, "--all-years")
+	if allYears {
+		// Implies --all
+		text += " --all"
+	}
+	return allYears, text
+}
+
+// resolutionYear returns the year an issue was resolved, falling back to its last
+// update and then to the current year when neither is known.
+func resolutionYear(issue IssueItem, now time.Time) int {
+	switch {
+	case !issue.Resolved.IsZero():
+		return issue.Resolved.Year()
+	case !issue.Updated.IsZero():
+		return issue.Updated.Year()
+	}
+	return now.Year()
+}
+
+// yearStatus names the group of a status' issues resolved in year.
+func yearStatus(status string, year int) string {
+	return fmt.Sprintf("%s (%d)", status, year)
+}
+
+// splitByResolutionYear replaces the groups of yearSplitStatuses with one group
+// per resolution year. Returns the new groups, the year groups of each split
+// status (newest first) for ordering, and the year groups left out because
+// allYears is off (only the current year is kept).
+func splitByResolutionYear(statusGroups map[string][]IssueItem, allYears bool, now time.Time) (map[string][]IssueItem, map[string][]string, map[string][]IssueItem) {
+	split := make(map[string][]IssueItem, len(statusGroups))
+	for status, issues := range statusGroups {
+		split[status] = issues
+	}
+	order := make(map[string][]string)
+	hidden := make(map[string][]IssueItem)
+
+	for _, status := range yearSplitStatuses {
+		issues, ok := split[status]
+		if !ok {
+			continue
+		}
+		delete(split, status)
+
+		byYear := make(map[int][]IssueItem)
+		var years []int
+		for _, issue := range issues {
+			year := resolutionYear(issue, now)
+			if _, seen := byYear[year]; !seen {
+				years = append(years, year)
+			}
+			byYear[year] = append(byYear[year], issue)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(years)))
+
+		for _, year := range years {
+			name := yearStatus(status, year)
+			order[status] = append(order[status], name)
+			if allYears || year == now.Year() {
+				split[name] = byYear[year]
+			} else {
+				hidden[name] = byYear[year]
+			}
+		}
+	}
+	return split, order, hidden
+}
+
+// expandStatusOrder replaces each split status in statusOrder with its year groups.
+func expandStatusOrder(statusOrder []string, yearOrder map[string][]string) []string {
+	var expanded []string
+	for _, status := range statusOrder {
+		if years, ok := yearOrder[status]; ok {
+			expanded = append(expanded, years...)
+			continue
+		}
+		expanded = append(expanded, status)
+	}
+	return expanded
+}
+
+// statusGroupJQL returns the query behind a status group, narrowed to the
+// resolution year for year groups.
+func statusGroupJQL(user jiraUser, group string) string {
+	match := yearStatusPattern.FindStringSubmatch(group)
+	if match == nil {
+		return userStatusJQL(user, group)
+	}
+	year, _ := strconv.Atoi(match[2])
+	return fmt.Sprintf(`%s AND resolutiondate >= "%d-01-01" AND resolutiondate < "%d-01-01"`, userStatusJQL(user, match[1]), year, year+1)
+}
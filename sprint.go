@@ -0,0 +1,121 @@
+// Current sprint
+//
+// /issues --sprint narrows the results to the active sprint by adding
+// `sprint in openSprints()` to the query. The Sprint custom field
+// (JIRA_FIELD_SPRINT, defaulting to Red Hat JIRA's field) is requested as well,
+// and the result's header names the sprint(s) the issues are in. The flag
+// combines with the status filters and --all.
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// defaultSprintField is the Sprint custom field in Red Hat JIRA.
+const defaultSprintField = "customfield_12310940"
+
+// openSprintsClause restricts a query to issues in an active sprint.
+const openSprintsClause = "sprint in openSprints()"
+
+// serverSprintPattern reads the name and state out of the string form JIRA Server
+// uses for sprints ("com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=1,state=ACTIVE,name=Sprint 42,...]").
+var serverSprintPattern = regexp.MustCompile(`state=([A-Z]+),name=([^,\]]*)`)
+
+// sprintField returns the configured Sprint field ID.
+func sprintField() string {
+	if field := os.Getenv("JIRA_FIELD_SPRINT"); field != "" {
+		return field
+	}
+	return defaultSprintField
+}
+
+// parseSprintFlag extracts --sprint from the command text. Returns whether it
+// was given and the text without it.
+func parseSprintFlag(text string) (bool, string) {
+	return takeSlashFlag(text, "--sprint")
+}
+
+// withSprintClause narrows jql to the active sprint when sprintOnly is set.
+func withSprintClause(jql string, sprintOnly bool) string {
+	if !sprintOnly {
+		return jql
+	}
+	return addJQLConditions(jql, openSprintsClause)
+}
+
+// addJQLConditions ANDs conditions onto jql, keeping its ORDER BY at the end.
+func addJQLConditions(jql string, conditions ...string) string {
+	if len(conditions) == 0 {
+		return jql
+	}
+	orderBy := ""
+	if i := strings.LastIndex(strings.ToUpper(jql), " ORDER BY "); i >= 0 {
+		jql, orderBy = jql[:i], jql[i:]
+	}
+	return "(" + jql + ") AND " + strings.Join(conditions, " AND ") + orderBy
+}
+
+// issueSprint returns the name of the issue's active sprint, or "" if it has none.
+// Cloud returns sprints as objects, Server as strings.
+func issueSprint(issue JiraIssue) string {
+	value := issue.customField(sprintField())
+	if value == nil {
+		return ""
+	}
+
+	var sprints []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(value, &sprints); err == nil {
+		for _, sprint := range sprints {
+			if strings.EqualFold(sprint.State, "active") {
+				return strings.TrimSpace(sprint.Name)
+			}
+		}
+		return ""
+	}
+
+	var serverSprints []string
+	if err := json.Unmarshal(value, &serverSprints); err == nil {
+		for _, sprint := range serverSprints {
+			if match := serverSprintPattern.FindStringSubmatch(sprint); match != nil && match[1] == "ACTIVE" {
+				return strings.TrimSpace(match[2])
+			}
+		}
+	}
+	return ""
+}
+
+// sprintNames lists the distinct active sprints of the grouped issues, sorted.
+func sprintNames(statusGroups map[string][]IssueItem) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, issues := range statusGroups {
+		for _, issue := range issues {
+			if issue.Sprint != "" && !seen[issue.Sprint] {
+				seen[issue.Sprint] = true
+				names = append(names, issue.Sprint)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sprintOverviewLine names the sprint(s) of a --sprint result for its header, or
+// returns "" without --sprint.
+func sprintOverviewLine(statusGroups map[string][]IssueItem, sprintOnly bool) string {
+	if !sprintOnly {
+		return ""
+	}
+	names := sprintNames(statusGroups)
+	if len(names) == 0 {
+		return "🏃 Current sprint only"
+	}
+	return "🏃 Current sprint: *" + escapeSlackMrkdwn(strings.Join(names, ", ")) + "*"
+}
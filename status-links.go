@@ -0,0 +1,121 @@
+// Status search links
+//
+// The slash command summary links each "• POST: 4" line to the matching JIRA
+// search, so the issues can be acted on in JIRA without building a filter by hand.
+// The search is scoped to the user by the JIRA accounts whose display name
+// contains the typed name (account IDs on Cloud, usernames on Server). JQL has no
+// contains operator for user fields, so the match is made on the fetched issues,
+// and only a name that matched no account falls back to the display name itself.
+//
+// The daily report's footer lists the per-status totals the same way, each
+// linking to the report's query narrowed to that status. SHOW_STATUS_TOTALS=false
+// leaves them out.
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// qaContactJQLField is the JQL name of the QA Contact field (customfield_12315948).
+const qaContactJQLField = "cf[12315948]"
+
+// jiraUser identifies the person a slash command is about.
+type jiraUser struct {
+	Name     string   // Name the issues matched on
+	Accounts []string // Account IDs (usernames on Server) of the users whose name contains Name, sorted
+}
+
+// collectUserAccounts adds the account ID (username on JIRA Server) of the
+// page's assignees and QA contacts whose display name contains name
+// (case-insensitive) to accounts.
+func collectUserAccounts(page JiraSearchResponse, name string, accounts map[string]bool) {
+	nameLower := strings.ToLower(name)
+	for _, issue := range page.Issues {
+		for _, user := range []*jiraUserRef{issue.Fields.Assignee, issue.Fields.QAContact} {
+			if user == nil || !strings.Contains(strings.ToLower(user.DisplayName), nameLower) {
+				continue
+			}
+			switch {
+			case user.AccountID != "":
+				accounts[user.AccountID] = true
+			case user.Name != "":
+				accounts[user.Name] = true
+			}
+		}
+	}
+}
+
+// sortedAccounts returns the accounts in a stable order, so links don't change between runs.
+func sortedAccounts(accounts map[string]bool) []string {
+	var sorted []string
+	for account := range accounts {
+		sorted = append(sorted, account)
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+// userStatusJQL returns the query for the user's issues in a status, within the
+// same window as the slash command's own query.
+func userStatusJQL(user jiraUser, status string) string {
+	values := []string{sanitizeJQLValue(user.Name)}
+	if len(user.Accounts) > 0 {
+		values = values[:0]
+		for _, account := range user.Accounts {
+			values = append(values, sanitizeJQLValue(account))
+		}
+	}
+	list := strings.Join(values, ", ")
+	return fmt.Sprintf("project = MTV AND updated >= -365d AND %s AND (assignee IN (%s) OR %s IN (%s))",
+		statusJQLClause(status), list, qaContactJQLField, list)
+}
+
+// statusTotalsEnabled reports whether the daily footer lists the per-status totals.
+func statusTotalsEnabled() bool {
+	return envBool("SHOW_STATUS_TOTALS", true)
+}
+
+// buildStatusTotalsBlocks creates the footer context block with the number of
+// issues in each status, each linking to the report's query narrowed to the
+// status. Returns nil when disabled or there are no issues.
+func buildStatusTotalsBlocks(jiraURL, jql string, personGroups []PersonStatusGroup) []map[string]interface{} {
+	if !statusTotalsEnabled() || jiraURL == "" {
+		return nil
+	}
+	if strings.TrimSpace(jql) == "" {
+		jql = "project = MTV"
+	}
+
+	var totals []string
+	for _, status := range orderedStatuses(personGroups) {
+		count := 0
+		for _, group := range personGroups {
+			count += len(group.StatusGroups[status])
+		}
+		link := jiraSearchURL(jiraURL, addJQLConditions(jql, statusJQLClause(status)))
+		totals = append(totals, fmt.Sprintf("<%s|*%s:*> %d", link, escapeSlackMrkdwn(status), count))
+	}
+	if len(totals) == 0 {
+		return nil
+	}
+
+	blocks := []map[string]interface{}{
+		{
+			"type": "context",
+			"elements": []map[string]string{
+				{"type": "mrkdwn", "text": "📊 " + strings.Join(totals, " · ")},
+			},
+		},
+	}
+	limitBlockText(blocks)
+	return blocks
+}
+
+// jiraSearchURL returns the JIRA issue navigator URL for a query. The query is
+// fully escaped, so the URL is safe inside a Slack <url|text> link.
+func jiraSearchURL(jiraURL, jql string) string {
+	return strings.TrimRight(jiraURL, "/") + "/issues/?jql=" + url.QueryEscape(jql)
+}
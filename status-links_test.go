@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// queryOfSearchURL returns the JQL of a JIRA search URL.
+func queryOfSearchURL(t *testing.T, link string) string {
+	t.Helper()
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf("parsing %q: %v", link, err)
+	}
+	return parsed.Query().Get("jql")
+}
+
+func TestJiraSearchURLEscaping(t *testing.T) {
+	tests := []struct {
+		name string
+		user jiraUser
+	}{
+		{name: "spaces", user: jiraUser{Name: "Jane Doe"}},
+		{name: "double quotes", user: jiraUser{Name: `Jane "JD" Doe`}},
+		{name: "single quote", user: jiraUser{Name: "Seán O'Brien"}},
+		{name: "Slack link syntax", user: jiraUser{Name: "Jane <QA> | Doe & co"}},
+		{name: "accounts", user: jiraUser{Name: "jane", Accounts: []string{"557058:abc", "557058:def"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jql := userStatusJQL(tt.user, "ON_QA")
+			link := jiraSearchURL("https://jira.example.com/", jql)
+
+			if !strings.HasPrefix(link, "https://jira.example.com/issues/?jql=") {
+				t.Errorf("link = %s", link)
+			}
+			if strings.ContainsAny(strings.TrimPrefix(link, "https://"), ` "'<>|&`) {
+				t.Errorf("link has characters that break a Slack <url|text> link: %s", link)
+			}
+			if got := queryOfSearchURL(t, link); got != jql {
+				t.Errorf("link decodes to %q, want %q", got, jql)
+			}
+		})
+	}
+}
+
+func TestUserStatusJQL(t *testing.T) {
+	tests := []struct {
+		name string
+		user jiraUser
+		want string
+	}{
+		{
+			name: "one account",
+			user: jiraUser{Name: "Jane Doe", Accounts: []string{"abc"}},
+			want: `(assignee IN ("abc") OR cf[12315948] IN ("abc"))`,
+		},
+		{
+			name: "partial name matching several accounts",
+			user: jiraUser{Name: "jane", Accounts: []string{"abc", "def"}},
+			want: `(assignee IN ("abc", "def") OR cf[12315948] IN ("abc", "def"))`,
+		},
+		{
+			name: "no account falls back to the name",
+			user: jiraUser{Name: `Jane "JD" Doe`},
+			want: `(assignee IN ("Jane \"JD\" Doe") OR cf[12315948] IN ("Jane \"JD\" Doe"))`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jql := userStatusJQL(tt.user, "POST")
+			if !strings.HasSuffix(jql, tt.want) {
+				t.Errorf("userStatusJQL = %s, want it to end with %s", jql, tt.want)
+			}
+			if !strings.Contains(jql, `status IN ("POST")`) {
+				t.Errorf("userStatusJQL = %s, want the status clause", jql)
+			}
+		})
+	}
+}
+
+func TestCollectUserAccounts(t *testing.T) {
+	page := JiraSearchResponse{Issues: []JiraIssue{
+		parseTestIssue(t, `{"key": "MTV-1", "fields": {"assignee": {"displayName": "Jane Doe", "accountId": "abc"}}}`),
+		parseTestIssue(t, `{"key": "MTV-2", "fields": {"assignee": {"displayName": "Bob"}, "customfield_12315948": {"displayName": "Jane Roe", "accountId": "def"}}}`),
+		parseTestIssue(t, `{"key": "MTV-3", "fields": {"assignee": {"displayName": "Janet Server", "name": "jserver"}}}`),
+		parseTestIssue(t, `{"key": "MTV-4", "fields": {"assignee": {"displayName": "Jane Doe", "accountId": "abc"}}}`),
+	}}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "jane", want: "[abc def jserver]"},
+		{name: "JANE DOE", want: "[abc]"},
+		{name: "bob", want: "[]"}, // No account ID or username
+		{name: "nobody", want: "[]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts := make(map[string]bool)
+			collectUserAccounts(page, tt.name, accounts)
+			if got := fmt.Sprint(sortedAccounts(accounts)); got != tt.want {
+				t.Errorf("accounts = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildStatusTotalsBlocks(t *testing.T) {
+	groups := []PersonStatusGroup{
+		{Person: "Ann", StatusGroups: map[string][]IssueItem{"POST": {{Key: "MTV-1"}, {Key: "MTV-2"}}, "ON_QA": {{Key: "MTV-3"}}}},
+		{Person: "Bob", StatusGroups: map[string][]IssueItem{"ON_QA": {{Key: "MTV-4"}}}},
+	}
+	jql := "project = MTV AND updated >= -365d ORDER BY assignee"
+
+	blocks := buildStatusTotalsBlocks("https://jira.example.com", jql, groups)
+	if len(blocks) != 1 {
+		t.Fatalf("got %d blocks, want 1", len(blocks))
+	}
+	text := blocks[0]["elements"].([]map[string]string)[0]["text"]
+
+	for status, want := range map[string]string{"POST": "2", "ON_QA": "2"} {
+		link := jiraSearchURL("https://jira.example.com", addJQLConditions(jql, statusJQLClause(status)))
+		if !strings.Contains(text, fmt.Sprintf("<%s|*%s:*> %s", link, escapeSlackMrkdwn(status), want)) {
+			t.Errorf("footer %q doesn't link %s with total %s", text, status, want)
+		}
+		if got := queryOfSearchURL(t, link); !strings.HasPrefix(got, "(project = MTV") || !strings.HasSuffix(got, " ORDER BY assignee") {
+			t.Errorf("%s link query = %s, want the report's query narrowed by status", status, got)
+		}
+	}
+	if strings.Index(text, "|*POST:*>") > strings.Index(text, "|*"+escapeSlackMrkdwn("ON_QA")) {
+		t.Errorf("statuses out of report order: %q", text)
+	}
+
+	t.Setenv("SHOW_STATUS_TOTALS", "false")
+	if blocks := buildStatusTotalsBlocks("https://jira.example.com", jql, groups); blocks != nil {
+		t.Errorf("disabled totals still built: %v", blocks)
+	}
+}
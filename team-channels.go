@@ -0,0 +1,174 @@
+// Per-team channels
+//
+// TEAM_CHANNEL_MAP routes each team's people (see team-grouping.go) to the team's
+// own channel as a separate thread, as a JSON object of team to channel ID:
+//
+//	TEAM_CHANNEL_MAP='{"Storage": "C0123STOR", "Network": "C0456NETW"}'
+//
+// Teams without an entry, including "Unmapped", go to the report channel. A channel
+// that fails doesn't stop the others; the run ends with a per-channel summary and
+// errPartialDelivery if any channel failed.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// activeTeamChannelMap holds TEAM_CHANNEL_MAP keyed by lowercased team, loaded at startup.
+var activeTeamChannelMap map[string]string
+
+// errPartialDelivery reports that the report reached some team channels but not all.
+var errPartialDelivery = errors.New("report not delivered to every team channel")
+
+// teamChannelReport is the share of the report posted to one channel.
+type teamChannelReport struct {
+	channel string
+	teams   []string
+	groups  []PersonStatusGroup
+}
+
+// loadTeamChannelMap parses TEAM_CHANNEL_MAP. An unset TEAM_CHANNEL_MAP yields an empty map.
+func loadTeamChannelMap() (map[string]string, error) {
+	channels := make(map[string]string)
+	value := strings.TrimSpace(os.Getenv("TEAM_CHANNEL_MAP"))
+	if value == "" {
+		return channels, nil
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return channels, fmt.Errorf("TEAM_CHANNEL_MAP is not a JSON object of team to channel: %w", err)
+	}
+	for team, channel := range raw {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			channels[strings.ToLower(strings.TrimSpace(team))] = channel
+		}
+	}
+	return channels, nil
+}
+
+// splitByTeamChannel sorts the groups by team and divides them among the team
+// channels, in the order the channels first appear.
+func splitByTeamChannel(groups []PersonStatusGroup, defaultChannel string) []teamChannelReport {
+	sortByTeam(groups)
+
+	var reports []teamChannelReport
+	index := make(map[string]int)
+	for _, group := range groups {
+		team := teamOf(group.Person)
+		channel, ok := activeTeamChannelMap[strings.ToLower(team)]
+		if !ok {
+			channel = defaultChannel
+		}
+
+		i, ok := index[channel]
+		if !ok {
+			i = len(reports)
+			index[channel] = i
+			reports = append(reports, teamChannelReport{channel: channel})
+		}
+		report := &reports[i]
+		if !containsString(report.teams, team) {
+			report.teams = append(report.teams, team)
+		}
+		report.groups = append(report.groups, group)
+	}
+	return reports
+}
+
+// sendTeamReports posts each team channel's share of the report as its own thread,
+// continuing past channels that fail.
+func sendTeamReports(mode reportMode, jiraURL, jql, slackBotToken, defaultChannel, date string, personGroups []PersonStatusGroup, stats *runStats) error {
+	reports := splitByTeamChannel(personGroups, defaultChannel)
+	logf("📤 Sending report to %d team channel(s) at %s...\n", len(reports), time.Now().Format("15:04:05"))
+
+	var summary []string
+	var manifestThread *reportThread
+	posted := make(map[string]manifestPerson)
+	var failures []failedChannel
+	failed, degraded := 0, false
+	for _, report := range reports {
+		logf("   %s: %s (%d people)\n", report.channel, strings.Join(report.teams, ", "), len(report.groups))
+
+		thread := newReportThread(slackBotToken, report.channel, stats)
+		header := buildHeaderBlocks(mode, date, "", report.groups, envRender)
+		var err error
+		if parentChannel, parentTS := parentThread(); parentTS != "" && parentChannel == report.channel {
+			err = thread.attach(parentTS, header)
+		} else {
+			err = thread.start(header)
+		}
+		var channelPosted map[string]manifestPerson
+		if err == nil {
+			channelPosted, err = sendDailyReportThreaded(thread, jiraURL, jql, report.groups, mode, stats, envRender)
+			for person, entry := range channelPosted {
+				posted[person] = entry
+			}
+		}
+		activeRunResult.recordDelivery(report.groups, channelPosted, err)
+
+		if err != nil {
+			failed++
+			failures = append(failures, failedThread(thread, report.groups, channelPosted))
+			logf("   ❌ %s failed: %v\n", report.channel, err)
+			summary = append(summary, fmt.Sprintf("   ❌ %s (%s): %v", report.channel, strings.Join(report.teams, ", "), err))
+			continue
+		}
+		if thread.degraded {
+			degraded = true
+			summary = append(summary, fmt.Sprintf("   ⚠️  %s (%s): %d issues, in fallback channel %s", report.channel, strings.Join(report.teams, ", "), countGroupIssues(report.groups), thread.channel))
+		} else {
+			summary = append(summary, fmt.Sprintf("   ✅ %s (%s): %d issues", report.channel, strings.Join(report.teams, ", "), countGroupIssues(report.groups)))
+		}
+		if manifestThread == nil || report.channel == defaultChannel {
+			manifestThread = thread
+		}
+		stats.sleep(500 * time.Millisecond)
+	}
+
+	logln("\n📊 Team channels:")
+	for _, line := range summary {
+		logln(line)
+	}
+
+	saveFailedDelivery(mode, jql, date, failures, personGroups)
+	if manifestThread != nil {
+		activeRunResult.recordThread(manifestThread.channel, manifestThread.threadTS)
+	}
+
+	if failed == len(reports) {
+		return fmt.Errorf("failed to send the report to any of %d team channel(s)", len(reports))
+	}
+
+	// The window only moves when nothing was lost; the manifest keeps what was posted
+	if asOfTime.IsZero() && !dryRun {
+		if failed == 0 {
+			if err := recordSuccessfulRun(mode, stats.Started, countGroupIssues(personGroups)); err != nil {
+				logf("⚠️  Failed to record successful run: %v\n", err)
+			}
+		}
+		manifest := reportManifest{
+			Mode:     mode,
+			PostedAt: time.Now().UTC(),
+			Channel:  manifestThread.channel,
+			ThreadTS: manifestThread.threadTS,
+			People:   posted,
+		}
+		if err := saveReportManifest(manifest); err != nil {
+			logf("⚠️  Failed to write report manifest: %v\n", err)
+		}
+	}
+
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%w: %d of %d failed", errPartialDelivery, failed, len(reports))
+	case degraded:
+		return errDegradedDelivery
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+// Team grouping
+//
+// With GROUP_BY=team the daily thread is organized by sub-team: people are
+// ordered by team (alphabetically, "Unmapped" last), each team starts a new reply
+// with a team header, and the person sections with their status sub-groups follow
+// unchanged. TEAM_MAP maps JIRA display names to teams as a JSON object, e.g.
+//
+//	TEAM_MAP='{"John Doe": "Storage", "Jane Roe": "Network"}'
+//
+// Names are matched case-insensitively. People not in the map go under "Unmapped".
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// unmappedTeam collects the people TEAM_MAP doesn't mention.
+const unmappedTeam = "Unmapped"
+
+// activeTeamMap holds TEAM_MAP keyed by lowercased display name, loaded at startup.
+var activeTeamMap map[string]string
+
+// loadTeamMap parses TEAM_MAP. An unset TEAM_MAP yields an empty map.
+func loadTeamMap() (map[string]string, error) {
+	teams := make(map[string]string)
+	value := strings.TrimSpace(os.Getenv("TEAM_MAP"))
+	if value == "" {
+		return teams, nil
+	}
+
+	var raw map[string]string
+	if err := json.Unmarshal([]byte(value), &raw); err != nil {
+		return teams, fmt.Errorf("TEAM_MAP is not a JSON object of name to team: %w", err)
+	}
+	for name, team := range raw {
+		if team = strings.TrimSpace(team); team != "" {
+			teams[strings.ToLower(strings.TrimSpace(name))] = team
+		}
+	}
+	return teams, nil
+}
+
+// groupByTeam reports whether GROUP_BY selects team grouping.
+func groupByTeam() bool {
+	switch value := groupBySetting(); value {
+	case "team":
+		return true
+	case "", "person", "workstream":
+		return false
+	default:
+		logf("⚠️  Unknown GROUP_BY %q, grouping by person\n", value)
+		return false
+	}
+}
+
+// teamOf returns the person's team, or unmappedTeam.
+func teamOf(person string) string {
+	if team, ok := activeTeamMap[strings.ToLower(person)]; ok {
+		return team
+	}
+	return unmappedTeam
+}
+
+// sortByTeam orders the groups by team, keeping the existing order within a team.
+func sortByTeam(groups []PersonStatusGroup) {
+	rank := func(team string) string {
+		// Sorts after every team name
+		if team == unmappedTeam {
+			return "\xff"
+		}
+		return strings.ToLower(team)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return rank(teamOf(groups[i].Person)) < rank(teamOf(groups[j].Person))
+	})
+}
+
+// buildTeamHeaderBlocks renders the header opening a team's part of the thread.
+func buildTeamHeaderBlocks(team string, groups []PersonStatusGroup) []map[string]interface{} {
+	people, issues := 0, 0
+	for _, group := range groups {
+		if teamOf(group.Person) == team {
+			people++
+			issues += group.TotalIssues
+		}
+	}
+	return []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]string{
+				"type": "plain_text",
+				"text": fmt.Sprintf("👥 %s — %d people, %d issue(s)", team, people, issues),
+			},
+		},
+	}
+}
@@ -0,0 +1,131 @@
+// Report trigger endpoint
+//
+// POST /report/run?mode=qa|inprogress runs the daily report on demand, e.g. from
+// a Slack workflow or a scheduler that can't exec the binary. It goes through the
+// same checks as a scheduled run first (see reportRunGate): on days without a
+// report (WEEKDAYS_ONLY, SKIP_DATES) it answers 200 with the reason and posts
+// nothing, and a failed channel preflight or field check is a 500. Otherwise the
+// run happens in the background and the endpoint answers 202 Accepted. 409
+// Conflict means another triggered run is still in progress.
+//
+// Callers authenticate with either:
+//
+//	REPORT_TRIGGER_TOKEN - shared token sent as "Authorization: Bearer <token>"
+//	                       or in the X-Report-Token header
+//	SLACK_SIGNING_SECRET - standard Slack request signature (for Slack callers)
+//
+// Requests that pass neither check get 401. With neither configured the trigger
+// endpoints reject everything.
+package main
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// triggerRunning guards against overlapping triggered runs.
+var triggerRunning sync.Mutex
+
+// requireTriggerAuth wraps a handler so it only runs for callers presenting the
+// token or, when signingSecret is set, a valid Slack signature. It also guards
+// the /api/report endpoint (with REPORT_API_TOKEN and no Slack signatures).
+func requireTriggerAuth(triggerToken, signingSecret string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if triggerToken != "" && validTriggerToken(triggerToken, r) {
+			next(w, r)
+			return
+		}
+
+		reason := "no valid token or signature"
+		if signingSecret != "" && r.Header.Get("X-Slack-Signature") != "" {
+			err := checkSignedRequest(signingSecret, r)
+			if err == nil {
+				next(w, r)
+				return
+			}
+			reason = err.Error()
+		}
+
+		logf("⚠️  Rejected unauthorized request to %s: %s\n", r.URL.Path, reason)
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+	}
+}
+
+// validTriggerToken reports whether the request carries the shared trigger token.
+func validTriggerToken(triggerToken string, r *http.Request) bool {
+	provided := r.Header.Get("X-Report-Token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		provided = strings.TrimPrefix(auth, "Bearer ")
+	}
+	if provided == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(provided), []byte(triggerToken)) == 1
+}
+
+// handleReportRun starts a report run in the background
+func handleReportRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	modeValue := r.URL.Query().Get("mode")
+	if modeValue == "" {
+		modeValue = string(modeQA)
+	}
+	mode, err := parseReportMode(modeValue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !triggerRunning.TryLock() {
+		http.Error(w, "A report run is already in progress", http.StatusConflict)
+		return
+	}
+
+	logf("📨 Triggered %s report run\n", mode)
+	skip, err := reportRunGate(mode)
+	switch {
+	case errors.Is(err, errMissingCredentials):
+		triggerRunning.Unlock()
+		http.Error(w, "Configuration error: JIRA_URL, JIRA_TOKEN, SLACK_BOT_TOKEN or SLACK_CHANNEL not set", http.StatusInternalServerError)
+		return
+	case err != nil:
+		triggerRunning.Unlock()
+		logf("❌ Triggered %s report not started: %v\n", mode, err)
+		http.Error(w, "Report not started: "+redactSecrets(err.Error()), http.StatusInternalServerError)
+		return
+	case skip != "":
+		triggerRunning.Unlock()
+		logf("⏭️  Skipping the triggered %s report: %s\n", mode, skip)
+		fmt.Fprintf(w, "Skipped %s report: %s\n", mode, skip)
+		return
+	}
+
+	go func() {
+		defer triggerRunning.Unlock()
+
+		stats := newRunStats()
+		if err := sendReport(mode, stats); err != nil {
+			logf("❌ Triggered %s report failed: %v\n", mode, err)
+			return
+		}
+		if err := notifyWatchers(os.Getenv("JIRA_URL"), os.Getenv("JIRA_TOKEN"), os.Getenv("SLACK_BOT_TOKEN")); err != nil {
+			logf("⚠️  Failed to notify watchers: %v\n", err)
+		}
+		stats.finish()
+		if verboseLogging {
+			stats.print()
+		}
+	}()
+
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "Started %s report\n", mode)
+}
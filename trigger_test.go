@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHandleReportRunGating(t *testing.T) {
+	setCredentials := func(t *testing.T) {
+		t.Setenv("JIRA_URL", "https://jira.example.com")
+		t.Setenv("JIRA_TOKEN", "jira-token")
+		t.Setenv("SLACK_BOT_TOKEN", "xoxb-test")
+		t.Setenv("SLACK_CHANNEL", "C1")
+	}
+
+	tests := []struct {
+		name       string
+		setup      func(t *testing.T)
+		locked     bool
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name: "skip date is reported, not run",
+			setup: func(t *testing.T) {
+				setCredentials(t)
+				t.Setenv("REPORT_TIMEZONE", "UTC")
+				t.Setenv("SKIP_DATES", time.Now().UTC().Format("2006-01-02"))
+			},
+			wantStatus: http.StatusOK,
+			wantBody:   "Skipped qa report",
+		},
+		{
+			name:       "missing credentials",
+			setup:      func(t *testing.T) { t.Setenv("JIRA_URL", "") },
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   "Configuration error",
+		},
+		{
+			name:       "a run in progress",
+			setup:      setCredentials,
+			locked:     true,
+			wantStatus: http.StatusConflict,
+			wantBody:   "already in progress",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup(t)
+			if tt.locked {
+				triggerRunning.Lock()
+				defer triggerRunning.Unlock()
+			}
+
+			w := httptest.NewRecorder()
+			handleReportRun(w, httptest.NewRequest(http.MethodPost, "/report/run?mode=qa", nil))
+			if w.Code != tt.wantStatus || !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("got %d %q, want %d containing %q", w.Code, w.Body.String(), tt.wantStatus, tt.wantBody)
+			}
+			if !tt.locked && !triggerRunning.TryLock() {
+				t.Fatal("the handler didn't release the run lock")
+			} else if !tt.locked {
+				triggerRunning.Unlock()
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Slack name normalization
+//
+// Slack display names often carry decorations ("John Doe :palm_tree: (PTO)",
+// pronouns in parentheses, status emoji) that never match a JIRA display name.
+// Names are cleaned up before matching, and if the cleaned name matches nothing,
+// the first and last name alone are tried.
+package main
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// nameDecorationPatterns match decorations removed from Slack names.
+var nameDecorationPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`:[a-z0-9_+\-]+:`), // Emoji shortcodes like :palm_tree:
+	regexp.MustCompile(`\([^)]*\)`),       // (PTO), (she/her)
+	regexp.MustCompile(`\[[^\]]*\]`),      // [OOO]
+	regexp.MustCompile(`\{[^}]*\}`),
+}
+
+// normalizeSlackName strips emoji, bracketed suffixes and extra whitespace from a name.
+func normalizeSlackName(name string) string {
+	for _, pattern := range nameDecorationPatterns {
+		name = pattern.ReplaceAllString(name, " ")
+	}
+	name = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r) || r == '‍' || r == '️' {
+			return ' '
+		}
+		return r
+	}, name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// userNameVariants returns the names to match for username, in order of
+// preference: the normalized name, then just its first and last tokens.
+func userNameVariants(username string) []string {
+	normalized := normalizeSlackName(username)
+	if normalized == "" {
+		normalized = strings.TrimSpace(username)
+	}
+	variants := []string{normalized}
+
+	if tokens := strings.Fields(normalized); len(tokens) > 2 {
+		variants = append(variants, tokens[0]+" "+tokens[len(tokens)-1])
+	}
+	return variants
+}
@@ -0,0 +1,112 @@
+// JQL validation
+//
+// -validate-jql checks the report's JQL against JIRA without paginating through
+// the results. The /rest/api/3/search/jql endpoint no longer returns a total, so
+// the check uses /rest/api/3/search/approximate-count, which validates the query
+// and returns the match count in a single request.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// JiraErrorResponse represents the error body JIRA returns for invalid requests (e.g. bad JQL).
+type JiraErrorResponse struct {
+	ErrorMessages   []string          `json:"errorMessages"`
+	Errors          map[string]string `json:"errors"`
+	WarningMessages []string          `json:"warningMessages"`
+}
+
+// jqlRejectedError is a non-200 answer from JIRA to a JQL check or search. A 400
+// means JIRA rejected the query itself; other codes may be temporary.
+type jqlRejectedError struct {
+	StatusCode int
+	message    string
+}
+
+func (e *jqlRejectedError) Error() string {
+	return e.message
+}
+
+// runValidateJQL validates the JQL of the given report mode, prints the result and exits.
+func runValidateJQL(mode reportMode) {
+	jiraURL := os.Getenv("JIRA_URL")
+	jiraToken := os.Getenv("JIRA_TOKEN")
+	if jiraURL == "" || jiraToken == "" {
+		logln("❌ Missing required credentials")
+		logln("Please set environment variables: JIRA_URL, JIRA_TOKEN")
+		os.Exit(1)
+	}
+
+	jql := reportQuery(mode, jiraURL, jiraToken)
+	logf("🔎 Validating JQL: %s\n", jql)
+
+	count, err := validateJQL(jiraURL, jiraToken, jql)
+	if err != nil {
+		logf("❌ JQL is invalid: %v\n", err)
+		os.Exit(1)
+	}
+
+	logf("✅ JQL is valid — %d matching issue(s)\n", count)
+
+	// Names for the fields a sample issue may come back without
+	if _, err := fetchJiraFieldNames(jiraURL, jiraToken); err != nil {
+		logf("⚠️  Couldn't load the JIRA field list: %v\n", err)
+	}
+	reportSampleFields(jiraURL, jiraToken, jql, reportFetchOptions(mode))
+}
+
+// validateJQL asks JIRA for the (approximate) number of issues matching jql.
+// Returns JIRA's validation messages as a *jqlRejectedError when the query is rejected.
+func validateJQL(jiraURL, jiraToken, jql string) (int, error) {
+	body, err := json.Marshal(map[string]string{"jql": jql})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequest("POST", fmt.Sprintf("%s/rest/api/3/search/approximate-count", jiraURL), bytes.NewBuffer(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	setJiraAuth(req, jiraToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		var jiraErr JiraErrorResponse
+		if json.Unmarshal(responseBody, &jiraErr) == nil && len(jiraErr.ErrorMessages)+len(jiraErr.Errors) > 0 {
+			messages := append([]string{}, jiraErr.ErrorMessages...)
+			for field, msg := range jiraErr.Errors {
+				messages = append(messages, fmt.Sprintf("%s: %s", field, msg))
+			}
+			return 0, &jqlRejectedError{StatusCode: resp.StatusCode, message: strings.Join(messages, "; ")}
+		}
+		return 0, &jqlRejectedError{StatusCode: resp.StatusCode, message: fmt.Sprintf("JIRA API returned %d: %s", resp.StatusCode, redactSecrets(string(responseBody)))}
+	}
+
+	var result struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal(responseBody, &result); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return result.Count, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// approximateCountStub answers approximate-count requests with status and body,
+// and counts the requests.
+func approximateCountStub(t *testing.T, status int, body string) (*httptest.Server, *int) {
+	t.Helper()
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/search/approximate-count" {
+			http.NotFound(w, r)
+			return
+		}
+		requests++
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestValidateJQL(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantCount  int
+		wantErr    string
+		wantStatus int
+	}{
+		{name: "valid query", status: 200, body: `{"count": 42}`, wantCount: 42},
+		{
+			name:       "rejected query",
+			status:     400,
+			body:       `{"errorMessages": ["Field 'foo' does not exist."], "errors": {}}`,
+			wantErr:    "Field 'foo' does not exist.",
+			wantStatus: 400,
+		},
+		{
+			name:       "field errors",
+			status:     400,
+			body:       `{"errorMessages": [], "errors": {"jql": "bad"}}`,
+			wantErr:    "jql: bad",
+			wantStatus: 400,
+		},
+		{name: "server error", status: 503, body: "unavailable", wantErr: "JIRA API returned 503", wantStatus: 503},
+		{name: "malformed response", status: 200, body: "not json", wantErr: "failed to unmarshal response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := approximateCountStub(t, tt.status, tt.body)
+			count, err := validateJQL(server.URL, "token", "project = MTV")
+			if tt.wantErr == "" {
+				if err != nil || count != tt.wantCount {
+					t.Fatalf("validateJQL = %d, %v, want %d", count, err, tt.wantCount)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("validateJQL error = %v, want %q", err, tt.wantErr)
+			}
+			var rejected *jqlRejectedError
+			if errors.As(err, &rejected) != (tt.wantStatus != 0) || (rejected != nil && rejected.StatusCode != tt.wantStatus) {
+				t.Errorf("validateJQL error = %#v, want status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidateJQLNetworkFailure(t *testing.T) {
+	server, _ := approximateCountStub(t, 200, `{"count": 1}`)
+	server.Close()
+
+	_, err := validateJQL(server.URL, "token", "project = MTV")
+	var rejected *jqlRejectedError
+	if err == nil || errors.As(err, &rejected) {
+		t.Fatalf("validateJQL error = %v, want a request failure", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Watchers
+//
+// With SHOW_WATCHERS=true each issue in the daily report shows how many people
+// watch it ("👀 3"), to spot high-attention items. SORT_BY=watchers moves the most
+// watched issues to the top of each status.
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// watchersEnabled reports whether the watches field is needed for this run.
+func watchersEnabled() bool {
+	return envBool("SHOW_WATCHERS", false) || sortByWatchersEnabled()
+}
+
+// sortByWatchersEnabled reports whether SORT_BY=watchers is set.
+func sortByWatchersEnabled() bool {
+	return strings.EqualFold(strings.TrimSpace(os.Getenv("SORT_BY")), "watchers")
+}
+
+// issueWatchers returns the watcher count, or 0 if the watches field wasn't fetched.
+func issueWatchers(issue JiraIssue) int {
+	if issue.Fields.Watches == nil {
+		return 0
+	}
+	return issue.Fields.Watches.WatchCount
+}
+
+// watchersSuffix renders the watcher count shown after an issue's status, or "" if disabled.
+func watchersSuffix(issue IssueItem) string {
+	if !envBool("SHOW_WATCHERS", false) {
+		return ""
+	}
+	return fmt.Sprintf("  |  👀 %d", issue.Watchers)
+}
+
+// sortByWatchers orders issues by watcher count, most watched first.
+func sortByWatchers(issues []IssueItem) {
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Watchers > issues[j].Watchers
+	})
+}
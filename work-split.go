@@ -0,0 +1,55 @@
+// Active vs waiting split
+//
+// With SHOW_WORK_SPLIT=true each person header shows how many of their issues are
+// actively being worked on and how many wait on someone else ("Active: 3,
+// Waiting: 5"). The buckets are ACTIVE_STATUSES and WAITING_STATUSES
+// (comma-separated), matched after status normalization (see STATUS_ALIASES).
+// Statuses in neither bucket, e.g. Open, are left out of the split.
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var (
+	// defaultActiveStatuses are the statuses in which someone works on the issue.
+	defaultActiveStatuses = []string{"In Progress", "ASSIGNED"}
+
+	// defaultWaitingStatuses are the statuses in which the issue waits on review, a build or QA.
+	defaultWaitingStatuses = []string{"POST", "MODIFIED", "ON_QA"}
+)
+
+// workBucketStatuses returns the canonical, lowercased statuses of a bucket.
+func workBucketStatuses(name string, defaults []string) map[string]bool {
+	statuses := envList(name)
+	if len(statuses) == 0 {
+		statuses = defaults
+	}
+	set := make(map[string]bool, len(statuses))
+	for _, status := range statuses {
+		set[strings.ToLower(canonicalStatus(status))] = true
+	}
+	return set
+}
+
+// workSplitSuffix returns the person header's Active/Waiting counts, or "" unless
+// SHOW_WORK_SPLIT=true.
+func workSplitSuffix(group PersonStatusGroup) string {
+	if !envBool("SHOW_WORK_SPLIT", false) {
+		return ""
+	}
+	active := workBucketStatuses("ACTIVE_STATUSES", defaultActiveStatuses)
+	waiting := workBucketStatuses("WAITING_STATUSES", defaultWaitingStatuses)
+
+	var activeCount, waitingCount int
+	for status, issues := range group.StatusGroups {
+		switch key := strings.ToLower(status); {
+		case active[key]:
+			activeCount += len(issues)
+		case waiting[key]:
+			waitingCount += len(issues)
+		}
+	}
+	return fmt.Sprintf("  ·  Active: %d, Waiting: %d", activeCount, waitingCount)
+}
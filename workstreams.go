@@ -0,0 +1,185 @@
+// Workstream grouping
+//
+// With GROUP_BY=workstream (or -group-by=workstream) the daily thread is organized
+// by workstream before people: each workstream starts a new reply with its header,
+// followed by the sections of its people holding only that workstream's issues.
+// WORKSTREAMS defines the workstreams by label, as a JSON object of name to label
+// patterns (same syntax as the label exclusions, case-sensitive):
+//
+//	WORKSTREAMS='{"Networking": ["mtv-networking"], "Storage": ["mtv-storage", "mtv-csi-*"], "UX": "mtv-ux"}'
+//
+// An issue belongs to the first workstream, in the order written, with a
+// matching label. Issues matching several are shown once, there, with a note
+// naming the others. Issues matching none go under "Unlabelled", last. A person
+// with issues in several workstreams has a section in each.
+//
+// Updating the thread in place isn't supported with workstream grouping, which
+// always posts a new thread.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// unlabelledWorkstream collects the issues no workstream claims.
+const unlabelledWorkstream = "Unlabelled"
+
+// workstream is one WORKSTREAMS entry.
+type workstream struct {
+	name     string
+	patterns []exclusionPattern
+}
+
+// activeWorkstreams holds WORKSTREAMS in the order written, loaded at startup.
+var activeWorkstreams []workstream
+
+// groupByOverride is the -group-by flag; empty leaves GROUP_BY in charge.
+var groupByOverride string
+
+// groupBySetting returns -group-by, or else GROUP_BY, lowercased.
+func groupBySetting() string {
+	if groupByOverride != "" {
+		return strings.ToLower(strings.TrimSpace(groupByOverride))
+	}
+	return strings.ToLower(strings.TrimSpace(os.Getenv("GROUP_BY")))
+}
+
+// groupByWorkstream reports whether the grouping selects workstreams.
+func groupByWorkstream() bool {
+	return groupBySetting() == "workstream"
+}
+
+// loadWorkstreams parses WORKSTREAMS, keeping the order of its entries. Each
+// entry's value is a list of label patterns or a single one. An unset
+// WORKSTREAMS yields no workstreams.
+func loadWorkstreams() ([]workstream, error) {
+	value := strings.TrimSpace(os.Getenv("WORKSTREAMS"))
+	if value == "" {
+		return nil, nil
+	}
+
+	// Decoded token by token, since a map would lose the order
+	dec := json.NewDecoder(strings.NewReader(value))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("WORKSTREAMS is not a JSON object of workstream to label patterns")
+	}
+	var workstreams []workstream
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("WORKSTREAMS: %w", err)
+		}
+		name := strings.TrimSpace(tok.(string))
+
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("WORKSTREAMS: %w", err)
+		}
+		var entries []string
+		if err := json.Unmarshal(raw, &entries); err != nil {
+			var single string
+			if err := json.Unmarshal(raw, &single); err != nil {
+				return nil, fmt.Errorf("WORKSTREAMS: %q must map to a label pattern or a list of them", name)
+			}
+			entries = []string{single}
+		}
+
+		patterns, err := compilePatterns(entries)
+		if err != nil {
+			return nil, fmt.Errorf("WORKSTREAMS: %s: %w", name, err)
+		}
+		if name == "" || len(patterns) == 0 {
+			return nil, fmt.Errorf("WORKSTREAMS: every workstream needs a name and at least one label pattern")
+		}
+		workstreams = append(workstreams, workstream{name: name, patterns: patterns})
+	}
+	return workstreams, nil
+}
+
+// issueWorkstreams returns the workstreams whose patterns match any of the
+// labels, in WORKSTREAMS order.
+func issueWorkstreams(labels []string) []string {
+	var names []string
+	for _, ws := range activeWorkstreams {
+		for _, label := range labels {
+			if matchesAny(ws.patterns, label) {
+				names = append(names, ws.name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// workstreamOf returns the workstream an issue is shown in.
+func workstreamOf(issue IssueItem) string {
+	if len(issue.Workstreams) == 0 {
+		return unlabelledWorkstream
+	}
+	return issue.Workstreams[0]
+}
+
+// splitByWorkstream regroups the person groups by workstream: for each
+// workstream in WORKSTREAMS order, then Unlabelled, the people with issues in it
+// (in their existing order) holding only those issues.
+func splitByWorkstream(groups []PersonStatusGroup) []PersonStatusGroup {
+	names := make([]string, 0, len(activeWorkstreams)+1)
+	for _, ws := range activeWorkstreams {
+		names = append(names, ws.name)
+	}
+	names = append(names, unlabelledWorkstream)
+
+	var split []PersonStatusGroup
+	for _, name := range names {
+		for _, group := range groups {
+			part := group
+			part.Workstream = name
+			part.StatusGroups = make(map[string][]IssueItem)
+			part.TotalIssues = 0
+			for status, issues := range group.StatusGroups {
+				for _, issue := range issues {
+					if workstreamOf(issue) == name {
+						part.StatusGroups[status] = append(part.StatusGroups[status], issue)
+						part.TotalIssues++
+					}
+				}
+			}
+			if part.TotalIssues > 0 {
+				split = append(split, part)
+			}
+		}
+	}
+	return split
+}
+
+// buildWorkstreamHeaderBlocks renders the header opening a workstream's part of the thread.
+func buildWorkstreamHeaderBlocks(name string, groups []PersonStatusGroup) []map[string]interface{} {
+	people, issues := 0, 0
+	for _, group := range groups {
+		if group.Workstream == name {
+			people++
+			issues += group.TotalIssues
+		}
+	}
+	return []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]string{
+				"type": "plain_text",
+				"text": fmt.Sprintf("🧵 %s — %d people, %d issue(s)", name, people, issues),
+			},
+		},
+	}
+}
+
+// workstreamNoteSuffix names the other workstreams an issue also matches, or
+// returns "" outside workstream grouping or when it matches at most one.
+func workstreamNoteSuffix(issue IssueItem) string {
+	if !groupByWorkstream() || len(issue.Workstreams) < 2 {
+		return ""
+	}
+	return fmt.Sprintf("  |  _also %s_", escapeSlackMrkdwn(strings.Join(issue.Workstreams[1:], ", ")))
+}